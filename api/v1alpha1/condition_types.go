@@ -40,4 +40,26 @@ const (
 	WorkspaceConditionTypeSucceeded ConditionType = ConditionType("WorkspaceSucceeded")
 
 	RAGEngineConditionTypeSucceeded ConditionType = ConditionType("RAGEngineSucceeded")
+
+	// WorkspaceConditionTypeIdle is the Workspace state when the inference workload has been scaled to zero
+	// because no request was received within the idle timeout.
+	WorkspaceConditionTypeIdle ConditionType = ConditionType("Idle")
+
+	// WorkspaceConditionTypeWaking is the Workspace state when a request has been received by an idle workspace
+	// and the inference workload is being brought back.
+	WorkspaceConditionTypeWaking ConditionType = ConditionType("Waking")
+
+	// WorkspaceConditionTypeSuspended is the Workspace state when the workload has been torn down and the nodes
+	// have been released because the workspace is suspended.
+	WorkspaceConditionTypeSuspended ConditionType = ConditionType("Suspended")
+
+	// WorkspaceConditionTypeRolledBack reports the result of the last rollback to a previous revision.
+	WorkspaceConditionTypeRolledBack ConditionType = ConditionType("RolledBack")
+
+	// WorkspaceConditionTypeInstanceTypeSelected reports the instance type selected by the controller and the reason
+	// it was selected, including the fallback to the next instance type when one is unavailable.
+	WorkspaceConditionTypeInstanceTypeSelected ConditionType = ConditionType("InstanceTypeSelected")
+
+	// WorkspaceConditionTypeNodeReplaced reports the last node which was replaced because it was deleted or NotReady.
+	WorkspaceConditionTypeNodeReplaced ConditionType = ConditionType("NodeReplaced")
 )
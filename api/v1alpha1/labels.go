@@ -31,16 +31,36 @@ const (
 	// LabelRAGEngineNamespace is the label for ragengine namespace.
 	LabelRAGEngineNamespace = KAITOPrefix + "ragenginenamespace"
 
+	// LabelWorkspaceController is the label of the KAITO workspace controller pods.
+	LabelWorkspaceController = KAITOPrefix + "workspace-controller"
+
+	// LabelSharedNodePool is the label for the shared node pool of the nodes which are shared by workspaces.
+	LabelSharedNodePool = KAITOPrefix + "shared-node-pool"
+
 	// WorkspaceRevisionAnnotation is the Annotations for revision number
 	WorkspaceRevisionAnnotation = "workspace.kaito.io/revision"
 
+	// WorkspaceTemplateHashAnnotation is the Annotations for the hash of the pod template rendered by the controller
+	WorkspaceTemplateHashAnnotation = "workspace.kaito.io/template-hash"
+
 	// RAGEngineRevisionAnnotation is the Annotations for revision number
 	RAGEngineRevisionAnnotation = "ragengine.kaito.io/revision"
 
 	// AnnotationWorkspaceRuntime is the annotation for runtime selection.
 	AnnotationWorkspaceRuntime = KAITOPrefix + "runtime"
+
+	// AnnotationWorkspaceSuspend suspends the workspace workload when set to "true".
+	AnnotationWorkspaceSuspend = KAITOPrefix + "suspend"
+
+	// AnnotationWorkspaceRollbackToRevision requests restoring the workspace spec from the given revision number.
+	AnnotationWorkspaceRollbackToRevision = KAITOPrefix + "rollback-to-revision"
 )
 
+// IsWorkspaceSuspended returns true if the workspace workload is suspended.
+func IsWorkspaceSuspended(ws *Workspace) bool {
+	return ws.Annotations[AnnotationWorkspaceSuspend] == "true"
+}
+
 // GetWorkspaceRuntimeName returns the runtime name of the workspace.
 func GetWorkspaceRuntimeName(ws *Workspace) model.RuntimeName {
 	if ws == nil {
@@ -9,4 +9,7 @@ import (
 
 // SetDefaults for the RAG Engine
 func (w *RAGEngine) SetDefaults(_ context.Context) {
+	if w.Spec != nil && w.Spec.Compute != nil && w.Spec.Compute.InstanceType == "" {
+		w.Spec.Compute.InstanceType = DefaultInstanceType
+	}
 }
@@ -9,10 +9,8 @@ import (
 	"net/url"
 	"os"
 	"regexp"
-	"strings"
 
 	"github.com/kaito-project/kaito/pkg/utils"
-	"github.com/kaito-project/kaito/pkg/utils/consts"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
@@ -36,7 +34,7 @@ func (w *RAGEngine) Validate(ctx context.Context) (errs *apis.FieldError) {
 		old := base.(*RAGEngine)
 		errs = errs.Also(
 			w.validateCreate().ViaField("spec"),
-			w.Spec.Compute.validateUpdate(old.Spec.Compute).ViaField("resource"),
+			w.Spec.Compute.validateUpdate(old.Spec.Compute, false).ViaField("resource"),
 		)
 	}
 	return errs
@@ -70,6 +68,21 @@ func (w *RAGEngine) validateCreate() (errs *apis.FieldError) {
 
 func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
 	instanceType := string(r.InstanceType)
+	if instanceType == "" {
+		return errs.Also(apis.ErrMissingField("instanceType"))
+	}
+	if len(r.FallbackInstanceTypes) > 0 {
+		errs = errs.Also(apis.ErrGeneric("RAGEngine does not support fallback instance types", "fallbackInstanceTypes"))
+	}
+	if r.SharedNodePool != "" {
+		errs = errs.Also(apis.ErrGeneric("RAGEngine does not support shared node pools", "sharedNodePool"))
+	}
+	if r.FractionalGPU != nil {
+		errs = errs.Also(apis.ErrGeneric("RAGEngine does not support fractional GPUs", "fractionalGPU"))
+	}
+	if r.CapacityType != "" {
+		errs = errs.Also(apis.ErrGeneric("RAGEngine does not support capacity types", "capacityType"))
+	}
 
 	skuHandler, err := utils.GetSKUHandler()
 	if err != nil {
@@ -79,15 +92,14 @@ func (r *ResourceSpec) validateRAGCreate() (errs *apis.FieldError) {
 	gpuConfigs := skuHandler.GetGPUConfigs()
 
 	if _, exists := gpuConfigs[instanceType]; !exists {
-		provider := os.Getenv("CLOUD_PROVIDER")
-		// Check for other instance types pattern matches if cloud provider is Azure
-		if provider != consts.AzureCloudName || (!strings.HasPrefix(instanceType, N_SERIES_PREFIX) && !strings.HasPrefix(instanceType, D_SERIES_PREFIX)) {
+		// Check for other instance types pattern matches of the cloud provider
+		if !matchesInstanceTypePattern(os.Getenv("CLOUD_PROVIDER"), instanceType) {
 			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), "instanceType"))
 		}
 	}
 
 	// Validate labelSelector
-	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
+	if _, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
 		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
 	}
 
@@ -95,6 +95,23 @@ func TestRAGEngineValidateCreate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Capacity type specified",
+			ragEngine: &RAGEngine{
+				Spec: &RAGEngineSpec{
+					Compute: &ResourceSpec{
+						InstanceType: "Standard_NC12s_v3",
+						CapacityType: CapacityTypeSpot,
+					},
+					InferenceService: &InferenceServiceSpec{URL: "http://example.com"},
+					Embedding: &EmbeddingSpec{
+						Remote: &RemoteEmbeddingSpec{URL: "http://remote-embedding.com"},
+					},
+				},
+			},
+			wantErr:  true,
+			errField: "RAGEngine does not support capacity types",
+		},
 	}
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 	for _, tt := range tests {
@@ -7,6 +7,13 @@ import (
 	"context"
 )
 
+// DefaultInstanceType is the GPU node SKU used if no instance type is specified, except for preset inference
+// workloads whose instance type is selected by the controller from the GPU requirements of the preset.
+const DefaultInstanceType = "Standard_NC12s_v3"
+
 // SetDefaults for the Workspace
 func (w *Workspace) SetDefaults(_ context.Context) {
+	if w.Resource.InstanceType == "" && (w.Inference == nil || w.Inference.Preset == nil) {
+		w.Resource.InstanceType = DefaultInstanceType
+	}
 }
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWorkspaceSetDefaults(t *testing.T) {
+	tests := []struct {
+		name                 string
+		workspace            *Workspace
+		expectedInstanceType string
+	}{
+		{
+			name: "Preset inference keeps an empty instance type",
+			workspace: &Workspace{
+				Inference: &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation"}}},
+			},
+			expectedInstanceType: "",
+		},
+		{
+			name: "Template inference defaults the instance type",
+			workspace: &Workspace{
+				Inference: &InferenceSpec{Template: &corev1.PodTemplateSpec{}},
+			},
+			expectedInstanceType: DefaultInstanceType,
+		},
+		{
+			name: "Tuning defaults the instance type",
+			workspace: &Workspace{
+				Tuning: &TuningSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation"}}},
+			},
+			expectedInstanceType: DefaultInstanceType,
+		},
+		{
+			name: "Specified instance type is kept",
+			workspace: &Workspace{
+				Resource: ResourceSpec{InstanceType: "Standard_NC6s_v3"},
+				Tuning:   &TuningSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: "test-validation"}}},
+			},
+			expectedInstanceType: "Standard_NC6s_v3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.workspace.SetDefaults(context.Background())
+			if tt.workspace.Resource.InstanceType != tt.expectedInstanceType {
+				t.Errorf("SetDefaults() instanceType = %q, want %q", tt.workspace.Resource.InstanceType, tt.expectedInstanceType)
+			}
+		})
+	}
+}
+
+func TestRAGEngineSetDefaults(t *testing.T) {
+	ragEngine := &RAGEngine{Spec: &RAGEngineSpec{Compute: &ResourceSpec{}}}
+	ragEngine.SetDefaults(context.Background())
+	if ragEngine.Spec.Compute.InstanceType != DefaultInstanceType {
+		t.Errorf("SetDefaults() instanceType = %q, want %q", ragEngine.Spec.Compute.InstanceType, DefaultInstanceType)
+	}
+}
@@ -6,6 +6,7 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -20,16 +21,23 @@ const (
 // The final list of nodes used to run the workload is presented in workspace Status.
 type ResourceSpec struct {
 	// Count is the required number of GPU nodes.
+	// For inference workloads, Count can be updated to scale the workload up or down.
 	// +optional
 	// +kubebuilder:default:=1
 	Count *int `json:"count,omitempty"`
 
 	// InstanceType specifies the GPU node SKU.
-	// This field defaults to "Standard_NC12s_v3" if not specified.
+	// This field defaults to "Standard_NC12s_v3" if not specified, except for preset inference workloads, for which
+	// the controller selects the cheapest supported SKU which meets the GPU requirements of the preset, and reports
+	// it in the workspace status.
 	// +optional
-	// +kubebuilder:default:="Standard_NC12s_v3"
 	InstanceType string `json:"instanceType,omitempty"`
 
+	// FallbackInstanceTypes is an ordered list of GPU node SKUs which are used in turn if nodes of InstanceType
+	// cannot be provisioned because there is no capacity. The SKU in use is reported in the workspace status.
+	// +optional
+	FallbackInstanceTypes []string `json:"fallbackInstanceTypes,omitempty"`
+
 	// LabelSelector specifies the required labels for the GPU nodes.
 	LabelSelector *metav1.LabelSelector `json:"labelSelector"`
 
@@ -37,8 +45,64 @@ type ResourceSpec struct {
 	// If a node in the list does not have the required labels, it will be ignored.
 	// +optional
 	PreferredNodes []string `json:"preferredNodes,omitempty"`
+
+	// CapacityType specifies the capacity type of the GPU nodes provisioned by the controller. Spot nodes are cheaper
+	// but can be evicted by the cloud provider at any time, evicted nodes are replaced automatically.
+	// The capacity type of the node provisioner is used if not specified. It is not supported by RAGEngines.
+	// +optional
+	CapacityType CapacityType `json:"capacityType,omitempty"`
+
+	// SharedNodePool is the name of a pool of GPU nodes shared by workspaces. The workload of the workspace is placed
+	// on the nodes of the pool which have enough free GPUs left by the other workspaces, new nodes are added to the
+	// pool only when none of them fits. The nodes of the pool are deleted once no workspace uses them.
+	// Only preset inference workloads can run on shared nodes.
+	// +optional
+	SharedNodePool string `json:"sharedNodePool,omitempty"`
+
+	// FractionalGPU runs the workload on a fraction of a GPU of the nodes, either a MIG slice or a time-sliced replica.
+	// Only preset inference workloads of models which fit in a single GPU slice are supported.
+	// +optional
+	FractionalGPU *FractionalGPUSpec `json:"fractionalGPU,omitempty"`
+}
+
+// FractionalGPUStrategy is how the GPUs of the nodes are partitioned.
+// +kubebuilder:validation:Enum=mig;time-slicing
+type FractionalGPUStrategy string
+
+const (
+	// FractionalGPUStrategyMIG partitions the GPUs into Multi-Instance GPU slices with isolated memory.
+	FractionalGPUStrategyMIG FractionalGPUStrategy = "mig"
+	// FractionalGPUStrategyTimeSlicing shares the GPUs between replicas which are scheduled in turn.
+	FractionalGPUStrategyTimeSlicing FractionalGPUStrategy = "time-slicing"
+)
+
+// FractionalGPUSpec describes the GPU slice which the workload runs on.
+type FractionalGPUSpec struct {
+	// Strategy is how the GPUs of the nodes are partitioned.
+	Strategy FractionalGPUStrategy `json:"strategy"`
+	// MIGProfile is the MIG profile of the GPU slice used by the workload, e.g. 1g.10gb. It is required by the mig
+	// strategy. The nodes provisioned by the controller are partitioned with the profile by the NVIDIA GPU operator.
+	// +optional
+	MIGProfile string `json:"migProfile,omitempty"`
+	// Replicas is the number of time-sliced replicas each GPU of the nodes is shared by, it is required by the
+	// time-slicing strategy. The nodes need to be configured with the same number of replicas.
+	// +optional
+	Replicas int `json:"replicas,omitempty"`
 }
 
+// CapacityType is the capacity type of the GPU nodes provisioned for the workload.
+// +kubebuilder:validation:Enum=on-demand;spot;spot-with-fallback
+type CapacityType string
+
+const (
+	// CapacityTypeOnDemand provisions on-demand nodes.
+	CapacityTypeOnDemand CapacityType = "on-demand"
+	// CapacityTypeSpot provisions spot nodes only.
+	CapacityTypeSpot CapacityType = "spot"
+	// CapacityTypeSpotWithFallback provisions spot nodes, or on-demand nodes if there is no spot capacity.
+	CapacityTypeSpotWithFallback CapacityType = "spot-with-fallback"
+)
+
 type ModelName string
 
 // +kubebuilder:validation:Enum=public;private
@@ -64,11 +128,62 @@ type PresetOptions struct {
 	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
 }
 
+// PodOverrides customizes the pods rendered from the preset configurations. They are merged on top of the preset
+// output, the fields managed by KAITO such as the command and the GPU resource cannot be overridden.
+type PodOverrides struct {
+	// Labels are added to the pod labels. Keys with the kaito.sh/ prefix are reserved for KAITO.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are added to the pod annotations. Keys with the kaito.sh/ prefix are reserved for KAITO.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Env is added to the environment of the model container. The variables set by KAITO take precedence.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	Env []v1.EnvVar `json:"env,omitempty"`
+	// Volumes are added to the pod volumes. They cannot use the names of the volumes generated by KAITO.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	Volumes []v1.Volume `json:"volumes,omitempty"`
+	// VolumeMounts are added to the model container. They cannot use the mount paths of the volumes generated by KAITO.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+	// SecurityContext is the security context of the pod.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	SecurityContext *v1.PodSecurityContext `json:"securityContext,omitempty"`
+	// ContainerSecurityContext is the security context of the model container.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	ContainerSecurityContext *v1.SecurityContext `json:"containerSecurityContext,omitempty"`
+	// Resources are the CPU, memory and ephemeral storage requests and limits of the model container. The GPU
+	// resource is set by KAITO and cannot be overridden.
+	// +optional
+	Resources *PodOverridesResources `json:"resources,omitempty"`
+}
+
+// PodOverridesResources are the resource requests and limits which override the ones of the preset configurations.
+type PodOverridesResources struct {
+	// +optional
+	Requests v1.ResourceList `json:"requests,omitempty"`
+	// +optional
+	Limits v1.ResourceList `json:"limits,omitempty"`
+}
+
 // PresetSpec provides the information for rendering preset configurations to run the model inference service.
 type PresetSpec struct {
 	PresetMeta `json:",inline"`
 	// +optional
 	PresetOptions `json:"presetOptions,omitempty"`
+	// PodOverrides customizes the pods rendered from the preset configurations.
+	// +optional
+	PodOverrides *PodOverrides `json:"podOverrides,omitempty"`
 }
 
 type InferenceSpec struct {
@@ -90,6 +205,83 @@ type InferenceSpec struct {
 	// Users can specify multiple adapters for the model and the respective weight of using each of them.
 	// +optional
 	Adapters []AdapterSpec `json:"adapters,omitempty"`
+	// Autoscaling configures metric-driven scaling of the preset inference workload. When specified, the controller
+	// adjusts the number of replicas and GPU nodes between MinReplicas and MaxReplicas based on the target metric,
+	// and Resource.Count is only used as the initial replica count.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// IdleTimeout is the duration without inference requests after which the workload is scaled to zero and the
+	// GPU nodes created for the workspace are released. The Service is kept and the first request sent to it
+	// afterwards wakes the workload up. Scale-to-zero is disabled if not specified.
+	// +optional
+	IdleTimeout *metav1.Duration `json:"idleTimeout,omitempty"`
+	// DisruptionBudget configures the PodDisruptionBudget which the controller creates for the inference workload.
+	// If not specified, at most one pod of a Deployment can be evicted at a time and the pods of the StatefulSet
+	// used for distributed inference cannot be evicted. Since the latter blocks node drains, upgrades and
+	// consolidation, set MaxUnavailable to 1 to allow them.
+	// +optional
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+}
+
+// DisruptionBudgetSpec describes how many pods of the inference workload can be evicted at the same time.
+// Only one of MaxUnavailable and MinAvailable can be specified.
+type DisruptionBudgetSpec struct {
+	// MaxUnavailable is the maximum number, or percentage, of pods that can be unavailable after an eviction.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MinAvailable is the minimum number, or percentage, of pods that must be available after an eviction.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// AutoscalingMetricName is the name of an inference metric that drives autoscaling.
+// +kubebuilder:validation:Enum=queuedRequests;kvCacheUtilization;requestLatency
+type AutoscalingMetricName string
+
+const (
+	// AutoscalingMetricQueuedRequests is the number of requests waiting to be processed per replica.
+	AutoscalingMetricQueuedRequests AutoscalingMetricName = "queuedRequests"
+	// AutoscalingMetricKVCacheUtilization is the GPU KV-cache utilization per replica, between 0 and 1.
+	AutoscalingMetricKVCacheUtilization AutoscalingMetricName = "kvCacheUtilization"
+	// AutoscalingMetricRequestLatency is the average end-to-end request latency per replica in seconds.
+	AutoscalingMetricRequestLatency AutoscalingMetricName = "requestLatency"
+)
+
+type AutoscalingMetricSpec struct {
+	// Name of the metric scraped from the inference pods.
+	Name AutoscalingMetricName `json:"name"`
+	// TargetValue is the desired average value of the metric across replicas, e.g., "5" queued requests,
+	// "0.8" KV-cache utilization or "2.5" seconds of latency. It is defined as a string type to be language agnostic.
+	TargetValue string `json:"targetValue"`
+}
+
+// AutoscalingSpec describes how the inference workload is scaled based on a metric.
+type AutoscalingSpec struct {
+	// MinReplicas is the lower limit for the number of replicas.
+	// +kubebuilder:default:=1
+	// +optional
+	MinReplicas *int `json:"minReplicas,omitempty"`
+	// MaxReplicas is the upper limit for the number of replicas. Each replica runs on its own GPU node.
+	MaxReplicas int `json:"maxReplicas"`
+	// Metric is the signal used to compute the desired number of replicas.
+	Metric AutoscalingMetricSpec `json:"metric"`
+	// ScaleDownDelaySeconds is the minimum time since the last scaling event before replicas and
+	// idle nodes are released. This field defaults to 300 if not specified.
+	// +kubebuilder:default:=300
+	// +optional
+	ScaleDownDelaySeconds *int32 `json:"scaleDownDelaySeconds,omitempty"`
+}
+
+// AutoscalingStatus reports the observed state of metric-driven autoscaling.
+type AutoscalingStatus struct {
+	// DesiredReplicas is the number of replicas computed from the latest metric sample.
+	DesiredReplicas int `json:"desiredReplicas"`
+	// CurrentMetricValue is the latest average value of the target metric across replicas.
+	// +optional
+	CurrentMetricValue string `json:"currentMetricValue,omitempty"`
+	// LastScaleTime is the last time the number of replicas was changed.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
 }
 
 type AdapterSpec struct {
@@ -163,6 +355,108 @@ type TuningSpec struct {
 	Output *DataDestination `json:"output"`
 }
 
+// SchedulingSpec customizes the scheduling of the pods of the workload. It is merged into the scheduling settings which
+// the controller generates from the resource spec.
+type SchedulingSpec struct {
+	// Tolerations are added to the tolerations of the GPU node taints which are set by the controller.
+	// +optional
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// TopologySpreadConstraints describe how the pods are spread across topology domains.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// Affinity is merged into the affinity of the pods. The required node selector terms are combined with the node
+	// requirements of the label selector, the other node affinity terms are added, and the pod affinity and anti-affinity
+	// are used as is.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// +optional
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+}
+
+// AccessSpec restricts the clients which can call the inference service of the workspace. The pods of the workspace
+// only admit requests from the listed namespaces and peers, and from the KAITO controller.
+type AccessSpec struct {
+	// Namespaces are the names of the namespaces whose pods can call the inference service.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Peers select the pods which can call the inference service.
+	// +optional
+	Peers []AccessPeer `json:"peers,omitempty"`
+}
+
+// AccessPeer selects the pods which can call the inference service. If NamespaceSelector is not specified, the pods
+// are selected in the namespace of the workspace, and if PodSelector is not specified, all pods of the selected
+// namespaces are selected.
+type AccessPeer struct {
+	// NamespaceSelector selects namespaces by their labels.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// PodSelector selects pods by their labels.
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// ExposeSpec exposes the inference service outside of the cluster. The controller creates an Ingress for the Service
+// of the workspace, or a Gateway API HTTPRoute if GatewayRef is specified.
+type ExposeSpec struct {
+	// Hostname is the host name of the inference service.
+	Hostname string `json:"hostname"`
+	// PathPrefix is the path prefix of the requests routed to the inference service.
+	// +kubebuilder:default:="/"
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	// IngressClassName is the class of the Ingress. The default IngressClass of the cluster is used if not specified.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+	// TLSSecretName is the name of the Secret which holds the TLS certificate of the host name, in the namespace of
+	// the workspace. It is only used by the Ingress, the TLS settings of a Gateway are configured on its listeners.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// GatewayRef is the Gateway which the HTTPRoute of the inference service is attached to.
+	// +optional
+	GatewayRef *GatewayReference `json:"gatewayRef,omitempty"`
+	// ModelRouting routes the requests to the inference service by the requested model instead of the path prefix
+	// only, so that the workspaces attached to the same Gateway can share a host name.
+	// +optional
+	ModelRouting *ModelRoutingSpec `json:"modelRouting,omitempty"`
+	// ControllerNamespace is the namespace of the ingress controller or Gateway pods which proxy the requests to the
+	// inference service. Its pods are admitted by the NetworkPolicy generated for the access spec. It defaults to the
+	// namespace of the Gateway, and is required for an Ingress if the access spec is specified.
+	// +optional
+	ControllerNamespace string `json:"controllerNamespace,omitempty"`
+}
+
+// GatewayReference identifies a listener of a Gateway.
+type GatewayReference struct {
+	// Name of the Gateway.
+	Name string `json:"name"`
+	// Namespace of the Gateway. It defaults to the namespace of the workspace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName is the name of the listener of the Gateway. The HTTPRoute is attached to all listeners if not specified.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// DefaultModelRoutingHeaderName is the request header which holds the requested model if ModelRoutingSpec.HeaderName
+// is not specified.
+const DefaultModelRoutingHeaderName = "X-Gateway-Model-Name"
+
+// ModelRoutingSpec describes how the Gateway routes the OpenAI-compatible requests by the model field of their body.
+// HTTPRoutes cannot match the request body, so the Gateway must copy the model field into a request header, e.g.,
+// with a body-based routing extension.
+type ModelRoutingSpec struct {
+	// HeaderName is the request header which holds the model field of the request body.
+	// +kubebuilder:default:="X-Gateway-Model-Name"
+	// +optional
+	HeaderName string `json:"headerName,omitempty"`
+}
+
 // WorkspaceStatus defines the observed state of Workspace
 type WorkspaceStatus struct {
 	// WorkerNodes is the list of nodes chosen to run the workload based on the workspace resource requirement.
@@ -172,6 +466,62 @@ type WorkspaceStatus struct {
 	// Conditions report the current conditions of the workspace.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Autoscaling reports the state of metric-driven autoscaling if it is enabled.
+	// +optional
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// LastActivityTime is the last time inference requests were observed. It is only reported if IdleTimeout is specified.
+	// +optional
+	LastActivityTime *metav1.Time `json:"lastActivityTime,omitempty"`
+
+	// CurrentRevision is the revision number of the workspace spec that the workload has been reconciled to.
+	// +optional
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+
+	// ExternalURL is the URL of the inference service exposed through an Ingress or an HTTPRoute.
+	// +optional
+	ExternalURL string `json:"externalURL,omitempty"`
+
+	// InstanceType is the instance type of the nodes provisioned for the workspace. It is selected by the controller
+	// if ResourceSpec.InstanceType is not specified, and it is one of ResourceSpec.FallbackInstanceTypes if the
+	// instance types before it were unavailable.
+	// +optional
+	InstanceType string `json:"instanceType,omitempty"`
+
+	// NodeProvisioning reports the provisioning status of each machine/nodeClaim created for the workspace.
+	// +optional
+	NodeProvisioning []NodeProvisioningStatus `json:"nodeProvisioning,omitempty"`
+}
+
+// NodeProvisioningPhase is the provisioning phase of a machine/nodeClaim created for the workspace.
+type NodeProvisioningPhase string
+
+const (
+	// NodeProvisioningPhasePending means the node is being launched or has not become ready yet.
+	NodeProvisioningPhasePending NodeProvisioningPhase = "Pending"
+	// NodeProvisioningPhaseReady means the node is ready to run the workload.
+	NodeProvisioningPhaseReady NodeProvisioningPhase = "Ready"
+	// NodeProvisioningPhaseFailed means the node failed to launch or did not become ready in time. The machine/nodeClaim
+	// is deleted and a new one is created in its place.
+	NodeProvisioningPhaseFailed NodeProvisioningPhase = "Failed"
+)
+
+// NodeProvisioningStatus is the provisioning status of a machine/nodeClaim created for the workspace.
+type NodeProvisioningStatus struct {
+	// Name is the name of the machine/nodeClaim.
+	Name string `json:"name"`
+
+	// NodeName is the name of the node launched by the machine/nodeClaim.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Phase is the provisioning phase of the node.
+	Phase NodeProvisioningPhase `json:"phase"`
+
+	// Message explains why the node is pending or has failed.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // Workspace is the Schema for the workspaces API
@@ -189,7 +539,17 @@ type Workspace struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Resource  ResourceSpec    `json:"resource,omitempty"`
+	Resource ResourceSpec `json:"resource,omitempty"`
+	// Scheduling customizes the scheduling of the pods of the inference or tuning workload.
+	// +optional
+	Scheduling *SchedulingSpec `json:"scheduling,omitempty"`
+	// Access restricts the clients which can call the inference service. If not specified, the inference service
+	// can be called from any pod in the cluster.
+	// +optional
+	Access *AccessSpec `json:"access,omitempty"`
+	// Expose exposes the inference service outside of the cluster through an Ingress or a Gateway API HTTPRoute.
+	// +optional
+	Expose    *ExposeSpec     `json:"expose,omitempty"`
 	Inference *InferenceSpec  `json:"inference,omitempty"`
 	Tuning    *TuningSpec     `json:"tuning,omitempty"`
 	Status    WorkspaceStatus `json:"status,omitempty"`
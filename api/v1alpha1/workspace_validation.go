@@ -7,13 +7,18 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kaito-project/kaito/pkg/utils"
@@ -24,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/apis"
@@ -33,6 +39,9 @@ const (
 	N_SERIES_PREFIX = "Standard_N"
 	D_SERIES_PREFIX = "Standard_D"
 
+	// GCP accelerator-optimized machine types are named <series>-<type>-<size>, e.g. a2-highgpu-1g or g2-standard-8.
+	GCP_GPU_MACHINE_TYPE_PATTERN = `^(a2|a3|a4|g2)-[a-z]+-[0-9]+g?$`
+
 	DefaultLoraConfigMapTemplate   = "lora-params-template"
 	DefaultQloraConfigMapTemplate  = "qlora-params-template"
 	DefaultInferenceConfigTemplate = "inference-params-template"
@@ -59,6 +68,24 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 			// TODO: Add Adapter Spec Validation - Including DataSource Validation for Adapter
 			errs = errs.Also(w.Resource.validateCreateWithInference(w.Inference).ViaField("resource"),
 				w.Inference.validateCreate(ctx, w.Namespace).ViaField("inference"))
+			if w.Inference.Autoscaling != nil {
+				errs = errs.Also(w.Inference.validateAutoscaling(GetWorkspaceRuntimeName(w)).ViaField("inference.autoscaling"))
+			}
+			if w.Inference.IdleTimeout != nil {
+				errs = errs.Also(w.Inference.validateIdleTimeout(GetWorkspaceRuntimeName(w)).ViaField("inference.idleTimeout"))
+			}
+			if w.Inference.DisruptionBudget != nil {
+				errs = errs.Also(w.Inference.DisruptionBudget.validate().ViaField("inference.disruptionBudget"))
+			}
+		}
+		if w.Scheduling != nil {
+			errs = errs.Also(w.Scheduling.validate().ViaField("scheduling"))
+		}
+		if w.Access != nil {
+			errs = errs.Also(w.validateAccess().ViaField("access"))
+		}
+		if w.Expose != nil {
+			errs = errs.Also(w.validateExpose().ViaField("expose"))
 		}
 		if w.Tuning != nil {
 			// TODO: Add validate resource based on Tuning Spec
@@ -70,13 +97,31 @@ func (w *Workspace) Validate(ctx context.Context) (errs *apis.FieldError) {
 		old := base.(*Workspace)
 		errs = errs.Also(
 			w.validateUpdate(old).ViaField("spec"),
-			w.Resource.validateUpdate(&old.Resource).ViaField("resource"),
+			w.Resource.validateUpdate(&old.Resource, w.Inference != nil).ViaField("resource"),
 		)
 		if w.Inference != nil {
-			errs = errs.Also(w.Inference.validateUpdate(old.Inference).ViaField("inference"))
+			errs = errs.Also(w.Inference.validateUpdate(ctx, w.Namespace, old.Inference).ViaField("inference"))
+			if w.Inference.Autoscaling != nil {
+				errs = errs.Also(w.Inference.validateAutoscaling(GetWorkspaceRuntimeName(w)).ViaField("inference.autoscaling"))
+			}
+			if w.Inference.IdleTimeout != nil {
+				errs = errs.Also(w.Inference.validateIdleTimeout(GetWorkspaceRuntimeName(w)).ViaField("inference.idleTimeout"))
+			}
+			if w.Inference.DisruptionBudget != nil {
+				errs = errs.Also(w.Inference.DisruptionBudget.validate().ViaField("inference.disruptionBudget"))
+			}
+		}
+		if w.Scheduling != nil {
+			errs = errs.Also(w.Scheduling.validate().ViaField("scheduling"))
+		}
+		if w.Access != nil {
+			errs = errs.Also(w.validateAccess().ViaField("access"))
+		}
+		if w.Expose != nil {
+			errs = errs.Also(w.validateExpose().ViaField("expose"))
 		}
 		if w.Tuning != nil {
-			errs = errs.Also(w.Tuning.validateUpdate(old.Tuning).ViaField("tuning"))
+			errs = errs.Also(w.Tuning.validateUpdate(ctx, w.Namespace, old.Tuning).ViaField("tuning"))
 		}
 	}
 	return errs
@@ -171,13 +216,18 @@ func (r *TuningSpec) validateCreate(ctx context.Context, workspaceNamespace stri
 	// Currently require a preset to specified, in future we can consider defining a template
 	if r.Preset == nil {
 		errs = errs.Also(apis.ErrMissingField("Preset"))
-	} else if presetName := string(r.Preset.Name); !plugin.IsValidPreset(presetName) {
-		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported tuning preset name %s", presetName), "presetName"))
+	} else {
+		if presetName := string(r.Preset.Name); !plugin.IsValidPreset(presetName) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported tuning preset name %s", presetName), "presetName"))
+		}
+		if r.Preset.PodOverrides != nil {
+			errs = errs.Also(r.Preset.PodOverrides.validate(defaultContainerLimits(ctx, workspaceNamespace, r.Preset.PodOverrides)).ViaField("Preset.podOverrides"))
+		}
 	}
 	return errs
 }
 
-func (r *TuningSpec) validateUpdate(old *TuningSpec) (errs *apis.FieldError) {
+func (r *TuningSpec) validateUpdate(ctx context.Context, namespace string, old *TuningSpec) (errs *apis.FieldError) {
 	if r.Input == nil {
 		errs = errs.Also(apis.ErrMissingField("Input"))
 	} else {
@@ -188,9 +238,13 @@ func (r *TuningSpec) validateUpdate(old *TuningSpec) (errs *apis.FieldError) {
 	} else {
 		errs = errs.Also(r.Output.validateUpdate().ViaField("Output"))
 	}
-	if !reflect.DeepEqual(old.Preset, r.Preset) {
+	// The pod overrides can be changed, they are rolled out with the workload.
+	if !reflect.DeepEqual(old.Preset.withoutPodOverrides(), r.Preset.withoutPodOverrides()) {
 		errs = errs.Also(apis.ErrGeneric("Preset cannot be changed", "Preset"))
 	}
+	if r.Preset != nil && r.Preset.PodOverrides != nil {
+		errs = errs.Also(r.Preset.PodOverrides.validate(defaultContainerLimits(ctx, namespace, r.Preset.PodOverrides)).ViaField("Preset.podOverrides"))
+	}
 	oldMethod, newMethod := strings.ToLower(string(old.Method)), strings.ToLower(string(r.Method))
 	if !reflect.DeepEqual(oldMethod, newMethod) {
 		errs = errs.Also(apis.ErrGeneric("Method cannot be changed", "Method"))
@@ -285,6 +339,27 @@ func (r *DataDestination) validateUpdate() (errs *apis.FieldError) {
 }
 
 func (r *ResourceSpec) validateCreateWithTuning(tuning *TuningSpec) (errs *apis.FieldError) {
+	if r.InstanceType == "" {
+		errs = errs.Also(apis.ErrMissingField("instanceType"))
+	}
+	if len(r.FallbackInstanceTypes) > 0 {
+		errs = errs.Also(apis.ErrGeneric("Tuning does not support fallback instance types", "fallbackInstanceTypes"))
+	}
+	if r.SharedNodePool != "" {
+		errs = errs.Also(apis.ErrGeneric("Tuning does not support shared node pools", "sharedNodePool"))
+	}
+	if r.FractionalGPU != nil {
+		errs = errs.Also(apis.ErrGeneric("Tuning does not support fractional GPUs", "fractionalGPU"))
+	}
+	if tuning.Preset != nil && r.InstanceType != "" && plugin.IsValidPreset(string(tuning.Preset.Name)) {
+		presetName := string(tuning.Preset.Name)
+		vendor := utils.GetGPUVendor(r.InstanceType)
+		if !plugin.KaitoModelRegister.MustGet(presetName).GetTuningParameters().SupportsGPUVendor(vendor) {
+			errs = errs.Also(apis.ErrInvalidValue(
+				fmt.Sprintf("Unsupported GPU vendor: Instance type %s has %s GPUs, but preset %s has no image built for them", r.InstanceType, vendor.Name, presetName),
+				"instanceType"))
+		}
+	}
 	if *r.Count > 1 {
 		errs = errs.Also(apis.ErrInvalidValue("Tuning does not currently support multinode configurations. Please set the node count to 1. Future support with DeepSpeed will allow this.", "count"))
 	}
@@ -303,14 +378,131 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec) (er
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to get SKU handler: %v", err), "instanceType"))
 		return errs
 	}
-	gpuConfigs := skuHandler.GetGPUConfigs()
 
-	// Check if instancetype exists in our SKUs map for the particular cloud provider
-	if skuConfig, exists := gpuConfigs[instanceType]; exists {
+	if instanceType == "" {
+		// The instance type can only be selected by the controller from the GPU requirements of a preset.
+		if presetName == "" {
+			errs = errs.Also(apis.ErrMissingField("instanceType"))
+		} else {
+			model := plugin.KaitoModelRegister.MustGet(presetName) // InferenceSpec has been validated so the name is valid.
+			if _, _, err := sku.SelectInstanceType(skuHandler, model.GetInferenceParameters().GetGPURequirement(*r.Count)); err != nil {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Failed to select an instance type for preset %s: %v, please specify one", presetName, err), "instanceType"))
+			}
+		}
+	} else {
+		errs = errs.Also(validateInstanceType(skuHandler, instanceType, presetName, *r.Count, "instanceType"))
+	}
+
+	// Fallback instance types are validated in the same way, they must be different from each other.
+	if len(r.FallbackInstanceTypes) > 0 && instanceType == "" {
+		errs = errs.Also(apis.ErrGeneric("fallbackInstanceTypes can only be specified together with instanceType", "fallbackInstanceTypes"))
+	}
+	seen := map[string]bool{instanceType: true}
+	for i, fallback := range r.FallbackInstanceTypes {
+		field := fmt.Sprintf("fallbackInstanceTypes[%d]", i)
+		if seen[fallback] {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Instance type %s is specified more than once", fallback), field))
+			continue
+		}
+		seen[fallback] = true
+		errs = errs.Also(validateInstanceType(skuHandler, fallback, presetName, *r.Count, field))
+	}
+
+	if r.SharedNodePool != "" {
+		if presetName == "" {
+			errs = errs.Also(apis.ErrGeneric("Only preset inference workloads can run on shared nodes", "sharedNodePool"))
+		} else if plugin.KaitoModelRegister.MustGet(presetName).SupportDistributedInference() && *r.Count > 1 {
+			errs = errs.Also(apis.ErrGeneric("Distributed inference across multiple nodes cannot run on shared nodes", "sharedNodePool"))
+		}
+		if errmsgs := validation.IsValidLabelValue(r.SharedNodePool); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "sharedNodePool"))
+		}
+	}
+
+	if r.FractionalGPU != nil {
+		errs = errs.Also(r.validateFractionalGPU(skuHandler, presetName))
+	}
+
+	// Validate labelSelector
+	if _, err := metav1.LabelSelectorAsSelector(r.LabelSelector); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+	}
+
+	return errs
+}
+
+// validateFractionalGPU validates that the GPU slice is well formed and that the preset fits in a single slice.
+func (r *ResourceSpec) validateFractionalGPU(skuHandler sku.CloudSKUHandler, presetName string) (errs *apis.FieldError) {
+	fractional := r.FractionalGPU
+	if presetName == "" {
+		return errs.Also(apis.ErrGeneric("Only preset inference workloads can run on fractional GPUs", "fractionalGPU"))
+	}
+	if r.InstanceType == "" {
+		errs = errs.Also(apis.ErrGeneric("instanceType must be specified to run on fractional GPUs", "instanceType"))
+	}
+	skuConfig, knownSKU := skuHandler.GetGPUConfigs()[r.InstanceType]
+
+	var sliceMemory int
+	switch fractional.Strategy {
+	case FractionalGPUStrategyMIG:
+		if fractional.Replicas != 0 {
+			errs = errs.Also(apis.ErrGeneric("replicas can only be specified with the time-slicing strategy", "fractionalGPU.replicas"))
+		}
+		memory, err := sku.GetMIGProfileMemory(fractional.MIGProfile)
+		if err != nil {
+			return errs.Also(apis.ErrInvalidValue(err.Error(), "fractionalGPU.migProfile"))
+		}
+		if knownSKU && sku.GetGPUVendor(skuConfig.GPUModel) != sku.GPUVendorNvidia {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Instance type %s does not have NVIDIA GPUs which support MIG", r.InstanceType), "instanceType"))
+		}
+		sliceMemory = memory
+	case FractionalGPUStrategyTimeSlicing:
+		if fractional.MIGProfile != "" {
+			errs = errs.Also(apis.ErrGeneric("migProfile can only be specified with the mig strategy", "fractionalGPU.migProfile"))
+		}
+		if fractional.Replicas < 2 {
+			return errs.Also(apis.ErrInvalidValue(fmt.Sprintf("replicas must be at least 2, got %d", fractional.Replicas), "fractionalGPU.replicas"))
+		}
+		if !knownSKU {
+			// The size of the slices cannot be computed without the GPU memory of the instance type.
+			return errs
+		}
+		sliceMemory = skuConfig.GPUMem / skuConfig.GPUCount / fractional.Replicas
+	default:
+		return errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported fractional GPU strategy %s", fractional.Strategy), "fractionalGPU.strategy"))
+	}
+
+	params := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters()
+	if params.GPUCountRequirement != "1" {
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("Preset %s requires %s GPUs, only presets which run on a single GPU can run on fractional GPUs", presetName, params.GPUCountRequirement),
+			"fractionalGPU"))
+	}
+	sliceGPUMemory := resource.NewQuantity(int64(sliceMemory)*consts.GiBToBytes, resource.BinarySI)
+	modelTotalGPUMemory := resource.MustParse(params.TotalGPUMemoryRequirement)
+	if sliceGPUMemory.Cmp(modelTotalGPUMemory) < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(
+			fmt.Sprintf("Insufficient GPU slice memory: GPU slices provide %s, but preset %s requires at least %s",
+				sliceGPUMemory.String(), presetName, modelTotalGPUMemory.String()),
+			"fractionalGPU"))
+	}
+	return errs
+}
+
+// validateInstanceType validates that the instance type is supported and, for presets, that nodes of the instance type
+// meet the GPU requirements of the preset. The errors are reported on the given field.
+func validateInstanceType(skuHandler sku.CloudSKUHandler, instanceType, presetName string, machineCount int, field string) (errs *apis.FieldError) {
+	if skuConfig, exists := skuHandler.GetGPUConfigs()[instanceType]; exists {
+		// The instance type exists in our SKUs map for the particular cloud provider
 		if presetName != "" {
 			model := plugin.KaitoModelRegister.MustGet(presetName) // InferenceSpec has been validated so the name is valid.
 
-			machineCount := *r.Count
+			if vendor := sku.GetGPUVendor(skuConfig.GPUModel); !model.GetInferenceParameters().SupportsGPUVendor(vendor) {
+				errs = errs.Also(apis.ErrInvalidValue(
+					fmt.Sprintf("Unsupported GPU vendor: Instance type %s has %s GPUs, but preset %s has no image built for them", instanceType, vendor.Name, presetName),
+					field))
+			}
+
 			machineTotalNumGPUs := resource.NewQuantity(int64(machineCount*skuConfig.GPUCount), resource.DecimalSI)
 			machinePerGPUMemory := resource.NewQuantity(int64(skuConfig.GPUMem/skuConfig.GPUCount)*consts.GiBToBytes, resource.BinarySI) // Ensure it's per GPU
 			machineTotalGPUMem := resource.NewQuantity(int64(machineCount*skuConfig.GPUMem)*consts.GiBToBytes, resource.BinarySI)        // Total GPU memory
@@ -329,7 +521,7 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec) (er
 						presetName,
 						modelGPUCount.Value(),
 					),
-					"instanceType",
+					field,
 				))
 			}
 
@@ -342,7 +534,7 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec) (er
 						presetName,
 						modelPerGPUMemory.String(),
 					),
-					"instanceType",
+					field,
 				))
 			}
 
@@ -355,42 +547,57 @@ func (r *ResourceSpec) validateCreateWithInference(inference *InferenceSpec) (er
 						presetName,
 						modelTotalGPUMemory.String(),
 					),
-					"instanceType",
+					field,
 				))
 			}
 		}
 	} else {
-		provider := os.Getenv("CLOUD_PROVIDER")
-		// Check for other instance types pattern matches if cloud provider is Azure
-		if provider != consts.AzureCloudName || (!strings.HasPrefix(instanceType, N_SERIES_PREFIX) && !strings.HasPrefix(instanceType, D_SERIES_PREFIX)) {
-			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), "instanceType"))
+		// Check for other instance types pattern matches of the cloud provider
+		if !matchesInstanceTypePattern(os.Getenv("CLOUD_PROVIDER"), instanceType) {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported instance type %s. Supported SKUs: %s", instanceType, skuHandler.GetSupportedSKUs()), field))
 		}
 	}
+	return errs
+}
 
-	// Validate labelSelector
-	if _, err := metav1.LabelSelectorAsMap(r.LabelSelector); err != nil {
-		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+// matchesInstanceTypePattern returns whether an instance type unknown to the SKU handler follows the naming of the
+// GPU instance types of the cloud provider.
+func matchesInstanceTypePattern(provider, instanceType string) bool {
+	switch provider {
+	case consts.AzureCloudName:
+		return strings.HasPrefix(instanceType, N_SERIES_PREFIX) || strings.HasPrefix(instanceType, D_SERIES_PREFIX)
+	case consts.GCPCloudName:
+		return regexp.MustCompile(GCP_GPU_MACHINE_TYPE_PATTERN).MatchString(instanceType)
+	default:
+		return false
 	}
-
-	return errs
 }
 
-func (r *ResourceSpec) validateUpdate(old *ResourceSpec) (errs *apis.FieldError) {
-	// We disable changing node count for now.
+func (r *ResourceSpec) validateUpdate(old *ResourceSpec, isInference bool) (errs *apis.FieldError) {
+	// Node count can be changed to scale inference workloads, tuning jobs keep a fixed node count.
 	if r.Count != nil && old.Count != nil && *r.Count != *old.Count {
-		errs = errs.Also(apis.ErrGeneric("field is immutable", "count"))
+		if !isInference {
+			errs = errs.Also(apis.ErrGeneric("field is immutable", "count"))
+		} else if *r.Count < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("count must be at least 1, got %d", *r.Count), "count"))
+		}
 	}
 	if r.InstanceType != old.InstanceType {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "instanceType"))
 	}
-	newLabels, err0 := metav1.LabelSelectorAsMap(r.LabelSelector)
-	oldLabels, err1 := metav1.LabelSelectorAsMap(old.LabelSelector)
-	if err0 != nil || err1 != nil {
-		errs = errs.Also(apis.ErrGeneric("Only allow matchLabels or 'IN' matchExpression", "labelSelector"))
-	} else {
-		if !reflect.DeepEqual(newLabels, oldLabels) {
-			errs = errs.Also(apis.ErrGeneric("field is immutable", "labelSelector"))
-		}
+	if r.SharedNodePool != old.SharedNodePool {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "sharedNodePool"))
+	}
+	if !reflect.DeepEqual(r.FractionalGPU, old.FractionalGPU) {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "fractionalGPU"))
+	}
+	// The selectors are compared in their canonical form, so that reordering the requirements is not a change.
+	newSelector, err0 := metav1.LabelSelectorAsSelector(r.LabelSelector)
+	oldSelector, err1 := metav1.LabelSelectorAsSelector(old.LabelSelector)
+	if err0 != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err0.Error(), "labelSelector"))
+	} else if err1 != nil || newSelector.String() != oldSelector.String() {
+		errs = errs.Also(apis.ErrGeneric("field is immutable", "labelSelector"))
 	}
 	return errs
 }
@@ -422,6 +629,9 @@ func (i *InferenceSpec) validateCreate(ctx context.Context, namespace string) (e
 			errs = errs.Also(apis.ErrGeneric("When AccessMode is private, an image must be provided in PresetOptions"))
 		}
 		// Note: we don't enforce private access mode to have image secrets, in case anonymous pulling is enabled
+		if i.Preset.PodOverrides != nil {
+			errs = errs.Also(i.Preset.PodOverrides.validate(defaultContainerLimits(ctx, namespace, i.Preset.PodOverrides)).ViaField("preset.podOverrides"))
+		}
 	}
 	if len(i.Adapters) > MaxAdaptersNumber {
 		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Number of Adapters exceeds the maximum limit, maximum of %s allowed", strconv.Itoa(MaxAdaptersNumber))))
@@ -465,10 +675,14 @@ func (i *InferenceSpec) validateConfigMap(ctx context.Context, namespace string)
 	return errs
 }
 
-func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldError) {
-	if !reflect.DeepEqual(i.Preset, old.Preset) {
+func (i *InferenceSpec) validateUpdate(ctx context.Context, namespace string, old *InferenceSpec) (errs *apis.FieldError) {
+	// The pod overrides can be changed, they are rolled out with the workload.
+	if !reflect.DeepEqual(i.Preset.withoutPodOverrides(), old.Preset.withoutPodOverrides()) {
 		errs = errs.Also(apis.ErrGeneric("field is immutable", "preset"))
 	}
+	if i.Preset != nil && i.Preset.PodOverrides != nil {
+		errs = errs.Also(i.Preset.PodOverrides.validate(defaultContainerLimits(ctx, namespace, i.Preset.PodOverrides)).ViaField("preset.podOverrides"))
+	}
 	// inference.template can be changed, but cannot be set/unset.
 	if (i.Template != nil && old.Template == nil) || (i.Template == nil && old.Template != nil) {
 		errs = errs.Also(apis.ErrGeneric("field cannot be unset/set if it was set/unset", "template"))
@@ -488,6 +702,423 @@ func (i *InferenceSpec) validateUpdate(old *InferenceSpec) (errs *apis.FieldErro
 	return errs
 }
 
+func (i *InferenceSpec) validateAutoscaling(runtime model.RuntimeName) (errs *apis.FieldError) {
+	a := i.Autoscaling
+	if i.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("Autoscaling is only supported for preset inference", "preset"))
+	} else if plugin.IsValidPreset(string(i.Preset.Name)) &&
+		plugin.KaitoModelRegister.MustGet(string(i.Preset.Name)).SupportDistributedInference() {
+		errs = errs.Also(apis.ErrGeneric("Autoscaling is not supported for presets that use distributed inference", "preset"))
+	}
+	// The scaling metrics are exposed by the vLLM runtime only.
+	if runtime != model.RuntimeNameVLLM {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Autoscaling requires the %s runtime, got %s", model.RuntimeNameVLLM, runtime)))
+	}
+
+	minReplicas := 1
+	if a.MinReplicas != nil {
+		minReplicas = *a.MinReplicas
+	}
+	if minReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("minReplicas must be at least 1, got %d", minReplicas), "minReplicas"))
+	}
+	if a.MaxReplicas < minReplicas {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("maxReplicas %d must not be less than minReplicas %d", a.MaxReplicas, minReplicas), "maxReplicas"))
+	}
+	if a.ScaleDownDelaySeconds != nil && *a.ScaleDownDelaySeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue("scaleDownDelaySeconds must not be negative", "scaleDownDelaySeconds"))
+	}
+
+	switch a.Metric.Name {
+	case AutoscalingMetricQueuedRequests, AutoscalingMetricKVCacheUtilization, AutoscalingMetricRequestLatency:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported autoscaling metric %s", a.Metric.Name), "metric.name"))
+	}
+	target, err := strconv.ParseFloat(a.Metric.TargetValue, 64)
+	if err != nil || target <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("targetValue must be a positive number, got %q", a.Metric.TargetValue), "metric.targetValue"))
+	} else if a.Metric.Name == AutoscalingMetricKVCacheUtilization && target > 1 {
+		errs = errs.Also(apis.ErrInvalidValue("targetValue of kvCacheUtilization must be between 0 and 1", "metric.targetValue"))
+	}
+	return errs
+}
+
+// minIdleTimeout is the lower limit of the idle timeout, a shorter timeout would scale the workload down between requests.
+const minIdleTimeout = time.Minute
+
+func (i *InferenceSpec) validateIdleTimeout(runtime model.RuntimeName) (errs *apis.FieldError) {
+	if i.Preset == nil {
+		errs = errs.Also(apis.ErrGeneric("Scale-to-zero is only supported for preset inference", "preset"))
+	}
+	// Request activity is observed through the metrics exposed by the vLLM runtime.
+	if runtime != model.RuntimeNameVLLM {
+		errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Scale-to-zero requires the %s runtime, got %s", model.RuntimeNameVLLM, runtime)))
+	}
+	if i.IdleTimeout.Duration < minIdleTimeout {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("idleTimeout must be at least %s, got %s", minIdleTimeout, i.IdleTimeout.Duration), ""))
+	}
+	return errs
+}
+
+func (w *Workspace) validateAccess() (errs *apis.FieldError) {
+	if w.Inference == nil {
+		errs = errs.Also(apis.ErrGeneric("Access is only supported for inference workspaces"))
+	}
+	// The activator which wakes idle workspaces up forwards the requests of any client.
+	if w.Inference != nil && w.Inference.IdleTimeout != nil {
+		errs = errs.Also(apis.ErrGeneric("Access cannot be combined with inference.idleTimeout", "inference.idleTimeout"))
+	}
+	a := w.Access
+	if len(a.Namespaces) == 0 && len(a.Peers) == 0 {
+		errs = errs.Also(apis.ErrMissingOneOf("namespaces", "peers"))
+	}
+	for i, namespace := range a.Namespaces {
+		if errmsgs := validation.IsDNS1123Label(namespace); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "").ViaFieldIndex("namespaces", i))
+		}
+	}
+	for i, peer := range a.Peers {
+		if peer.NamespaceSelector == nil && peer.PodSelector == nil {
+			errs = errs.Also(apis.ErrMissingOneOf("namespaceSelector", "podSelector").ViaFieldIndex("peers", i))
+		}
+		if _, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "namespaceSelector").ViaFieldIndex("peers", i))
+		}
+		if _, err := metav1.LabelSelectorAsSelector(peer.PodSelector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "podSelector").ViaFieldIndex("peers", i))
+		}
+	}
+	return errs
+}
+
+func (w *Workspace) validateExpose() (errs *apis.FieldError) {
+	if w.Inference == nil {
+		errs = errs.Also(apis.ErrGeneric("Expose is only supported for inference workspaces"))
+	}
+	e := w.Expose
+	if e.Hostname == "" {
+		errs = errs.Also(apis.ErrMissingField("hostname"))
+	} else if errmsgs := validation.IsDNS1123Subdomain(e.Hostname); len(errmsgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "hostname"))
+	}
+	if e.PathPrefix != "" && !strings.HasPrefix(e.PathPrefix, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("pathPrefix must start with /, got %s", e.PathPrefix), "pathPrefix"))
+	}
+	if e.TLSSecretName != "" {
+		if errmsgs := validation.IsDNS1123Subdomain(e.TLSSecretName); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "tlsSecretName"))
+		}
+	}
+
+	if e.GatewayRef != nil {
+		if e.IngressClassName != nil {
+			errs = errs.Also(apis.ErrMultipleOneOf("gatewayRef", "ingressClassName"))
+		}
+		if e.TLSSecretName != "" {
+			errs = errs.Also(apis.ErrGeneric("TLS of a Gateway is configured on its listeners", "gatewayRef", "tlsSecretName"))
+		}
+		if errmsgs := validation.IsDNS1123Subdomain(e.GatewayRef.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "gatewayRef.name"))
+		}
+		if e.GatewayRef.Namespace != "" {
+			if errmsgs := validation.IsDNS1123Label(e.GatewayRef.Namespace); len(errmsgs) > 0 {
+				errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "gatewayRef.namespace"))
+			}
+		}
+	}
+	if e.ControllerNamespace != "" {
+		if errmsgs := validation.IsDNS1123Label(e.ControllerNamespace); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "controllerNamespace"))
+		}
+	} else if w.Access != nil && e.GatewayRef == nil {
+		// The NetworkPolicy of the access spec would drop the requests proxied by the ingress controller.
+		errs = errs.Also(apis.ErrGeneric("controllerNamespace is required to admit the ingress controller when access is specified", "controllerNamespace"))
+	}
+	if e.ModelRouting != nil {
+		if e.GatewayRef == nil {
+			errs = errs.Also(apis.ErrGeneric("Model routing is only supported for Gateways", "modelRouting"))
+		}
+		if w.Inference != nil && w.Inference.Preset == nil {
+			errs = errs.Also(apis.ErrGeneric("Model routing is only supported for preset inference", "modelRouting"))
+		}
+		if e.ModelRouting.HeaderName != "" {
+			if errmsgs := validation.IsHTTPHeaderName(e.ModelRouting.HeaderName); len(errmsgs) > 0 {
+				errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "modelRouting.headerName"))
+			}
+		}
+	}
+	return errs
+}
+
+func (d *DisruptionBudgetSpec) validate() (errs *apis.FieldError) {
+	if d.MaxUnavailable != nil && d.MinAvailable != nil {
+		errs = errs.Also(apis.ErrMultipleOneOf("maxUnavailable", "minAvailable"))
+	}
+	if d.MaxUnavailable != nil {
+		errs = errs.Also(validateIntOrPercent(d.MaxUnavailable, "maxUnavailable"))
+	}
+	if d.MinAvailable != nil {
+		errs = errs.Also(validateIntOrPercent(d.MinAvailable, "minAvailable"))
+	}
+	return errs
+}
+
+// validateIntOrPercent checks that the value is a non-negative integer or a percentage between 0% and 100%.
+func validateIntOrPercent(value *intstr.IntOrString, field string) *apis.FieldError {
+	if value.Type == intstr.Int {
+		if value.IntVal < 0 {
+			return apis.ErrInvalidValue(fmt.Sprintf("%s must not be negative, got %d", field, value.IntVal), field)
+		}
+		return nil
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+	if !strings.HasSuffix(value.StrVal, "%") || err != nil || percent < 0 || percent > 100 {
+		return apis.ErrInvalidValue(fmt.Sprintf("%s must be an integer or a percentage between 0%% and 100%%, got %q", field, value.StrVal), field)
+	}
+	return nil
+}
+
+// podOverridesResourceNames are the resources which can be overridden, the GPU resource is managed by KAITO.
+var podOverridesResourceNames = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory, corev1.ResourceEphemeralStorage}
+
+// validate checks the pod overrides. defaultLimits are the limits of the model container which are not overridden,
+// the requests must not exceed the limits applied once they are merged with the overridden ones.
+func (p *PodOverrides) validate(defaultLimits corev1.ResourceList) (errs *apis.FieldError) {
+	errs = errs.Also(validateOverrideMetadata(p.Labels).ViaField("labels"))
+	errs = errs.Also(validateOverrideMetadata(p.Annotations).ViaField("annotations"))
+	for i, env := range p.Env {
+		if errmsgs := validation.IsEnvVarName(env.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "name").ViaFieldIndex("env", i))
+		}
+	}
+
+	volumeNames := make(map[string]bool)
+	for i, volume := range p.Volumes {
+		if errmsgs := validation.IsDNS1123Label(volume.Name); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "name").ViaFieldIndex("volumes", i))
+		} else if lo.Contains(utils.ReservedVolumeNames, volume.Name) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Volume name %s is reserved for KAITO", volume.Name), "name").ViaFieldIndex("volumes", i))
+		} else if volumeNames[volume.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Duplicate volume name %s", volume.Name), "name").ViaFieldIndex("volumes", i))
+		}
+		volumeNames[volume.Name] = true
+	}
+	for i, mount := range p.VolumeMounts {
+		if !volumeNames[mount.Name] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Volume mount must refer to a volume in podOverrides, got %s", mount.Name), "name").ViaFieldIndex("volumeMounts", i))
+		}
+		if mount.MountPath == "" {
+			errs = errs.Also(apis.ErrMissingField("mountPath").ViaFieldIndex("volumeMounts", i))
+		} else if lo.Contains(utils.ReservedMountPaths, path.Clean(mount.MountPath)) {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("Mount path %s is reserved for KAITO", mount.MountPath), "mountPath").ViaFieldIndex("volumeMounts", i))
+		}
+	}
+
+	if p.Resources != nil {
+		errs = errs.Also(validateOverrideResources(p.Resources.Requests).ViaField("resources.requests"))
+		errs = errs.Also(validateOverrideResources(p.Resources.Limits).ViaField("resources.limits"))
+		limits := lo.Assign(defaultLimits, p.Resources.Limits)
+		for name, request := range p.Resources.Requests {
+			if limit, ok := limits[name]; ok && request.Cmp(limit) > 0 {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("request of %s must not exceed its limit %s", name, limit.String()), "resources.requests"))
+			}
+		}
+	}
+	return errs
+}
+
+// withoutPodOverrides returns a copy of the preset without its pod overrides.
+func (p *PresetSpec) withoutPodOverrides() *PresetSpec {
+	if p == nil {
+		return nil
+	}
+	preset := p.DeepCopy()
+	preset.PodOverrides = nil
+	return preset
+}
+
+// defaultContainerLimits returns the default container limits of the LimitRanges of the namespace, which the API
+// server sets on the model container for the resources whose limit is not generated by KAITO nor overridden.
+// They are only looked up when the overrides set resource requests, the lowest default of a resource is returned.
+func defaultContainerLimits(ctx context.Context, namespace string, overrides *PodOverrides) corev1.ResourceList {
+	if overrides.Resources == nil || len(overrides.Resources.Requests) == 0 || k8sclient.Client == nil {
+		return nil
+	}
+	limitRanges := &corev1.LimitRangeList{}
+	if err := k8sclient.Client.List(ctx, limitRanges, client.InNamespace(namespace)); err != nil {
+		klog.ErrorS(err, "failed to list LimitRanges", "namespace", namespace)
+		return nil
+	}
+	limits := corev1.ResourceList{}
+	for _, limitRange := range limitRanges.Items {
+		for _, item := range limitRange.Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for name, quantity := range item.Default {
+				if limit, ok := limits[name]; !ok || quantity.Cmp(limit) < 0 {
+					limits[name] = quantity
+				}
+			}
+		}
+	}
+	return limits
+}
+
+func validateOverrideMetadata(metadata map[string]string) (errs *apis.FieldError) {
+	for key := range metadata {
+		if errmsgs := validation.IsQualifiedName(key); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "", strings.Join(errmsgs, ", ")))
+		} else if strings.HasPrefix(key, KAITOPrefix) {
+			errs = errs.Also(apis.ErrInvalidKeyName(key, "", fmt.Sprintf("keys with the %s prefix are reserved for KAITO", KAITOPrefix)))
+		}
+	}
+	return errs
+}
+
+func validateOverrideResources(resources corev1.ResourceList) (errs *apis.FieldError) {
+	for name, quantity := range resources {
+		if !lo.Contains(podOverridesResourceNames, name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(string(name), "", "only cpu, memory and ephemeral-storage can be overridden"))
+		} else if quantity.Sign() < 0 {
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("%s must not be negative", name), string(name)))
+		}
+	}
+	return errs
+}
+
+func (s *SchedulingSpec) validate() (errs *apis.FieldError) {
+	for i, toleration := range s.Tolerations {
+		errs = errs.Also(validateToleration(toleration).ViaFieldIndex("tolerations", i))
+	}
+	if s.PriorityClassName != "" {
+		if errmsgs := validation.IsDNS1123Subdomain(s.PriorityClassName); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "priorityClassName"))
+		}
+	}
+	for i, constraint := range s.TopologySpreadConstraints {
+		errs = errs.Also(validateTopologySpreadConstraint(constraint).ViaFieldIndex("topologySpreadConstraints", i))
+	}
+	if s.Affinity != nil {
+		errs = errs.Also(validateAffinity(s.Affinity).ViaField("affinity"))
+	}
+	return errs
+}
+
+func validateToleration(toleration corev1.Toleration) (errs *apis.FieldError) {
+	if toleration.Key != "" {
+		if errmsgs := validation.IsQualifiedName(toleration.Key); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "key"))
+		}
+	}
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+		if toleration.Key == "" {
+			errs = errs.Also(apis.ErrGeneric("operator must be Exists when key is empty", "operator"))
+		}
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			errs = errs.Also(apis.ErrGeneric("value must be empty when operator is Exists", "value"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported toleration operator %s", toleration.Operator), "operator"))
+	}
+	switch toleration.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute, "":
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported toleration effect %s", toleration.Effect), "effect"))
+	}
+	return errs
+}
+
+func validateTopologySpreadConstraint(constraint corev1.TopologySpreadConstraint) (errs *apis.FieldError) {
+	if constraint.MaxSkew <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("maxSkew must be greater than 0, got %d", constraint.MaxSkew), "maxSkew"))
+	}
+	if constraint.TopologyKey == "" {
+		errs = errs.Also(apis.ErrMissingField("topologyKey"))
+	} else if errmsgs := validation.IsQualifiedName(constraint.TopologyKey); len(errmsgs) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), "topologyKey"))
+	}
+	switch constraint.WhenUnsatisfiable {
+	case corev1.DoNotSchedule, corev1.ScheduleAnyway:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported whenUnsatisfiable %s", constraint.WhenUnsatisfiable), "whenUnsatisfiable"))
+	}
+	if _, err := metav1.LabelSelectorAsSelector(constraint.LabelSelector); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+	}
+	return errs
+}
+
+func validateAffinity(affinity *corev1.Affinity) (errs *apis.FieldError) {
+	if nodeAffinity := affinity.NodeAffinity; nodeAffinity != nil {
+		if required := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for i, term := range required.NodeSelectorTerms {
+				errs = errs.Also(validateNodeSelectorTerm(term).
+					ViaFieldIndex("nodeSelectorTerms", i).ViaField("nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution"))
+			}
+		}
+		for i, term := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if term.Weight < 1 || term.Weight > 100 {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("weight must be between 1 and 100, got %d", term.Weight), "weight").
+					ViaFieldIndex("nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution", i))
+			}
+			errs = errs.Also(validateNodeSelectorTerm(term.Preference).ViaField("preference").
+				ViaFieldIndex("nodeAffinity.preferredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+	if affinity.PodAffinity != nil {
+		for i, term := range affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			errs = errs.Also(validatePodAffinityTerm(term).ViaFieldIndex("podAffinity.requiredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+	if affinity.PodAntiAffinity != nil {
+		for i, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			errs = errs.Also(validatePodAffinityTerm(term).ViaFieldIndex("podAntiAffinity.requiredDuringSchedulingIgnoredDuringExecution", i))
+		}
+	}
+	return errs
+}
+
+func validateNodeSelectorTerm(term corev1.NodeSelectorTerm) (errs *apis.FieldError) {
+	for i, requirement := range term.MatchExpressions {
+		field := fmt.Sprintf("matchExpressions[%d]", i)
+		if errmsgs := validation.IsQualifiedName(requirement.Key); len(errmsgs) > 0 {
+			errs = errs.Also(apis.ErrInvalidValue(strings.Join(errmsgs, ", "), field+".key"))
+		}
+		switch requirement.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+			if len(requirement.Values) == 0 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("values must be specified for operator %s", requirement.Operator), field+".values"))
+			}
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+			if len(requirement.Values) > 0 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("values must be empty for operator %s", requirement.Operator), field+".values"))
+			}
+		case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+			if len(requirement.Values) != 1 {
+				errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("exactly one value must be specified for operator %s", requirement.Operator), field+".values"))
+			} else if _, err := strconv.ParseInt(requirement.Values[0], 10, 64); err != nil {
+				errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("value must be an integer for operator %s", requirement.Operator), field+".values"))
+			}
+		default:
+			errs = errs.Also(apis.ErrInvalidValue(fmt.Sprintf("Unsupported node selector operator %s", requirement.Operator), field+".operator"))
+		}
+	}
+	return errs
+}
+
+func validatePodAffinityTerm(term corev1.PodAffinityTerm) (errs *apis.FieldError) {
+	if term.TopologyKey == "" {
+		errs = errs.Also(apis.ErrMissingField("topologyKey"))
+	}
+	if _, err := metav1.LabelSelectorAsSelector(term.LabelSelector); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "labelSelector"))
+	}
+	return errs
+}
+
 func validateDuplicateName(adapters []AdapterSpec, nameMap map[string]bool) (errs *apis.FieldError) {
 	for _, adapter := range adapters {
 		if _, ok := nameMap[adapter.Source.Name]; ok {
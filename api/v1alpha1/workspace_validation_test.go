@@ -8,15 +8,20 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -342,6 +347,112 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 			expectErrs:     false,
 			validateTuning: false,
 		},
+		{
+			name: "Instance type selected for preset",
+			resourceSpec: &ResourceSpec{
+				Count: pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "80Gi",
+			modelTotalGPUMemory: "80Gi",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "No instance type meets preset requirement",
+			resourceSpec: &ResourceSpec{
+				Count: pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "200Gi",
+			modelTotalGPUMemory: "200Gi",
+			preset:              true,
+			errContent:          "Failed to select an instance type",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Instance type missing for template",
+			resourceSpec: &ResourceSpec{
+				Count: pointerToInt(1),
+			},
+			preset:         false,
+			errContent:     "missing field(s): instanceType",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Preset without image for the GPU vendor",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NG32ads_V620_v1",
+				Count:        pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "Unsupported GPU vendor",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Valid fallback instance types",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC24ads_A100_v4",
+				FallbackInstanceTypes: []string{"Standard_NC48ads_A100_v4", "Standard_NC24s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Fallback instance type with insufficient per GPU memory",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC24ads_A100_v4",
+				FallbackInstanceTypes: []string{"Standard_NC6s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "40Gi",
+			modelTotalGPUMemory: "40Gi",
+			preset:              true,
+			errContent:          "fallbackInstanceTypes[0]",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Duplicate fallback instance type",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC6s_v3",
+				FallbackInstanceTypes: []string{"Standard_NC12s_v3", "Standard_NC6s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			preset:         false,
+			errContent:     "Instance type Standard_NC6s_v3 is specified more than once",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Fallback instance types without instance type",
+			resourceSpec: &ResourceSpec{
+				FallbackInstanceTypes: []string{"Standard_NC12s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "0",
+			modelTotalGPUMemory: "0",
+			preset:              true,
+			errContent:          "fallbackInstanceTypes can only be specified together with instanceType",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
 		{
 			name: "Tuning validation with single node",
 			resourceSpec: &ResourceSpec{
@@ -362,6 +473,236 @@ func TestResourceSpecValidateCreate(t *testing.T) {
 			expectErrs:     true,
 			validateTuning: true,
 		},
+		{
+			name: "Tuning validation with fallback instance types",
+			resourceSpec: &ResourceSpec{
+				InstanceType:          "Standard_NC6s_v3",
+				FallbackInstanceTypes: []string{"Standard_NC12s_v3"},
+				Count:                 pointerToInt(1),
+			},
+			errContent:     "Tuning does not support fallback instance types",
+			expectErrs:     true,
+			validateTuning: true,
+		},
+		{
+			name: "Shared node pool with preset",
+			resourceSpec: &ResourceSpec{
+				InstanceType:   "Standard_NC24s_v3",
+				Count:          pointerToInt(1),
+				SharedNodePool: "small-models",
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "0",
+			modelTotalGPUMemory: "0",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Shared node pool with template",
+			resourceSpec: &ResourceSpec{
+				InstanceType:   "Standard_NC24s_v3",
+				Count:          pointerToInt(1),
+				SharedNodePool: "small-models",
+			},
+			preset:         false,
+			errContent:     "Only preset inference workloads can run on shared nodes",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Invalid shared node pool name",
+			resourceSpec: &ResourceSpec{
+				InstanceType:   "Standard_NC24s_v3",
+				Count:          pointerToInt(1),
+				SharedNodePool: "small models",
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "0",
+			modelTotalGPUMemory: "0",
+			preset:              true,
+			errContent:          "sharedNodePool",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Tuning validation with shared node pool",
+			resourceSpec: &ResourceSpec{
+				InstanceType:   "Standard_NC6s_v3",
+				Count:          pointerToInt(1),
+				SharedNodePool: "small-models",
+			},
+			errContent:     "Tuning does not support shared node pools",
+			expectErrs:     true,
+			validateTuning: true,
+		},
+		{
+			name: "LabelSelector with match expressions",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC6s_v3",
+				Count:        pointerToInt(1),
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"zone-1"}},
+					{Key: "draining", Operator: metav1.LabelSelectorOpDoesNotExist},
+				}},
+			},
+			preset:         false,
+			errContent:     "",
+			expectErrs:     false,
+			validateTuning: false,
+		},
+		{
+			name: "LabelSelector with invalid match expression",
+			resourceSpec: &ResourceSpec{
+				InstanceType: "Standard_NC6s_v3",
+				Count:        pointerToInt(1),
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpExists, Values: []string{"zone-1"}},
+				}},
+			},
+			preset:         false,
+			errContent:     "labelSelector",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Fractional GPU with MIG profile",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24ads_A100_v4",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "1g.10gb"},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "8Gi",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with MIG slice too small for the preset",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24ads_A100_v4",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "1g.10gb"},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "16Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "Insufficient GPU slice memory",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with invalid MIG profile",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24ads_A100_v4",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "10gb"},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "8Gi",
+			preset:              true,
+			errContent:          "invalid MIG profile",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with time-slicing",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24s_v3",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyTimeSlicing, Replicas: 2},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "8Gi",
+			preset:              true,
+			errContent:          "",
+			expectErrs:          false,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with time-sliced replicas too small for the preset",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24s_v3",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyTimeSlicing, Replicas: 4},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "8Gi",
+			preset:              true,
+			errContent:          "Insufficient GPU slice memory",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with time-slicing without replicas",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24s_v3",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyTimeSlicing},
+			},
+			modelGPUCount:       "1",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "8Gi",
+			preset:              true,
+			errContent:          "replicas must be at least 2",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU for preset requiring multiple GPUs",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24ads_A100_v4",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "3g.40gb"},
+			},
+			modelGPUCount:       "2",
+			modelPerGPUMemory:   "8Gi",
+			modelTotalGPUMemory: "16Gi",
+			preset:              true,
+			errContent:          "only presets which run on a single GPU",
+			expectErrs:          true,
+			validateTuning:      false,
+		},
+		{
+			name: "Fractional GPU with template",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC24ads_A100_v4",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "1g.10gb"},
+			},
+			preset:         false,
+			errContent:     "Only preset inference workloads can run on fractional GPUs",
+			expectErrs:     true,
+			validateTuning: false,
+		},
+		{
+			name: "Tuning validation with fractional GPU",
+			resourceSpec: &ResourceSpec{
+				InstanceType:  "Standard_NC6s_v3",
+				Count:         pointerToInt(1),
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyTimeSlicing, Replicas: 2},
+			},
+			errContent:     "Tuning does not support fractional GPUs",
+			expectErrs:     true,
+			validateTuning: true,
+		},
+		{
+			name: "Tuning validation without instance type",
+			resourceSpec: &ResourceSpec{
+				Count: pointerToInt(1),
+			},
+			errContent:     "missing field(s): instanceType",
+			expectErrs:     true,
+			validateTuning: true,
+		},
 	}
 
 	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
@@ -427,11 +768,12 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 		name        string
 		newResource *ResourceSpec
 		oldResource *ResourceSpec
+		isInference bool
 		errContent  string // Content expected error to include, if any
 		expectErrs  bool
 	}{
 		{
-			name: "Immutable Count",
+			name: "Immutable Count For Tuning",
 			newResource: &ResourceSpec{
 				Count: pointerToInt(10),
 			},
@@ -441,6 +783,30 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Mutable Count For Inference",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(10),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			isInference: true,
+			errContent:  "",
+			expectErrs:  false,
+		},
+		{
+			name: "Invalid Count For Inference",
+			newResource: &ResourceSpec{
+				Count: pointerToInt(0),
+			},
+			oldResource: &ResourceSpec{
+				Count: pointerToInt(5),
+			},
+			isInference: true,
+			errContent:  "count must be at least 1",
+			expectErrs:  true,
+		},
 		{
 			name: "Immutable InstanceType",
 			newResource: &ResourceSpec{
@@ -452,6 +818,30 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Immutable SharedNodePool",
+			newResource: &ResourceSpec{
+				SharedNodePool: "new-pool",
+			},
+			oldResource: &ResourceSpec{
+				SharedNodePool: "old-pool",
+			},
+			isInference: true,
+			errContent:  "field is immutable",
+			expectErrs:  true,
+		},
+		{
+			name: "Immutable FractionalGPU",
+			newResource: &ResourceSpec{
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "2g.20gb"},
+			},
+			oldResource: &ResourceSpec{
+				FractionalGPU: &FractionalGPUSpec{Strategy: FractionalGPUStrategyMIG, MIGProfile: "1g.10gb"},
+			},
+			isInference: true,
+			errContent:  "field is immutable",
+			expectErrs:  true,
+		},
 		{
 			name: "Immutable LabelSelector",
 			newResource: &ResourceSpec{
@@ -463,6 +853,38 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Immutable LabelSelector match expressions",
+			newResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"zone-1"}},
+				}},
+			},
+			oldResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"zone-2"}},
+				}},
+			},
+			errContent: "field is immutable",
+			expectErrs: true,
+		},
+		{
+			name: "Reordered LabelSelector match expressions",
+			newResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "draining", Operator: metav1.LabelSelectorOpDoesNotExist},
+					{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"zone-2", "zone-1"}},
+				}},
+			},
+			oldResource: &ResourceSpec{
+				LabelSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"zone-1", "zone-2"}},
+					{Key: "draining", Operator: metav1.LabelSelectorOpDoesNotExist},
+				}},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
 		{
 			name: "Valid Update",
 			newResource: &ResourceSpec{
@@ -483,7 +905,7 @@ func TestResourceSpecValidateUpdate(t *testing.T) {
 	// Run the tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			errs := tc.newResource.validateUpdate(tc.oldResource)
+			errs := tc.newResource.validateUpdate(tc.oldResource, tc.isInference)
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
 				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
@@ -650,189 +1072,962 @@ func TestInferenceSpecValidateCreate(t *testing.T) {
 			expectErrs: true,
 		},
 		{
-			name: "Valid Preset",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name:       ModelName("test-validation"),
-						AccessMode: ModelImageAccessModePublic,
-					},
-				},
+			name: "Valid Preset",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("test-validation"),
+						AccessMode: ModelImageAccessModePublic,
+					},
+				},
+			},
+			errContent: "Duplicate adapter source name found:",
+			expectErrs: false,
+		},
+		{
+			name: "Config specified but ConfigMap not found",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("test-validation"),
+						AccessMode: ModelImageAccessModePublic,
+					},
+				},
+				Config: "nonexistent-config",
+			},
+			errContent: "ConfigMap 'nonexistent-config' specified in 'config' not found in namespace",
+			expectErrs: true,
+		},
+		{
+			name: "Config specified with valid ConfigMap",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("test-validation"),
+						AccessMode: ModelImageAccessModePublic,
+					},
+				},
+				Config: "valid-config",
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+		{
+			name: "ConfigMap missing required inference_config.yaml",
+			inferenceSpec: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name:       ModelName("test-validation"),
+						AccessMode: ModelImageAccessModePublic,
+					},
+				},
+				Config: "missing-key-config",
+			},
+			errContent: "missing field(s): inference_config.yaml in ConfigMap",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// If the test expects an error, setup defer function to catch the panic.
+			if tc.expectErrs {
+				defer func() {
+					if r := recover(); r != nil {
+						// Check if the recovered panic matches the expected error content.
+						if errContent, ok := r.(string); ok && strings.Contains(errContent, tc.errContent) {
+							return
+						}
+						t.Errorf("unexpected panic: %v", r)
+					}
+				}()
+			}
+			errs := tc.inferenceSpec.validateCreate(ctx, "")
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateCreate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			// If there is an error and errContent is not empty, check that the error contains the expected content.
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateCreate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestAdapterSpecValidateCreateorUpdate(t *testing.T) {
+	RegisterValidationTestModels()
+	tests := []struct {
+		name        string
+		adapterSpec *AdapterSpec
+		errContent  string // Content expected error to include, if any
+		expectErrs  bool
+	}{
+		{
+			name: "Missing Source",
+			adapterSpec: &AdapterSpec{
+				Strength: &ValidStrength,
+			},
+			errContent: "Source",
+			expectErrs: true,
+		},
+		{
+			name: "Missing Source Name",
+			adapterSpec: &AdapterSpec{
+				Source: &DataSource{
+					Image: "fake.kaito.com/kaito-image:0.0.1",
+				},
+				Strength: &ValidStrength,
+			},
+			errContent: "Name of Adapter field must be specified",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Strength, not a number",
+			adapterSpec: &AdapterSpec{
+				Source: &DataSource{
+					Name:  "Adapter-1",
+					Image: "fake.kaito.com/kaito-image:0.0.1",
+				},
+				Strength: &InvalidStrength1,
+			},
+			errContent: "Invalid strength value for Adapter 'Adapter-1'",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Strength, larger than 1",
+			adapterSpec: &AdapterSpec{
+				Source: &DataSource{
+					Name:  "Adapter-1",
+					Image: "fake.kaito.com/kaito-image:0.0.1",
+				},
+				Strength: &InvalidStrength2,
+			},
+			errContent: "Strength value for Adapter 'Adapter-1' must be between 0 and 1",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Source Name, longer than 253",
+			adapterSpec: &AdapterSpec{
+				Source: &DataSource{
+					Name:  invalidSourceName,
+					Image: "fake.kaito.com/kaito-image:0.0.1",
+				},
+				Strength: &ValidStrength,
+			},
+			errContent: "invalid value",
+			expectErrs: true,
+		},
+		{
+			name: "Valid Adapter",
+			adapterSpec: &AdapterSpec{
+				Source: &DataSource{
+					Name:  "adapter-1",
+					Image: "fake.kaito.com/kaito-image:0.0.1",
+				},
+			},
+			errContent: "",
+			expectErrs: false,
+		},
+	}
+
+	// Run the tests
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.adapterSpec.validateCreateorUpdate()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			// If there is an error and errContent is not empty, check that the error contains the expected content.
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateUpdate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestInferenceSpecValidateAutoscaling(t *testing.T) {
+	RegisterValidationTestModels()
+	validAutoscaling := func() *AutoscalingSpec {
+		return &AutoscalingSpec{
+			MinReplicas: pointerToInt(1),
+			MaxReplicas: 3,
+			Metric: AutoscalingMetricSpec{
+				Name:        AutoscalingMetricQueuedRequests,
+				TargetValue: "5",
+			},
+		}
+	}
+	tests := []struct {
+		name       string
+		runtime    model.RuntimeName
+		modify     func(i *InferenceSpec)
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name:       "Valid Autoscaling",
+			runtime:    model.RuntimeNameVLLM,
+			modify:     func(i *InferenceSpec) {},
+			expectErrs: false,
+		},
+		{
+			name:    "Template Inference",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Preset = nil
+				i.Template = &v1.PodTemplateSpec{}
+			},
+			errContent: "Autoscaling is only supported for preset inference",
+			expectErrs: true,
+		},
+		{
+			name:       "Transformers Runtime",
+			runtime:    model.RuntimeNameHuggingfaceTransformers,
+			modify:     func(i *InferenceSpec) {},
+			errContent: "Autoscaling requires the vllm runtime",
+			expectErrs: true,
+		},
+		{
+			name:    "Invalid MinReplicas",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Autoscaling.MinReplicas = pointerToInt(0)
+			},
+			errContent: "minReplicas must be at least 1",
+			expectErrs: true,
+		},
+		{
+			name:    "MaxReplicas Less Than MinReplicas",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Autoscaling.MinReplicas = pointerToInt(4)
+			},
+			errContent: "maxReplicas 3 must not be less than minReplicas 4",
+			expectErrs: true,
+		},
+		{
+			name:    "Negative ScaleDownDelaySeconds",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				delay := int32(-1)
+				i.Autoscaling.ScaleDownDelaySeconds = &delay
+			},
+			errContent: "scaleDownDelaySeconds must not be negative",
+			expectErrs: true,
+		},
+		{
+			name:    "Unsupported Metric",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Autoscaling.Metric.Name = "gpuUtilization"
+			},
+			errContent: "Unsupported autoscaling metric gpuUtilization",
+			expectErrs: true,
+		},
+		{
+			name:    "Invalid TargetValue",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Autoscaling.Metric.TargetValue = "-1"
+			},
+			errContent: "targetValue must be a positive number",
+			expectErrs: true,
+		},
+		{
+			name:    "KVCacheUtilization TargetValue Larger Than 1",
+			runtime: model.RuntimeNameVLLM,
+			modify: func(i *InferenceSpec) {
+				i.Autoscaling.Metric.Name = AutoscalingMetricKVCacheUtilization
+				i.Autoscaling.Metric.TargetValue = "1.5"
+			},
+			errContent: "targetValue of kvCacheUtilization must be between 0 and 1",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inferenceSpec := &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+				},
+				Autoscaling: validAutoscaling(),
+			}
+			tc.modify(inferenceSpec)
+			errs := inferenceSpec.validateAutoscaling(tc.runtime)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateAutoscaling() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateAutoscaling() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestInferenceSpecValidateIdleTimeout(t *testing.T) {
+	RegisterValidationTestModels()
+	tests := []struct {
+		name        string
+		runtime     model.RuntimeName
+		template    bool
+		idleTimeout time.Duration
+		errContent  string // Content expected error to include, if any
+		expectErrs  bool
+	}{
+		{
+			name:        "Valid IdleTimeout",
+			runtime:     model.RuntimeNameVLLM,
+			idleTimeout: time.Hour,
+			expectErrs:  false,
+		},
+		{
+			name:        "Template Inference",
+			runtime:     model.RuntimeNameVLLM,
+			template:    true,
+			idleTimeout: time.Hour,
+			errContent:  "Scale-to-zero is only supported for preset inference",
+			expectErrs:  true,
+		},
+		{
+			name:        "Transformers Runtime",
+			runtime:     model.RuntimeNameHuggingfaceTransformers,
+			idleTimeout: time.Hour,
+			errContent:  "Scale-to-zero requires the vllm runtime",
+			expectErrs:  true,
+		},
+		{
+			name:        "IdleTimeout Too Short",
+			runtime:     model.RuntimeNameVLLM,
+			idleTimeout: 30 * time.Second,
+			errContent:  "idleTimeout must be at least 1m0s, got 30s",
+			expectErrs:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inferenceSpec := &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{
+						Name: ModelName("test-validation"),
+					},
+				},
+				IdleTimeout: &metav1.Duration{Duration: tc.idleTimeout},
+			}
+			if tc.template {
+				inferenceSpec.Preset = nil
+				inferenceSpec.Template = &v1.PodTemplateSpec{}
+			}
+			errs := inferenceSpec.validateIdleTimeout(tc.runtime)
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateIdleTimeout() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateIdleTimeout() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulingSpecValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		scheduling *SchedulingSpec
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name: "Valid Scheduling",
+			scheduling: &SchedulingSpec{
+				Tolerations: []v1.Toleration{
+					{Key: "example.com/dedicated", Operator: v1.TolerationOpEqual, Value: "inference", Effect: v1.TaintEffectNoSchedule},
+					{Operator: v1.TolerationOpExists},
+				},
+				PriorityClassName: "inference-high",
+				TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+					{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway},
+				},
+				Affinity: &v1.Affinity{
+					NodeAffinity: &v1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+							NodeSelectorTerms: []v1.NodeSelectorTerm{
+								{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "example.com/draining", Operator: v1.NodeSelectorOpDoesNotExist}}},
+							},
+						},
+					},
+				},
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Toleration With Value And Exists Operator",
+			scheduling: &SchedulingSpec{
+				Tolerations: []v1.Toleration{{Key: "example.com/dedicated", Operator: v1.TolerationOpExists, Value: "inference"}},
+			},
+			errContent: "value must be empty when operator is Exists: tolerations[0].value",
+			expectErrs: true,
+		},
+		{
+			name: "Toleration Without Key",
+			scheduling: &SchedulingSpec{
+				Tolerations: []v1.Toleration{{Operator: v1.TolerationOpEqual, Value: "inference"}},
+			},
+			errContent: "operator must be Exists when key is empty",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Toleration Effect",
+			scheduling: &SchedulingSpec{
+				Tolerations: []v1.Toleration{{Key: "example.com/dedicated", Effect: "Evict"}},
+			},
+			errContent: "Unsupported toleration effect Evict",
+			expectErrs: true,
+		},
+		{
+			name:       "Invalid PriorityClassName",
+			scheduling: &SchedulingSpec{PriorityClassName: "Inference_High"},
+			errContent: "priorityClassName",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid TopologySpreadConstraint",
+			scheduling: &SchedulingSpec{
+				TopologySpreadConstraints: []v1.TopologySpreadConstraint{{MaxSkew: 0, WhenUnsatisfiable: v1.DoNotSchedule}},
+			},
+			errContent: "maxSkew must be greater than 0",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Node Affinity Operator",
+			scheduling: &SchedulingSpec{
+				Affinity: &v1.Affinity{
+					NodeAffinity: &v1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+							NodeSelectorTerms: []v1.NodeSelectorTerm{
+								{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn}}},
+							},
+						},
+					},
+				},
+			},
+			errContent: "values must be specified for operator In",
+			expectErrs: true,
+		},
+		{
+			name: "Pod Anti-Affinity Without Topology Key",
+			scheduling: &SchedulingSpec{
+				Affinity: &v1.Affinity{
+					PodAntiAffinity: &v1.PodAntiAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{{}},
+					},
+				},
+			},
+			errContent: "missing field(s): affinity.podAntiAffinity.requiredDuringSchedulingIgnoredDuringExecution[0].topologyKey",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.scheduling.validate()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		access     *AccessSpec
+		tuning     bool
+		idle       bool
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name: "Valid Access",
+			access: &AccessSpec{
+				Namespaces: []string{"clients"},
+				Peers: []AccessPeer{
+					{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "ml"}}},
+					{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "chat"}}},
+				},
+			},
+			expectErrs: false,
+		},
+		{
+			name:       "Empty Access",
+			access:     &AccessSpec{},
+			errContent: "expected exactly one, got neither: namespaces, peers",
+			expectErrs: true,
+		},
+		{
+			name:       "Invalid Namespace",
+			access:     &AccessSpec{Namespaces: []string{"Clients"}},
+			errContent: "namespaces[0]",
+			expectErrs: true,
+		},
+		{
+			name:       "Peer Without Selector",
+			access:     &AccessSpec{Peers: []AccessPeer{{}}},
+			errContent: "peers[0].namespaceSelector, peers[0].podSelector",
+			expectErrs: true,
+		},
+		{
+			name: "Invalid Pod Selector",
+			access: &AccessSpec{Peers: []AccessPeer{
+				{PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Equals"}}}},
+			}},
+			errContent: "peers[0].podSelector",
+			expectErrs: true,
+		},
+		{
+			name:       "Access With Idle Timeout",
+			access:     &AccessSpec{Namespaces: []string{"clients"}},
+			idle:       true,
+			errContent: "Access cannot be combined with inference.idleTimeout",
+			expectErrs: true,
+		},
+		{
+			name:       "Access With Tuning",
+			access:     &AccessSpec{Namespaces: []string{"clients"}},
+			tuning:     true,
+			errContent: "Access is only supported for inference workspaces",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := &Workspace{Access: tc.access, Inference: &InferenceSpec{}}
+			if tc.idle {
+				w.Inference.IdleTimeout = &metav1.Duration{Duration: time.Hour}
+			}
+			if tc.tuning {
+				w.Inference, w.Tuning = nil, &TuningSpec{}
+			}
+			errs := w.validateAccess()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validateAccess() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validateAccess() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkspaceValidateExpose(t *testing.T) {
+	tests := []struct {
+		name       string
+		expose     *ExposeSpec
+		access     *AccessSpec
+		tuning     bool
+		noPreset   bool
+		errContent string // Content expected error to include, if any
+		expectErrs bool
+	}{
+		{
+			name:       "Valid Ingress",
+			expose:     &ExposeSpec{Hostname: "llm.example.com", PathPrefix: "/falcon", TLSSecretName: "llm-tls"},
+			expectErrs: false,
+		},
+		{
+			name: "Valid Gateway With Model Routing",
+			expose: &ExposeSpec{
+				Hostname:     "llm.example.com",
+				GatewayRef:   &GatewayReference{Name: "inference-gateway", Namespace: "gateway", SectionName: "https"},
+				ModelRouting: &ModelRoutingSpec{HeaderName: DefaultModelRoutingHeaderName},
+			},
+			expectErrs: false,
+		},
+		{
+			name:       "Missing Hostname",
+			expose:     &ExposeSpec{},
+			errContent: "missing field(s): hostname",
+			expectErrs: true,
+		},
+		{
+			name:       "Invalid Hostname",
+			expose:     &ExposeSpec{Hostname: "LLM_example"},
+			errContent: "hostname",
+			expectErrs: true,
+		},
+		{
+			name:       "Invalid Path Prefix",
+			expose:     &ExposeSpec{Hostname: "llm.example.com", PathPrefix: "falcon"},
+			errContent: "pathPrefix must start with /",
+			expectErrs: true,
+		},
+		{
+			name: "Gateway With Ingress Class",
+			expose: &ExposeSpec{
+				Hostname:         "llm.example.com",
+				IngressClassName: lo.ToPtr("nginx"),
+				GatewayRef:       &GatewayReference{Name: "inference-gateway"},
+			},
+			errContent: "expected exactly one, got both: gatewayRef, ingressClassName",
+			expectErrs: true,
+		},
+		{
+			name: "Gateway With TLS Secret",
+			expose: &ExposeSpec{
+				Hostname:      "llm.example.com",
+				TLSSecretName: "llm-tls",
+				GatewayRef:    &GatewayReference{Name: "inference-gateway"},
+			},
+			errContent: "TLS of a Gateway is configured on its listeners",
+			expectErrs: true,
+		},
+		{
+			name:       "Model Routing Without Gateway",
+			expose:     &ExposeSpec{Hostname: "llm.example.com", ModelRouting: &ModelRoutingSpec{}},
+			errContent: "Model routing is only supported for Gateways",
+			expectErrs: true,
+		},
+		{
+			name: "Model Routing Without Preset",
+			expose: &ExposeSpec{
+				Hostname:     "llm.example.com",
+				GatewayRef:   &GatewayReference{Name: "inference-gateway"},
+				ModelRouting: &ModelRoutingSpec{},
 			},
-			errContent: "Duplicate adapter source name found:",
-			expectErrs: false,
+			noPreset:   true,
+			errContent: "Model routing is only supported for preset inference",
+			expectErrs: true,
 		},
 		{
-			name: "Config specified but ConfigMap not found",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name:       ModelName("test-validation"),
-						AccessMode: ModelImageAccessModePublic,
-					},
-				},
-				Config: "nonexistent-config",
+			name: "Invalid Header Name",
+			expose: &ExposeSpec{
+				Hostname:     "llm.example.com",
+				GatewayRef:   &GatewayReference{Name: "inference-gateway"},
+				ModelRouting: &ModelRoutingSpec{HeaderName: "model name"},
 			},
-			errContent: "ConfigMap 'nonexistent-config' specified in 'config' not found in namespace",
+			errContent: "modelRouting.headerName",
 			expectErrs: true,
 		},
 		{
-			name: "Config specified with valid ConfigMap",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name:       ModelName("test-validation"),
-						AccessMode: ModelImageAccessModePublic,
-					},
-				},
-				Config: "valid-config",
-			},
-			errContent: "",
+			name:       "Ingress With Access And Controller Namespace",
+			expose:     &ExposeSpec{Hostname: "llm.example.com", ControllerNamespace: "ingress-nginx"},
+			access:     &AccessSpec{Namespaces: []string{"clients"}},
 			expectErrs: false,
 		},
 		{
-			name: "ConfigMap missing required inference_config.yaml",
-			inferenceSpec: &InferenceSpec{
-				Preset: &PresetSpec{
-					PresetMeta: PresetMeta{
-						Name:       ModelName("test-validation"),
-						AccessMode: ModelImageAccessModePublic,
-					},
-				},
-				Config: "missing-key-config",
+			name:       "Ingress With Access Without Controller Namespace",
+			expose:     &ExposeSpec{Hostname: "llm.example.com"},
+			access:     &AccessSpec{Namespaces: []string{"clients"}},
+			errContent: "controllerNamespace is required to admit the ingress controller when access is specified",
+			expectErrs: true,
+		},
+		{
+			name: "Gateway With Access Without Controller Namespace",
+			expose: &ExposeSpec{
+				Hostname:   "llm.example.com",
+				GatewayRef: &GatewayReference{Name: "inference-gateway", Namespace: "gateway"},
 			},
-			errContent: "missing field(s): inference_config.yaml in ConfigMap",
+			access:     &AccessSpec{Namespaces: []string{"clients"}},
+			expectErrs: false,
+		},
+		{
+			name:       "Invalid Controller Namespace",
+			expose:     &ExposeSpec{Hostname: "llm.example.com", ControllerNamespace: "Ingress_Nginx"},
+			errContent: "controllerNamespace",
+			expectErrs: true,
+		},
+		{
+			name:       "Expose With Tuning",
+			expose:     &ExposeSpec{Hostname: "llm.example.com"},
+			tuning:     true,
+			errContent: "Expose is only supported for inference workspaces",
 			expectErrs: true,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			// If the test expects an error, setup defer function to catch the panic.
-			if tc.expectErrs {
-				defer func() {
-					if r := recover(); r != nil {
-						// Check if the recovered panic matches the expected error content.
-						if errContent, ok := r.(string); ok && strings.Contains(errContent, tc.errContent) {
-							return
-						}
-						t.Errorf("unexpected panic: %v", r)
-					}
-				}()
+			w := &Workspace{Expose: tc.expose, Access: tc.access, Inference: &InferenceSpec{Preset: &PresetSpec{}}}
+			if tc.noPreset {
+				w.Inference.Preset = nil
 			}
-			errs := tc.inferenceSpec.validateCreate(ctx, "")
+			if tc.tuning {
+				w.Inference, w.Tuning = nil, &TuningSpec{}
+			}
+			errs := w.validateExpose()
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
-				t.Errorf("validateCreate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+				t.Errorf("validateExpose() errors = %v, expectErrs %v", errs, tc.expectErrs)
 			}
 
-			// If there is an error and errContent is not empty, check that the error contains the expected content.
 			if hasErrs && tc.errContent != "" {
 				errMsg := errs.Error()
 				if !strings.Contains(errMsg, tc.errContent) {
-					t.Errorf("validateCreate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+					t.Errorf("validateExpose() error message = %v, expected to contain = %v", errMsg, tc.errContent)
 				}
 			}
 		})
 	}
 }
 
-func TestAdapterSpecValidateCreateorUpdate(t *testing.T) {
-	RegisterValidationTestModels()
+func TestDisruptionBudgetValidate(t *testing.T) {
 	tests := []struct {
-		name        string
-		adapterSpec *AdapterSpec
-		errContent  string // Content expected error to include, if any
-		expectErrs  bool
+		name       string
+		budget     *DisruptionBudgetSpec
+		errContent string // Content expected error to include, if any
+		expectErrs bool
 	}{
 		{
-			name: "Missing Source",
-			adapterSpec: &AdapterSpec{
-				Strength: &ValidStrength,
+			name:       "Valid MaxUnavailable",
+			budget:     &DisruptionBudgetSpec{MaxUnavailable: lo.ToPtr(intstr.FromInt32(0))},
+			expectErrs: false,
+		},
+		{
+			name:       "Valid MinAvailable Percentage",
+			budget:     &DisruptionBudgetSpec{MinAvailable: lo.ToPtr(intstr.FromString("50%"))},
+			expectErrs: false,
+		},
+		{
+			name: "Both MaxUnavailable And MinAvailable",
+			budget: &DisruptionBudgetSpec{
+				MaxUnavailable: lo.ToPtr(intstr.FromInt32(1)),
+				MinAvailable:   lo.ToPtr(intstr.FromInt32(1)),
 			},
-			errContent: "Source",
+			errContent: "expected exactly one, got both",
 			expectErrs: true,
 		},
 		{
-			name: "Missing Source Name",
-			adapterSpec: &AdapterSpec{
-				Source: &DataSource{
-					Image: "fake.kaito.com/kaito-image:0.0.1",
+			name:       "Negative MaxUnavailable",
+			budget:     &DisruptionBudgetSpec{MaxUnavailable: lo.ToPtr(intstr.FromInt32(-1))},
+			errContent: "maxUnavailable must not be negative",
+			expectErrs: true,
+		},
+		{
+			name:       "Invalid Percentage",
+			budget:     &DisruptionBudgetSpec{MinAvailable: lo.ToPtr(intstr.FromString("150%"))},
+			errContent: "minAvailable must be an integer or a percentage between 0% and 100%",
+			expectErrs: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.budget.validate()
+			hasErrs := errs != nil
+			if hasErrs != tc.expectErrs {
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+			}
+
+			if hasErrs && tc.errContent != "" {
+				errMsg := errs.Error()
+				if !strings.Contains(errMsg, tc.errContent) {
+					t.Errorf("validate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+				}
+			}
+		})
+	}
+}
+
+func TestPodOverridesValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		podOverrides  *PodOverrides
+		defaultLimits v1.ResourceList
+		errContent    string // Content expected error to include, if any
+		expectErrs    bool
+	}{
+		{
+			name: "Valid PodOverrides",
+			podOverrides: &PodOverrides{
+				Labels:      map[string]string{"sidecar.istio.io/inject": "true"},
+				Annotations: map[string]string{"example.com/cost-center": "ml"},
+				Env:         []v1.EnvVar{{Name: "HF_HUB_OFFLINE", Value: "1"}},
+				Volumes: []v1.Volume{
+					{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+				},
+				VolumeMounts: []v1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+				SecurityContext: &v1.PodSecurityContext{
+					RunAsNonRoot: lo.ToPtr(true),
+				},
+				Resources: &PodOverridesResources{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("16Gi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
 				},
-				Strength: &ValidStrength,
 			},
-			errContent: "Name of Adapter field must be specified",
+			expectErrs: false,
+		},
+		{
+			name:         "Reserved Label",
+			podOverrides: &PodOverrides{Labels: map[string]string{LabelWorkspaceName: "other"}},
+			errContent:   "reserved for KAITO",
+			expectErrs:   true,
+		},
+		{
+			name:         "Invalid Annotation Key",
+			podOverrides: &PodOverrides{Annotations: map[string]string{"cost center": "ml"}},
+			errContent:   "invalid key name",
+			expectErrs:   true,
+		},
+		{
+			name:         "Invalid Env Name",
+			podOverrides: &PodOverrides{Env: []v1.EnvVar{{Name: "1INVALID"}}},
+			errContent:   "env[0].name",
+			expectErrs:   true,
+		},
+		{
+			name: "Reserved Volume Name",
+			podOverrides: &PodOverrides{
+				Volumes: []v1.Volume{{Name: "dshm", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}},
+			},
+			errContent: "Volume name dshm is reserved for KAITO",
 			expectErrs: true,
 		},
 		{
-			name: "Invalid Strength, not a number",
-			adapterSpec: &AdapterSpec{
-				Source: &DataSource{
-					Name:  "Adapter-1",
-					Image: "fake.kaito.com/kaito-image:0.0.1",
-				},
-				Strength: &InvalidStrength1,
+			name: "Reserved Mount Path",
+			podOverrides: &PodOverrides{
+				Volumes:      []v1.Volume{{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}},
+				VolumeMounts: []v1.VolumeMount{{Name: "cache", MountPath: "/dev/shm/"}},
 			},
-			errContent: "Invalid strength value for Adapter 'Adapter-1'",
+			errContent: "Mount path /dev/shm/ is reserved for KAITO",
 			expectErrs: true,
 		},
 		{
-			name: "Invalid Strength, larger than 1",
-			adapterSpec: &AdapterSpec{
-				Source: &DataSource{
-					Name:  "Adapter-1",
-					Image: "fake.kaito.com/kaito-image:0.0.1",
-				},
-				Strength: &InvalidStrength2,
+			name:         "Mount Of Unknown Volume",
+			podOverrides: &PodOverrides{VolumeMounts: []v1.VolumeMount{{Name: "config-volume", MountPath: "/config"}}},
+			errContent:   "Volume mount must refer to a volume in podOverrides",
+			expectErrs:   true,
+		},
+		{
+			name: "GPU Resource",
+			podOverrides: &PodOverrides{
+				Resources: &PodOverridesResources{Limits: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")}},
 			},
-			errContent: "Strength value for Adapter 'Adapter-1' must be between 0 and 1",
+			errContent: "only cpu, memory and ephemeral-storage can be overridden",
 			expectErrs: true,
 		},
 		{
-			name: "Invalid Source Name, longer than 253",
-			adapterSpec: &AdapterSpec{
-				Source: &DataSource{
-					Name:  invalidSourceName,
-					Image: "fake.kaito.com/kaito-image:0.0.1",
+			name: "Request Exceeds Limit",
+			podOverrides: &PodOverrides{
+				Resources: &PodOverridesResources{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("16Gi")},
 				},
-				Strength: &ValidStrength,
 			},
-			errContent: "invalid value",
+			errContent: "request of memory must not exceed its limit",
 			expectErrs: true,
 		},
 		{
-			name: "Valid Adapter",
-			adapterSpec: &AdapterSpec{
-				Source: &DataSource{
-					Name:  "adapter-1",
-					Image: "fake.kaito.com/kaito-image:0.0.1",
+			name: "Request Exceeds Default Limit",
+			podOverrides: &PodOverrides{
+				Resources: &PodOverridesResources{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
 				},
 			},
-			errContent: "",
-			expectErrs: false,
+			defaultLimits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("16Gi")},
+			errContent:    "request of memory must not exceed its limit 16Gi",
+			expectErrs:    true,
+		},
+		{
+			name: "Default Limit Overridden",
+			podOverrides: &PodOverrides{
+				Resources: &PodOverridesResources{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Gi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("64Gi")},
+				},
+			},
+			defaultLimits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("16Gi")},
+			expectErrs:    false,
 		},
 	}
 
-	// Run the tests
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			errs := tc.adapterSpec.validateCreateorUpdate()
+			errs := tc.podOverrides.validate(tc.defaultLimits)
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
-				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
+				t.Errorf("validate() errors = %v, expectErrs %v", errs, tc.expectErrs)
 			}
 
-			// If there is an error and errContent is not empty, check that the error contains the expected content.
 			if hasErrs && tc.errContent != "" {
 				errMsg := errs.Error()
 				if !strings.Contains(errMsg, tc.errContent) {
-					t.Errorf("validateUpdate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
+					t.Errorf("validate() error message = %v, expected to contain = %v", errMsg, tc.errContent)
 				}
 			}
 		})
 	}
 }
 
+func TestDefaultContainerLimits(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1.AddToScheme(scheme)
+	limitRange := func(name string, limitType v1.LimitType, memory string) *v1.LimitRange {
+		return &v1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1.LimitRangeSpec{
+				Limits: []v1.LimitRangeItem{{Type: limitType, Default: v1.ResourceList{v1.ResourceMemory: resource.MustParse(memory)}}},
+			},
+		}
+	}
+	k8sclient.SetGlobalClient(fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		limitRange("large", v1.LimitTypeContainer, "32Gi"),
+		limitRange("small", v1.LimitTypeContainer, "16Gi"),
+		limitRange("pod", v1.LimitTypePod, "8Gi"),
+	).Build())
+
+	overrides := &PodOverrides{
+		Resources: &PodOverridesResources{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("20Gi")}},
+	}
+	limits := defaultContainerLimits(context.Background(), "default", overrides)
+	if limit := limits[v1.ResourceMemory]; limit.Cmp(resource.MustParse("16Gi")) != 0 {
+		t.Errorf("defaultContainerLimits() memory = %s, expected 16Gi", limit.String())
+	}
+	if errs := overrides.validate(limits); errs == nil {
+		t.Errorf("validate() expected the request to exceed the default limit")
+	}
+
+	// The LimitRanges are only looked up when requests are overridden.
+	if limits := defaultContainerLimits(context.Background(), "default", &PodOverrides{}); limits != nil {
+		t.Errorf("defaultContainerLimits() = %v, expected nil", limits)
+	}
+}
+
 func TestInferenceSpecValidateUpdate(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -860,6 +2055,37 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 			errContent: "field is immutable",
 			expectErrs: true,
 		},
+		{
+			name: "Pod Overrides Changed",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta:   PresetMeta{Name: ModelName("test-validation")},
+					PodOverrides: &PodOverrides{Env: []v1.EnvVar{{Name: "HF_HUB_OFFLINE", Value: "1"}}},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{Name: ModelName("test-validation")},
+				},
+			},
+			expectErrs: false,
+		},
+		{
+			name: "Invalid Pod Overrides",
+			newInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta:   PresetMeta{Name: ModelName("test-validation")},
+					PodOverrides: &PodOverrides{Labels: map[string]string{LabelWorkspaceName: "other"}},
+				},
+			},
+			oldInference: &InferenceSpec{
+				Preset: &PresetSpec{
+					PresetMeta: PresetMeta{Name: ModelName("test-validation")},
+				},
+			},
+			errContent: "reserved for KAITO",
+			expectErrs: true,
+		},
 		{
 			name: "Template Unset",
 			newInference: &InferenceSpec{
@@ -922,7 +2148,7 @@ func TestInferenceSpecValidateUpdate(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			errs := tc.newInference.validateUpdate(tc.oldInference)
+			errs := tc.newInference.validateUpdate(context.Background(), "default", tc.oldInference)
 			hasErrs := errs != nil
 			if hasErrs != tc.expectErrs {
 				t.Errorf("validateUpdate() errors = %v, expectErrs %v", errs, tc.expectErrs)
@@ -1301,6 +2527,25 @@ func TestTuningSpecValidateUpdate(t *testing.T) {
 			expectErrs: true,
 			errFields:  []string{"Preset"},
 		},
+		{
+			name: "Pod overrides changed",
+			oldTuning: &TuningSpec{
+				Input:  &DataSource{Name: "input1"},
+				Output: &DataDestination{Image: "AZURE_ACR.azurecr.io/test:0.0.0"},
+				Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}},
+				Method: TuningMethodLora,
+			},
+			newTuning: &TuningSpec{
+				Input:  &DataSource{Name: "input1"},
+				Output: &DataDestination{Image: "AZURE_ACR.azurecr.io/test:0.0.0"},
+				Preset: &PresetSpec{
+					PresetMeta:   PresetMeta{Name: ModelName("test-validation")},
+					PodOverrides: &PodOverrides{Annotations: map[string]string{"example.com/cost-center": "ml"}},
+				},
+				Method: TuningMethodLora,
+			},
+			expectErrs: false,
+		},
 		{
 			name: "Method changed",
 			oldTuning: &TuningSpec{
@@ -1316,7 +2561,7 @@ func TestTuningSpecValidateUpdate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			errs := tt.newTuning.validateUpdate(tt.oldTuning)
+			errs := tt.newTuning.validateUpdate(context.Background(), "default", tt.oldTuning)
 			hasErrs := errs != nil
 
 			if hasErrs != tt.expectErrs {
@@ -1591,3 +2836,66 @@ func TestDataDestinationValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesInstanceTypePattern(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		instanceType string
+		expected     bool
+	}{
+		{name: "Azure N-series", provider: consts.AzureCloudName, instanceType: "Standard_NC80adis_H100_v5", expected: true},
+		{name: "Azure unknown series", provider: consts.AzureCloudName, instanceType: "Standard_E4s_v3", expected: false},
+		{name: "GCP A3 machine type", provider: consts.GCPCloudName, instanceType: "a3-ultragpu-8g", expected: true},
+		{name: "GCP G2 machine type", provider: consts.GCPCloudName, instanceType: "g2-standard-64", expected: true},
+		{name: "GCP general purpose machine type", provider: consts.GCPCloudName, instanceType: "n2-standard-8", expected: false},
+		{name: "Azure SKU on GCP", provider: consts.GCPCloudName, instanceType: "Standard_NC6s_v3", expected: false},
+		{name: "AWS has no pattern", provider: consts.AWSCloudName, instanceType: "p5e.48xlarge", expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchesInstanceTypePattern(tc.provider, tc.instanceType); got != tc.expected {
+				t.Errorf("matchesInstanceTypePattern(%s, %s) = %v, expected %v", tc.provider, tc.instanceType, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestResourceSpecValidateCreateWithCustomSKUs(t *testing.T) {
+	RegisterValidationTestModels()
+	t.Setenv("CLOUD_PROVIDER", consts.CustomCloudName)
+	sku.GetCustomSKUHandler().SetGPUConfigs(map[string]sku.GPUConfig{
+		"on-prem-a100": {SKU: "on-prem-a100", GPUCount: 4, GPUMem: 160, GPUModel: "NVIDIA A100"},
+	})
+	defer sku.GetCustomSKUHandler().SetGPUConfigs(map[string]sku.GPUConfig{})
+
+	gpuCountRequirement = "2"
+	totalGPUMemoryRequirement = "80Gi"
+	perGPUMemoryRequirement = "40Gi"
+	spec := &InferenceSpec{Preset: &PresetSpec{PresetMeta: PresetMeta{Name: ModelName("test-validation")}}}
+
+	tests := []struct {
+		name         string
+		instanceType string
+		errContent   string
+	}{
+		{name: "Instance type in the catalog", instanceType: "on-prem-a100"},
+		{name: "Instance type not in the catalog", instanceType: "on-prem-t4", errContent: "Unsupported instance type on-prem-t4"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &ResourceSpec{InstanceType: tc.instanceType, Count: pointerToInt(1)}
+			errs := r.validateCreateWithInference(spec)
+			if tc.errContent == "" {
+				if errs != nil {
+					t.Errorf("validateCreate() unexpected errors = %v", errs)
+				}
+				return
+			}
+			if errs == nil || !strings.Contains(errs.Error(), tc.errContent) {
+				t.Errorf("validateCreate() errors = %v, expected to contain = %v", errs, tc.errContent)
+			}
+		})
+	}
+}
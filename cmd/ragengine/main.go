@@ -19,6 +19,9 @@ import (
 
 	"github.com/kaito-project/kaito/pkg/ragengine/controllers"
 	"github.com/kaito-project/kaito/pkg/ragengine/webhooks"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/skucatalog"
 	"k8s.io/api/apps/v1beta1"
 	"k8s.io/klog/v2"
 	"knative.dev/pkg/injection/sharedmain"
@@ -129,6 +132,18 @@ func main() {
 		klog.ErrorS(err, "unable to create controller", "controller", "RAG Eingine")
 		exitWithErrorFunc()
 	}
+
+	if os.Getenv("CLOUD_PROVIDER") == consts.CustomCloudName {
+		namespace, err := utils.GetReleaseNamespace()
+		if err != nil {
+			klog.ErrorS(err, "unable to get release namespace")
+			exitWithErrorFunc()
+		}
+		if err = skucatalog.NewReconciler(kClient, namespace).SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "SKUCatalog")
+			exitWithErrorFunc()
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
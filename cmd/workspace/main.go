@@ -15,12 +15,21 @@ import (
 	awsv1beta1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/kaito-project/kaito/pkg/utils/skucatalog"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/pkg/workspace/activator"
 	"github.com/kaito-project/kaito/pkg/workspace/controllers"
 	"github.com/kaito-project/kaito/pkg/workspace/webhooks"
 	"k8s.io/klog/v2"
@@ -46,6 +55,7 @@ import (
 const (
 	WebhookServiceName = "WEBHOOK_SERVICE"
 	WebhookServicePort = "WEBHOOK_PORT"
+	PodIP              = "POD_IP"
 )
 
 var (
@@ -64,6 +74,7 @@ func init() {
 	utilruntime.Must(v1beta1.SchemeBuilder.AddToScheme(scheme))
 	utilruntime.Must(azurev1alpha2.SchemeBuilder.AddToScheme(scheme))
 	utilruntime.Must(awsv1beta1.SchemeBuilder.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1.AddToScheme(scheme))
 
 	//+kubebuilder:scaffold:scheme
 	klog.InitFlags(nil)
@@ -75,6 +86,7 @@ func main() {
 	var enableWebhook bool
 	var probeAddr string
 	var featureGates string
+	var activatorPort int
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -83,6 +95,7 @@ func main() {
 	flag.BoolVar(&enableWebhook, "webhook", true,
 		"Enable webhook for controller manager. Default is true.")
 	flag.StringVar(&featureGates, "feature-gates", "Karpenter=false", "Enable Kaito feature gates. Default,	Karpenter=false.")
+	flag.IntVar(&activatorPort, "activator-port", 8090, "The port the activator of idle workspaces binds to.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -112,6 +125,15 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+
+		// Only the EndpointSlices of the activator are read, the others are not cached.
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&discoveryv1.EndpointSlice{}: {
+					Label: labels.SelectorFromSet(labels.Set{discoveryv1.LabelManagedBy: manifests.ActivatorEndpointSliceManager}),
+				},
+			},
+		},
 	})
 	if err != nil {
 		klog.ErrorS(err, "unable to start manager")
@@ -132,6 +154,28 @@ func main() {
 		klog.ErrorS(err, "unable to create controller", "controller", "Workspace")
 		exitWithErrorFunc()
 	}
+
+	if os.Getenv("CLOUD_PROVIDER") == consts.CustomCloudName {
+		namespace, err := utils.GetReleaseNamespace()
+		if err != nil {
+			klog.ErrorS(err, "unable to get release namespace")
+			exitWithErrorFunc()
+		}
+		if err = skucatalog.NewReconciler(kClient, namespace).SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "SKUCatalog")
+			exitWithErrorFunc()
+		}
+	}
+
+	// The activator receives the requests sent to idle workspaces at the address of this pod.
+	if podIP := os.Getenv(PodIP); podIP != "" {
+		workspaceReconciler.ActivatorAddress = podIP
+		workspaceReconciler.ActivatorPort = int32(activatorPort)
+		if err = mgr.Add(activator.NewActivator(kClient, activatorPort)); err != nil {
+			klog.ErrorS(err, "unable to add activator")
+			exitWithErrorFunc()
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -14,8 +14,9 @@ import (
 var (
 	// FeatureGates is a map that holds	the feature gates and their default values for Kaito.
 	FeatureGates = map[string]bool{
-		consts.FeatureFlagKarpenter: false,
-		consts.FeatureFlagVLLM:      true,
+		consts.FeatureFlagKarpenter:           false,
+		consts.FeatureFlagVLLM:                true,
+		consts.FeatureFlagGPUFeatureDiscovery: false,
 		//	Add more feature gates here
 	}
 )
@@ -6,8 +6,11 @@ import (
 	"path"
 	"time"
 
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type Model interface {
@@ -39,6 +42,7 @@ type PresetParam struct {
 	PerGPUMemoryRequirement       string         // GPU memory required per GPU. Used for inference.
 	TuningPerGPUMemoryRequirement map[string]int // Min GPU memory per tuning method (batch size 1). Used for tuning.
 	WorldSize                     int            // Defines the number of processes required for distributed inference.
+	GPUVendors                    []string       // GPU vendors the model images are built for, only NVIDIA if empty.
 
 	RuntimeParam
 
@@ -86,6 +90,7 @@ func (p *PresetParam) DeepCopy() *PresetParam {
 	for k, v := range p.TuningPerGPUMemoryRequirement {
 		out.TuningPerGPUMemoryRequirement[k] = v
 	}
+	out.GPUVendors = append([]string(nil), p.GPUVendors...)
 	return out
 }
 
@@ -159,3 +164,36 @@ func (p *PresetParam) GetInferenceCommand(runtime RuntimeName, skuNumGPUs string
 		return nil
 	}
 }
+
+// GetGPURequirement returns the GPU requirement of the preset when it runs on nodeCount nodes. Requirements which
+// are not specified are zero.
+func (p *PresetParam) GetGPURequirement(nodeCount int) sku.GPURequirement {
+	parse := func(requirement string) resource.Quantity {
+		if requirement == "" {
+			return resource.Quantity{}
+		}
+		return resource.MustParse(requirement)
+	}
+	return sku.GPURequirement{
+		NodeCount:      nodeCount,
+		GPUCount:       parse(p.GPUCountRequirement),
+		PerGPUMemory:   parse(p.PerGPUMemoryRequirement),
+		TotalGPUMemory: parse(p.TotalGPUMemoryRequirement),
+	}
+}
+
+// SupportsGPUVendor returns whether the model images are built for the GPUs of the vendor.
+func (p *PresetParam) SupportsGPUVendor(vendor sku.GPUVendor) bool {
+	if len(p.GPUVendors) == 0 {
+		return vendor == sku.GPUVendorNvidia
+	}
+	return lo.Contains(p.GPUVendors, vendor.Name)
+}
+
+// GetImageTag returns the tag of the model image built for the GPUs of the vendor.
+func (p *PresetParam) GetImageTag(vendor sku.GPUVendor) string {
+	if vendor.ImageVariant == "" {
+		return p.Tag
+	}
+	return p.Tag + "-" + vendor.ImageVariant
+}
@@ -11,6 +11,7 @@ import (
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -55,7 +56,7 @@ var (
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Operator: corev1.TolerationOpExists,
-			Key:      resources.CapacityNvidiaGPU,
+			Key:      sku.GPUVendorNvidia.ResourceName,
 		},
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
@@ -89,10 +90,10 @@ func CreatePresetRAG(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine,
 
 		resourceReq = corev1.ResourceRequirements{
 			Requests: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+				corev1.ResourceName(sku.GPUVendorNvidia.ResourceName): resource.MustParse(skuNumGPUs),
 			},
 			Limits: corev1.ResourceList{
-				corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+				corev1.ResourceName(sku.GPUVendorNvidia.ResourceName): resource.MustParse(skuNumGPUs),
 			},
 		}
 
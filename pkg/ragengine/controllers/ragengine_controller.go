@@ -11,7 +11,6 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
@@ -19,6 +18,7 @@ import (
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/ragengine/manifests"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/machine"
@@ -401,7 +401,7 @@ func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEng
 	}
 
 	// Ensure all gpu plugins are running successfully.
-	if strings.Contains(ragEngineObj.Spec.Compute.InstanceType, consts.GpuSkuPrefix) { // GPU skus
+	if utils.GetGPUConfigBySKU(ragEngineObj.Spec.Compute.InstanceType) != nil { // GPU skus
 		for i := range selectedNodes {
 			err = c.ensureNodePlugins(ctx, ragEngineObj, selectedNodes[i])
 			if err != nil {
@@ -453,7 +453,11 @@ func (c *RAGEngineReconciler) applyRAGEngineResource(ctx context.Context, ragEng
 func (c *RAGEngineReconciler) getAllQualifiedNodes(ctx context.Context, ragEngineObj *kaitov1alpha1.RAGEngine) ([]*corev1.Node, error) {
 	var qualifiedNodes []*corev1.Node
 
-	nodeList, err := resources.ListNodes(ctx, c.Client, ragEngineObj.Spec.Compute.LabelSelector.MatchLabels)
+	nodeSelector, err := resources.GetNodeSelector(ragEngineObj.Spec.Compute.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := resources.ListNodes(ctx, c.Client, nodeSelector)
 	if err != nil {
 		return nil, err
 	}
@@ -594,8 +598,8 @@ func (c *RAGEngineReconciler) ensureNodePlugins(ctx context.Context, ragEngineOb
 			return fmt.Errorf("node plugin installation timed out. node %s is not ready", nodeObj.Name)
 		default:
 			//Nvidia Plugin
-			if found := resources.CheckNvidiaPlugin(ctx, nodeObj); !found {
-				if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client); err != nil {
+			if found := resources.CheckGPUPlugin(ctx, nodeObj, sku.GPUVendorNvidia); !found {
+				if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyAccelerator, sku.GPUVendorNvidia.Name, c.Client); err != nil {
 					if apierrors.IsNotFound(err) {
 						klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", nodeObj.Name)
 						if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
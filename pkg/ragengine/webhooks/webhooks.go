@@ -11,6 +11,7 @@ import (
 	knativeinjection "knative.dev/pkg/injection"
 	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
@@ -19,10 +20,21 @@ import (
 func NewRAGEngineWebhooks() []knativeinjection.ControllerConstructor {
 	return []knativeinjection.ControllerConstructor{
 		certificates.NewController,
+		NewRAGEngineCRDDefaultingWebhook,
 		NewRAGEngineCRDValidationWebhook,
 	}
 }
 
+func NewRAGEngineCRDDefaultingWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return defaulting.NewAdmissionController(ctx,
+		"defaulting.ragengine.kaito.sh",
+		"/default/ragengine.kaito.sh",
+		RAGEngineResources,
+		func(ctx context.Context) context.Context { return ctx },
+		true,
+	)
+}
+
 func NewRAGEngineCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
 		"validation.ragengine.kaito.sh",
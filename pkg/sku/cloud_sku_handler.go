@@ -17,6 +17,11 @@ type GPUConfig struct {
 	GPUCount int
 	GPUMem   int
 	GPUModel string
+	// Price is a relative cost weight which ranks the instance types when one is selected automatically. The built-in
+	// SKU tables use approximate on-demand hourly list prices in USD, which are not kept up to date: only their order
+	// matters. The prices of custom SKUs come from the SKU catalog ConfigMap. Instance types without a price are
+	// never selected automatically.
+	Price float64
 }
 
 func GetCloudSKUHandler(cloud string) CloudSKUHandler {
@@ -25,6 +30,10 @@ func GetCloudSKUHandler(cloud string) CloudSKUHandler {
 		return NewAzureSKUHandler()
 	case consts.AWSCloudName:
 		return NewAwsSKUHandler()
+	case consts.GCPCloudName:
+		return NewGCPSKUHandler()
+	case consts.CustomCloudName:
+		return GetCustomSKUHandler()
 	default:
 		return nil
 	}
@@ -4,7 +4,11 @@
 package sku
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestAzureSKUHandler(t *testing.T) {
@@ -62,3 +66,279 @@ func TestAwsSKUHandler(t *testing.T) {
 		t.Errorf("Unsupported SKU found in GPUConfigs")
 	}
 }
+
+func TestGCPSKUHandler(t *testing.T) {
+	handler := NewGCPSKUHandler()
+
+	// Test GetSupportedSKUs
+	skus := handler.GetSupportedSKUs()
+	if len(skus) == 0 {
+		t.Errorf("GetSupportedSKUs returned an empty array")
+	}
+
+	// Test GetGPUConfigs with a SKU that is supported
+	sku := "a2-highgpu-1g"
+	configMap := handler.GetGPUConfigs()
+	config, exists := configMap[sku]
+	if !exists {
+		t.Errorf("Supported SKU missing from GPUConfigs")
+	}
+	if config.SKU != sku {
+		t.Errorf("Incorrect config returned for a supported SKU")
+	}
+
+	// Test GetGPUConfigs with a SKU that is not supported
+	sku = "n1-standard-4"
+	config, exists = configMap[sku]
+	if exists {
+		t.Errorf("Unsupported SKU found in GPUConfigs")
+	}
+}
+
+func TestSelectInstanceType(t *testing.T) {
+	testcases := map[string]struct {
+		handler          CloudSKUHandler
+		requirement      GPURequirement
+		expectedSKU      string
+		expectedUnpriced []string
+		expectedFail     bool
+	}{
+		"Cheapest NVIDIA SKU with a single small GPU": {
+			handler: NewAzureSKUHandler(),
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("16Gi"),
+				TotalGPUMemory: resource.MustParse("16Gi"),
+			},
+			expectedSKU: "Standard_NC4as_T4_v3",
+		},
+		"Cheapest SKU with an 80Gi GPU": {
+			handler: NewAzureSKUHandler(),
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("80Gi"),
+				TotalGPUMemory: resource.MustParse("80Gi"),
+			},
+			expectedSKU: "Standard_NC24ads_A100_v4",
+		},
+		"Cheapest GCP SKU with a single L4 GPU": {
+			handler: NewGCPSKUHandler(),
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("20Gi"),
+				TotalGPUMemory: resource.MustParse("20Gi"),
+			},
+			expectedSKU: "g2-standard-4",
+		},
+		"Requirement is shared by multiple nodes": {
+			handler: NewAwsSKUHandler(),
+			requirement: GPURequirement{
+				NodeCount:      2,
+				GPUCount:       resource.MustParse("2"),
+				PerGPUMemory:   resource.MustParse("20Gi"),
+				TotalGPUMemory: resource.MustParse("40Gi"),
+			},
+			expectedSKU: "g6.xlarge",
+		},
+		"SKUs without a price are reported": {
+			handler: &CustomSKUHandler{supportedSKUs: map[string]GPUConfig{
+				"priced":   {SKU: "priced", GPUCount: 1, GPUMem: 80, GPUModel: "NVIDIA A100", Price: 3},
+				"unpriced": {SKU: "unpriced", GPUCount: 1, GPUMem: 80, GPUModel: "NVIDIA H100"},
+			}},
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("40Gi"),
+				TotalGPUMemory: resource.MustParse("40Gi"),
+			},
+			expectedSKU:      "priced",
+			expectedUnpriced: []string{"unpriced"},
+		},
+		"Only SKUs without a price meet the requirement": {
+			handler: &CustomSKUHandler{supportedSKUs: map[string]GPUConfig{
+				"unpriced": {SKU: "unpriced", GPUCount: 1, GPUMem: 80, GPUModel: "NVIDIA H100"},
+			}},
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("40Gi"),
+				TotalGPUMemory: resource.MustParse("40Gi"),
+			},
+			expectedUnpriced: []string{"unpriced"},
+			expectedFail:     true,
+		},
+		"No SKU meets the requirement": {
+			handler: NewAzureSKUHandler(),
+			requirement: GPURequirement{
+				NodeCount:      1,
+				GPUCount:       resource.MustParse("1"),
+				PerGPUMemory:   resource.MustParse("200Gi"),
+				TotalGPUMemory: resource.MustParse("200Gi"),
+			},
+			expectedFail: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			config, unpriced, err := SelectInstanceType(tc.handler, tc.requirement)
+			if !reflect.DeepEqual(unpriced, tc.expectedUnpriced) {
+				t.Errorf("Reported %v without a price, expected %v", unpriced, tc.expectedUnpriced)
+			}
+			if tc.expectedFail {
+				if err == nil {
+					t.Errorf("Expected an error, got %s", config.SKU)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if config.SKU != tc.expectedSKU {
+				t.Errorf("Selected %s, expected %s", config.SKU, tc.expectedSKU)
+			}
+		})
+	}
+}
+
+func TestGetGPUVendor(t *testing.T) {
+	configs := NewAzureSKUHandler().GetGPUConfigs()
+
+	if vendor := GetGPUVendor(configs["Standard_NC6s_v3"].GPUModel); vendor != GPUVendorNvidia {
+		t.Errorf("Expected vendor %s for an NVIDIA GPU, got %s", GPUVendorNvidia.Name, vendor.Name)
+	}
+	if vendor := GetGPUVendor(configs["Standard_NG32ads_V620_v1"].GPUModel); vendor != GPUVendorAMD {
+		t.Errorf("Expected vendor %s for an AMD GPU, got %s", GPUVendorAMD.Name, vendor.Name)
+	}
+	if vendor := GetGPUVendor(""); vendor != GPUVendorNvidia {
+		t.Errorf("Expected vendor %s for an unknown GPU, got %s", GPUVendorNvidia.Name, vendor.Name)
+	}
+}
+
+func TestParseCustomSKUs(t *testing.T) {
+	testcases := map[string]struct {
+		data         string
+		expectedSKUs map[string]GPUConfig
+		expectedErr  string
+	}{
+		"Valid catalog": {
+			data: `
+- instanceType: dgx-a100
+  gpuCount: 8
+  gpuMemGiB: 640
+  gpuModel: NVIDIA A100
+  price: 30
+- instanceType: mi210-node
+  gpuCount: 2
+  gpuMemGiB: 128
+  gpuModel: AMD Instinct MI210
+`,
+			expectedSKUs: map[string]GPUConfig{
+				"dgx-a100":   {SKU: "dgx-a100", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA A100", Price: 30},
+				"mi210-node": {SKU: "mi210-node", GPUCount: 2, GPUMem: 128, GPUModel: "AMD Instinct MI210"},
+			},
+		},
+		"Empty catalog": {
+			expectedSKUs: map[string]GPUConfig{},
+		},
+		"Unknown field": {
+			data:        "- instanceType: dgx-a100\n  gpus: 8\n",
+			expectedErr: "failed to parse SKU catalog",
+		},
+		"Missing instance type": {
+			data:        "- gpuCount: 8\n  gpuMemGiB: 640\n",
+			expectedErr: "instanceType must be specified",
+		},
+		"Duplicate instance type": {
+			data:        "- {instanceType: a, gpuCount: 1, gpuMemGiB: 16}\n- {instanceType: a, gpuCount: 1, gpuMemGiB: 16}\n",
+			expectedErr: "instance type is specified more than once",
+		},
+		"Missing GPU count": {
+			data:        "- {instanceType: a, gpuMemGiB: 16}\n",
+			expectedErr: "gpuCount and gpuMemGiB must be positive",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			skus, err := ParseCustomSKUs(tc.data)
+			if tc.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(skus, tc.expectedSKUs) {
+				t.Errorf("Expected SKUs %v, got %v", tc.expectedSKUs, skus)
+			}
+		})
+	}
+}
+
+func TestGetGPUConfigFromNodeLabels(t *testing.T) {
+	testcases := map[string]struct {
+		labels        map[string]string
+		expected      GPUConfig
+		expectedFound bool
+	}{
+		"Labeled node": {
+			labels:        map[string]string{LabelGPUCount: "4", LabelGPUMemory: "40960", LabelGPUProduct: "NVIDIA-A100-SXM4-40GB"},
+			expected:      GPUConfig{SKU: "node-sku", GPUCount: 4, GPUMem: 160, GPUModel: "NVIDIA A100 SXM4 40GB"},
+			expectedFound: true,
+		},
+		"Product without vendor prefix": {
+			labels:        map[string]string{LabelGPUCount: "1", LabelGPUMemory: "15360", LabelGPUProduct: "Tesla-T4"},
+			expected:      GPUConfig{SKU: "node-sku", GPUCount: 1, GPUMem: 15, GPUModel: "NVIDIA Tesla T4"},
+			expectedFound: true,
+		},
+		"Missing memory label": {
+			labels: map[string]string{LabelGPUCount: "1"},
+		},
+		"Invalid GPU count": {
+			labels: map[string]string{LabelGPUCount: "many", LabelGPUMemory: "15360"},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			config, found := GetGPUConfigFromNodeLabels("node-sku", tc.labels)
+			if found != tc.expectedFound || config != tc.expected {
+				t.Errorf("Expected %v, %v, got %v, %v", tc.expected, tc.expectedFound, config, found)
+			}
+		})
+	}
+}
+
+func TestGetMIGProfileMemory(t *testing.T) {
+	tests := []struct {
+		profile   string
+		expected  int
+		expectErr bool
+	}{
+		{profile: "1g.10gb", expected: 10},
+		{profile: "3g.40gb", expected: 40},
+		{profile: "1g.10gb+me", expectErr: true},
+		{profile: "10gb", expectErr: true},
+		{profile: "", expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.profile, func(t *testing.T) {
+			memory, err := GetMIGProfileMemory(tc.profile)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("GetMIGProfileMemory() error = %v, expectErr %v", err, tc.expectErr)
+			}
+			if memory != tc.expected {
+				t.Errorf("GetMIGProfileMemory() = %d, expected %d", memory, tc.expected)
+			}
+		})
+	}
+	if name := MIGResourceName("1g.10gb"); name != "nvidia.com/mig-1g.10gb" {
+		t.Errorf("MIGResourceName() = %s, expected nvidia.com/mig-1g.10gb", name)
+	}
+}
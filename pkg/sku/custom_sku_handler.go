@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"fmt"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+var _ CloudSKUHandler = &CustomSKUHandler{}
+
+// CustomSKUHandler serves a user-defined SKU catalog, for on-prem and BYO GPU clusters whose instance types are not
+// known to any cloud provider. The catalog is loaded from a ConfigMap and is replaced whenever the ConfigMap changes.
+type CustomSKUHandler struct {
+	mu            sync.RWMutex
+	supportedSKUs map[string]GPUConfig
+}
+
+// customSKUHandler is shared by the controllers and the webhooks running in the same process.
+var customSKUHandler = &CustomSKUHandler{supportedSKUs: map[string]GPUConfig{}}
+
+func GetCustomSKUHandler() *CustomSKUHandler {
+	return customSKUHandler
+}
+
+func (c *CustomSKUHandler) GetSupportedSKUs() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return GetMapKeys(c.supportedSKUs)
+}
+
+func (c *CustomSKUHandler) GetGPUConfigs() map[string]GPUConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// The map is replaced as a whole and never modified, so it can be returned without copying.
+	return c.supportedSKUs
+}
+
+// SetGPUConfigs replaces the SKU catalog.
+func (c *CustomSKUHandler) SetGPUConfigs(configs map[string]GPUConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.supportedSKUs = configs
+}
+
+// customSKU is an entry of the SKU catalog ConfigMap.
+type customSKU struct {
+	// InstanceType is the value of the node.kubernetes.io/instance-type label of the nodes.
+	InstanceType string `yaml:"instanceType"`
+	GPUCount     int    `yaml:"gpuCount"`
+	// GPUMemGiB is the total GPU memory of a node in GiB.
+	GPUMemGiB int    `yaml:"gpuMemGiB"`
+	GPUModel  string `yaml:"gpuModel"`
+	// Price is optional, instance types without a price are never selected automatically.
+	Price float64 `yaml:"price,omitempty"`
+}
+
+// ParseCustomSKUs parses the SKU catalog, a YAML list of instance types with their GPU count, GPU memory and GPU model.
+func ParseCustomSKUs(data string) (map[string]GPUConfig, error) {
+	var skus []customSKU
+	if err := yaml.UnmarshalStrict([]byte(data), &skus); err != nil {
+		return nil, fmt.Errorf("failed to parse SKU catalog: %w", err)
+	}
+
+	configs := make(map[string]GPUConfig, len(skus))
+	for i, s := range skus {
+		if s.InstanceType == "" {
+			return nil, fmt.Errorf("SKU %d: instanceType must be specified", i)
+		}
+		if _, exists := configs[s.InstanceType]; exists {
+			return nil, fmt.Errorf("SKU %s: instance type is specified more than once", s.InstanceType)
+		}
+		if s.GPUCount <= 0 || s.GPUMemGiB <= 0 {
+			return nil, fmt.Errorf("SKU %s: gpuCount and gpuMemGiB must be positive", s.InstanceType)
+		}
+		configs[s.InstanceType] = GPUConfig{
+			SKU:      s.InstanceType,
+			GPUCount: s.GPUCount,
+			GPUMem:   s.GPUMemGiB,
+			GPUModel: s.GPUModel,
+			Price:    s.Price,
+		}
+	}
+	return configs, nil
+}
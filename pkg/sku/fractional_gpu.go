@@ -0,0 +1,38 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// LabelMIGConfig is the node label read by the MIG manager of the NVIDIA GPU operator to partition the GPUs of the node.
+	LabelMIGConfig = "nvidia.com/mig.config"
+)
+
+// migProfilePattern matches the MIG profiles of the NVIDIA GPUs, e.g. 1g.10gb or 3g.40gb.
+var migProfilePattern = regexp.MustCompile(`^([1-7])g\.([0-9]+)gb$`)
+
+// MIGResourceName returns the extended resource advertised by the NVIDIA device plugin for the GPU slices of the
+// MIG profile when the mixed MIG strategy is used, e.g. nvidia.com/mig-1g.10gb.
+func MIGResourceName(profile string) string {
+	return "nvidia.com/mig-" + profile
+}
+
+// MIGConfig returns the value of the MIG config label which partitions all the GPUs of a node with the MIG profile.
+func MIGConfig(profile string) string {
+	return "all-" + profile
+}
+
+// GetMIGProfileMemory returns the GPU memory in GiB of a slice of the MIG profile.
+func GetMIGProfileMemory(profile string) (int, error) {
+	matches := migProfilePattern.FindStringSubmatch(profile)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid MIG profile %s, expected a profile like 1g.10gb", profile)
+	}
+	return strconv.Atoi(matches[2])
+}
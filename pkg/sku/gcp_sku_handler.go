@@ -0,0 +1,45 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+var _ CloudSKUHandler = &GCPSKUHandler{}
+
+type GCPSKUHandler struct {
+	supportedSKUs map[string]GPUConfig
+}
+
+func NewGCPSKUHandler() *GCPSKUHandler {
+	return &GCPSKUHandler{
+		// Reference: https://cloud.google.com/compute/docs/accelerator-optimized-machines
+		supportedSKUs: map[string]GPUConfig{
+			"a2-highgpu-1g":  {SKU: "a2-highgpu-1g", GPUCount: 1, GPUMem: 40, GPUModel: "NVIDIA A100", Price: 3.673},
+			"a2-highgpu-2g":  {SKU: "a2-highgpu-2g", GPUCount: 2, GPUMem: 80, GPUModel: "NVIDIA A100", Price: 7.347},
+			"a2-highgpu-4g":  {SKU: "a2-highgpu-4g", GPUCount: 4, GPUMem: 160, GPUModel: "NVIDIA A100", Price: 14.694},
+			"a2-highgpu-8g":  {SKU: "a2-highgpu-8g", GPUCount: 8, GPUMem: 320, GPUModel: "NVIDIA A100", Price: 29.387},
+			"a2-megagpu-16g": {SKU: "a2-megagpu-16g", GPUCount: 16, GPUMem: 640, GPUModel: "NVIDIA A100", Price: 55.74},
+			"a2-ultragpu-1g": {SKU: "a2-ultragpu-1g", GPUCount: 1, GPUMem: 80, GPUModel: "NVIDIA A100 80GB", Price: 5.069},
+			"a2-ultragpu-2g": {SKU: "a2-ultragpu-2g", GPUCount: 2, GPUMem: 160, GPUModel: "NVIDIA A100 80GB", Price: 10.138},
+			"a2-ultragpu-4g": {SKU: "a2-ultragpu-4g", GPUCount: 4, GPUMem: 320, GPUModel: "NVIDIA A100 80GB", Price: 20.276},
+			"a2-ultragpu-8g": {SKU: "a2-ultragpu-8g", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA A100 80GB", Price: 40.552},
+			"a3-highgpu-8g":  {SKU: "a3-highgpu-8g", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA H100", Price: 88.254},
+			"a3-megagpu-8g":  {SKU: "a3-megagpu-8g", GPUCount: 8, GPUMem: 640, GPUModel: "NVIDIA H100 Mega", Price: 92.22},
+			"g2-standard-4":  {SKU: "g2-standard-4", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4", Price: 0.707},
+			"g2-standard-8":  {SKU: "g2-standard-8", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4", Price: 0.854},
+			"g2-standard-12": {SKU: "g2-standard-12", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4", Price: 1.0},
+			"g2-standard-16": {SKU: "g2-standard-16", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4", Price: 1.147},
+			"g2-standard-24": {SKU: "g2-standard-24", GPUCount: 2, GPUMem: 48, GPUModel: "NVIDIA L4", Price: 2.0},
+			"g2-standard-32": {SKU: "g2-standard-32", GPUCount: 1, GPUMem: 24, GPUModel: "NVIDIA L4", Price: 1.733},
+			"g2-standard-48": {SKU: "g2-standard-48", GPUCount: 4, GPUMem: 96, GPUModel: "NVIDIA L4", Price: 4.0},
+			"g2-standard-96": {SKU: "g2-standard-96", GPUCount: 8, GPUMem: 192, GPUModel: "NVIDIA L4", Price: 8.0},
+		},
+	}
+}
+
+func (g *GCPSKUHandler) GetSupportedSKUs() []string {
+	return GetMapKeys(g.supportedSKUs)
+}
+
+func (g *GCPSKUHandler) GetGPUConfigs() map[string]GPUConfig {
+	return g.supportedSKUs
+}
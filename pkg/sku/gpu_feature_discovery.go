@@ -0,0 +1,42 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Labels set on the nodes by NVIDIA GPU Feature Discovery.
+const (
+	LabelGPUCount = "nvidia.com/gpu.count"
+	// LabelGPUMemory is the memory of each GPU in MiB.
+	LabelGPUMemory  = "nvidia.com/gpu.memory"
+	LabelGPUProduct = "nvidia.com/gpu.product"
+)
+
+// GetGPUConfigFromNodeLabels returns the GPU configuration of a node of the instance type from its NVIDIA GPU Feature
+// Discovery labels. It returns false if the node is not labeled.
+func GetGPUConfigFromNodeLabels(instanceType string, labels map[string]string) (GPUConfig, bool) {
+	gpuCount, err := strconv.Atoi(labels[LabelGPUCount])
+	if err != nil || gpuCount <= 0 {
+		return GPUConfig{}, false
+	}
+	perGPUMemMiB, err := strconv.Atoi(labels[LabelGPUMemory])
+	if err != nil || perGPUMemMiB <= 0 {
+		return GPUConfig{}, false
+	}
+
+	// The product label looks like NVIDIA-A100-SXM4-80GB or Tesla-T4.
+	gpuModel := strings.ReplaceAll(labels[LabelGPUProduct], "-", " ")
+	if !strings.HasPrefix(strings.ToUpper(gpuModel), "NVIDIA") {
+		gpuModel = strings.TrimSpace("NVIDIA " + gpuModel)
+	}
+	return GPUConfig{
+		SKU:      instanceType,
+		GPUCount: gpuCount,
+		GPUMem:   gpuCount * perGPUMemMiB / 1024,
+		GPUModel: gpuModel,
+	}, true
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package sku
+
+import (
+	"strings"
+)
+
+// GPUVendor describes how the GPUs of a vendor are exposed to the workloads.
+type GPUVendor struct {
+	// Name is the vendor name, it is also the value of the accelerator label of the nodes.
+	Name string
+	// ResourceName is the extended resource advertised by the device plugin of the vendor.
+	ResourceName string
+	// ImageVariant is the suffix of the preset image tags built for the software stack of the vendor.
+	// It is empty for the default images, which are built with CUDA.
+	ImageVariant string
+}
+
+var (
+	GPUVendorNvidia = GPUVendor{Name: "nvidia", ResourceName: "nvidia.com/gpu"}
+	GPUVendorAMD    = GPUVendor{Name: "amd", ResourceName: "amd.com/gpu", ImageVariant: "rocm"}
+
+	// GPUVendors lists all the supported GPU vendors.
+	GPUVendors = []GPUVendor{GPUVendorNvidia, GPUVendorAMD}
+)
+
+// GetGPUVendor returns the vendor of the GPU model. NVIDIA is assumed for unknown GPU models.
+func GetGPUVendor(gpuModel string) GPUVendor {
+	if strings.HasPrefix(strings.ToUpper(gpuModel), "AMD") {
+		return GPUVendorAMD
+	}
+	return GPUVendorNvidia
+}
@@ -3,6 +3,15 @@
 
 package sku
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
 func GetMapKeys(m map[string]GPUConfig) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -10,3 +19,62 @@ func GetMapKeys(m map[string]GPUConfig) []string {
 	}
 	return keys
 }
+
+// GPURequirement is the GPU requirement of a workload running on a number of nodes.
+type GPURequirement struct {
+	NodeCount      int
+	GPUCount       resource.Quantity
+	PerGPUMemory   resource.Quantity
+	TotalGPUMemory resource.Quantity
+}
+
+// MeetsGPURequirement returns whether nodes of the given instance type provide enough GPUs and GPU memory.
+func MeetsGPURequirement(config GPUConfig, requirement GPURequirement) bool {
+	if config.GPUCount == 0 {
+		return false
+	}
+	totalNumGPUs := resource.NewQuantity(int64(requirement.NodeCount*config.GPUCount), resource.DecimalSI)
+	perGPUMemory := resource.NewQuantity(int64(config.GPUMem/config.GPUCount)*consts.GiBToBytes, resource.BinarySI)
+	totalGPUMemory := resource.NewQuantity(int64(requirement.NodeCount*config.GPUMem)*consts.GiBToBytes, resource.BinarySI)
+	return totalNumGPUs.Cmp(requirement.GPUCount) >= 0 &&
+		perGPUMemory.Cmp(requirement.PerGPUMemory) >= 0 &&
+		totalGPUMemory.Cmp(requirement.TotalGPUMemory) >= 0
+}
+
+// SelectInstanceType returns the cheapest instance type with NVIDIA GPUs which meets the requirement. Instance types
+// with the same price are ranked by the number of GPUs and then by name so that the result is stable. The instance
+// types which meet the requirement but have no price cannot be ranked, they are returned so that the caller can
+// report them.
+func SelectInstanceType(handler CloudSKUHandler, requirement GPURequirement) (GPUConfig, []string, error) {
+	var candidates []GPUConfig
+	var unpriced []string
+	for _, config := range handler.GetGPUConfigs() {
+		if !strings.HasPrefix(config.GPUModel, "NVIDIA") || !MeetsGPURequirement(config, requirement) {
+			continue
+		}
+		if config.Price <= 0 {
+			unpriced = append(unpriced, config.SKU)
+			continue
+		}
+		candidates = append(candidates, config)
+	}
+	sort.Strings(unpriced)
+	if len(candidates) == 0 {
+		if len(unpriced) > 0 {
+			return GPUConfig{}, unpriced, fmt.Errorf("instance types %s meet the requirement but have no price to rank them",
+				strings.Join(unpriced, ", "))
+		}
+		return GPUConfig{}, nil, fmt.Errorf("no supported instance type provides %s GPUs with %s memory per GPU and %s in total on %d node(s)",
+			requirement.GPUCount.String(), requirement.PerGPUMemory.String(), requirement.TotalGPUMemory.String(), requirement.NodeCount)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Price != candidates[j].Price {
+			return candidates[i].Price < candidates[j].Price
+		}
+		if candidates[i].GPUCount != candidates[j].GPUCount {
+			return candidates[i].GPUCount < candidates[j].GPUCount
+		}
+		return candidates[i].SKU < candidates[j].SKU
+	})
+	return candidates[0], unpriced, nil
+}
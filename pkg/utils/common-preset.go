@@ -13,15 +13,30 @@ const (
 	DefaultAdapterVolumePath  = "/mnt/adapter"
 )
 
+const (
+	ResultsVolumeName      = "results-volume"
+	DockerConfigVolumeName = "docker-config"
+	SHMVolumeName          = "dshm"
+	ConfigVolumeName       = "config-volume"
+	DataVolumeName         = "data-volume"
+	AdapterVolumeName      = "adapter-volume"
+)
+
+// ReservedVolumeNames are the names of the volumes generated for preset workloads, they cannot be used by other volumes.
+var ReservedVolumeNames = []string{ResultsVolumeName, DockerConfigVolumeName, SHMVolumeName, ConfigVolumeName, DataVolumeName, AdapterVolumeName}
+
+// ReservedMountPaths are the mount paths of the volumes generated for preset workloads, they cannot be used by other volumes.
+var ReservedMountPaths = []string{DefaultVolumeMountPath, DefaultConfigMapMountPath, DefaultDataVolumePath, DefaultAdapterVolumePath}
+
 func ConfigResultsVolume(outputPath string) (corev1.Volume, corev1.VolumeMount) {
 	sharedWorkspaceVolume := corev1.Volume{
-		Name: "results-volume",
+		Name: ResultsVolumeName,
 		VolumeSource: corev1.VolumeSource{
 			EmptyDir: &corev1.EmptyDirVolumeSource{},
 		},
 	}
 	sharedVolumeMount := corev1.VolumeMount{
-		Name:      "results-volume",
+		Name:      ResultsVolumeName,
 		MountPath: outputPath,
 	}
 	return sharedWorkspaceVolume, sharedVolumeMount
@@ -29,7 +44,7 @@ func ConfigResultsVolume(outputPath string) (corev1.Volume, corev1.VolumeMount)
 
 func ConfigImagePushSecretVolume(imagePushSecret string) (corev1.Volume, corev1.VolumeMount) {
 	volume := corev1.Volume{
-		Name: "docker-config",
+		Name: DockerConfigVolumeName,
 		VolumeSource: corev1.VolumeSource{
 			Projected: &corev1.ProjectedVolumeSource{
 				Sources: []corev1.VolumeProjection{
@@ -52,7 +67,7 @@ func ConfigImagePushSecretVolume(imagePushSecret string) (corev1.Volume, corev1.
 	}
 
 	volumeMount := corev1.VolumeMount{
-		Name:      "docker-config",
+		Name:      DockerConfigVolumeName,
 		MountPath: "/tmp/.docker/config",
 	}
 
@@ -67,7 +82,7 @@ func ConfigSHMVolume(instanceCount int) (corev1.Volume, corev1.VolumeMount) {
 	if instanceCount > 1 {
 		// Append share memory volume to any existing volumes
 		volume = corev1.Volume{
-			Name: "dshm",
+			Name: SHMVolumeName,
 			VolumeSource: corev1.VolumeSource{
 				EmptyDir: &corev1.EmptyDirVolumeSource{
 					Medium: "Memory",
@@ -86,7 +101,7 @@ func ConfigSHMVolume(instanceCount int) (corev1.Volume, corev1.VolumeMount) {
 
 func ConfigCMVolume(cmName string) (corev1.Volume, corev1.VolumeMount) {
 	volume := corev1.Volume{
-		Name: "config-volume",
+		Name: ConfigVolumeName,
 		VolumeSource: corev1.VolumeSource{
 			ConfigMap: &corev1.ConfigMapVolumeSource{
 				LocalObjectReference: corev1.LocalObjectReference{
@@ -119,12 +134,12 @@ func ConfigDataVolume(hostPath *string) (corev1.Volume, corev1.VolumeMount) {
 		}
 	}
 	volume = corev1.Volume{
-		Name:         "data-volume",
+		Name:         DataVolumeName,
 		VolumeSource: volumeSource,
 	}
 
 	volumeMount = corev1.VolumeMount{
-		Name:      "data-volume",
+		Name:      DataVolumeName,
 		MountPath: DefaultDataVolumePath,
 	}
 	return volume, volumeMount
@@ -139,12 +154,12 @@ func ConfigAdapterVolume() (corev1.Volume, corev1.VolumeMount) {
 	}
 
 	volume = corev1.Volume{
-		Name:         "adapter-volume",
+		Name:         AdapterVolumeName,
 		VolumeSource: volumeSource,
 	}
 
 	volumeMount = corev1.VolumeMount{
-		Name:      "adapter-volume",
+		Name:      AdapterVolumeName,
 		MountPath: DefaultAdapterVolumePath,
 	}
 	return volume, volumeMount
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
 	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"gopkg.in/yaml.v2"
@@ -68,10 +69,18 @@ func MergeConfigMaps(baseMap, overrideMap map[string]string) map[string]string {
 	return merged
 }
 
+// BuildCmdStr appends the parameters to the base command. The parameters of each map are sorted by name, so that
+// the command of a workload does not change between reconciliations.
 func BuildCmdStr(baseCommand string, runParams ...map[string]string) string {
 	updatedBaseCommand := baseCommand
 	for _, runParam := range runParams {
-		for key, value := range runParam {
+		keys := make([]string, 0, len(runParam))
+		for key := range runParam {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := runParam[key]
 			if value == "" {
 				updatedBaseCommand = fmt.Sprintf("%s --%s", updatedBaseCommand, key)
 			} else {
@@ -120,9 +129,35 @@ func GetSKUHandler() (sku.CloudSKUHandler, error) {
 		return nil, apis.ErrInvalidValue(fmt.Sprintf("Unsupported cloud provider %s", provider), "CLOUD_PROVIDER")
 	}
 
+	// The GPUs of the existing nodes take precedence over the SKU table when they can be discovered.
+	if featuregates.FeatureGates[consts.FeatureFlagGPUFeatureDiscovery] && k8sclient.Client != nil {
+		return &nodeDiscoverySKUHandler{kubeClient: k8sclient.Client, fallback: skuHandler}, nil
+	}
 	return skuHandler, nil
 }
 
+// GetGPUVendor returns the vendor of the GPUs of the instance type. NVIDIA is assumed for instance types which are not
+// in the SKU list of the cloud provider.
+func GetGPUVendor(instanceType string) sku.GPUVendor {
+	if skuConfig := GetGPUConfigBySKU(instanceType); skuConfig != nil {
+		return sku.GetGPUVendor(skuConfig.GPUModel)
+	}
+	return sku.GPUVendorNvidia
+}
+
+// GetGPUConfigBySKU returns the GPU configuration of the instance type in the SKU list of the cloud provider, or nil
+// if the instance type is unknown.
+func GetGPUConfigBySKU(instanceType string) *sku.GPUConfig {
+	skuHandler, err := GetSKUHandler()
+	if err != nil {
+		return nil
+	}
+	if skuConfig, exists := skuHandler.GetGPUConfigs()[instanceType]; exists {
+		return &skuConfig
+	}
+	return nil
+}
+
 func GetSKUNumGPUs(ctx context.Context, kubeClient client.Client, workerNodes []string, instanceType, defaultGPUCount string) (string, error) {
 	skuHandler, err := GetSKUHandler()
 	if err != nil {
@@ -171,9 +206,11 @@ func FetchGPUCountFromNodes(ctx context.Context, kubeClient client.Client, nodeN
 
 func GetPerNodeGPUCountFromNodes(nodeList *v1.NodeList) string {
 	for _, node := range nodeList.Items {
-		gpuCount, exists := node.Status.Capacity[consts.NvidiaGPU]
-		if exists && gpuCount.String() != "" {
-			return gpuCount.String()
+		for _, vendor := range sku.GPUVendors {
+			gpuCount, exists := node.Status.Capacity[v1.ResourceName(vendor.ResourceName)]
+			if exists && gpuCount.String() != "" {
+				return gpuCount.String()
+			}
 		}
 	}
 	return ""
@@ -4,6 +4,11 @@ import (
 	"context"
 	"testing"
 
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/k8sclient"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -117,3 +122,54 @@ func TestFetchGPUCountFromNodes(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSKUHandlerWithGPUFeatureDiscovery(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	featuregates.FeatureGates[consts.FeatureFlagGPUFeatureDiscovery] = true
+	defer func() { featuregates.FeatureGates[consts.FeatureFlagGPUFeatureDiscovery] = false }()
+
+	newNode := func(name, instanceType string, labels map[string]string) runtime.Object {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: lo.Assign(map[string]string{corev1.LabelInstanceTypeStable: instanceType}, labels),
+		}}
+	}
+	k8sclient.SetGlobalClient(fake.NewClientBuilder().WithRuntimeObjects(
+		// An instance type of the SKU table, the labels report the actual GPU memory.
+		newNode("node-1", "Standard_NC4as_T4_v3", map[string]string{
+			sku.LabelGPUCount: "1", sku.LabelGPUMemory: "15360", sku.LabelGPUProduct: "Tesla-T4",
+		}),
+		// An instance type which is not in the SKU table.
+		newNode("node-2", "on-prem-h100", map[string]string{
+			sku.LabelGPUCount: "8", sku.LabelGPUMemory: "81559", sku.LabelGPUProduct: "NVIDIA-H100-80GB-HBM3",
+		}),
+		// A node without GPU Feature Discovery labels.
+		newNode("node-3", "on-prem-cpu", nil),
+	).Build())
+	defer k8sclient.SetGlobalClient(nil)
+
+	skuHandler, err := GetSKUHandler()
+	require.NoError(t, err)
+	configs := skuHandler.GetGPUConfigs()
+
+	assert.Equal(t, sku.GPUConfig{SKU: "Standard_NC4as_T4_v3", GPUCount: 1, GPUMem: 15, GPUModel: "NVIDIA Tesla T4",
+		Price: sku.NewAzureSKUHandler().GetGPUConfigs()["Standard_NC4as_T4_v3"].Price}, configs["Standard_NC4as_T4_v3"])
+	assert.Equal(t, sku.GPUConfig{SKU: "on-prem-h100", GPUCount: 8, GPUMem: 637, GPUModel: "NVIDIA H100 80GB HBM3"}, configs["on-prem-h100"])
+	assert.NotContains(t, configs, "on-prem-cpu")
+	// The SKU table is still used for the instance types without labeled nodes.
+	assert.Contains(t, configs, "Standard_NC6s_v3")
+
+	gpuCount, err := GetSKUNumGPUs(context.TODO(), k8sclient.Client, nil, "on-prem-h100", "1")
+	require.NoError(t, err)
+	assert.Equal(t, "8", gpuCount)
+}
+
+func TestGetGPUConfigBySKU(t *testing.T) {
+	t.Setenv("CLOUD_PROVIDER", consts.AWSCloudName)
+
+	// GPU instance types of other cloud providers than Azure are recognized.
+	config := GetGPUConfigBySKU("g5.xlarge")
+	require.NotNil(t, config)
+	assert.Equal(t, "g5.xlarge", config.SKU)
+	assert.Nil(t, GetGPUConfigBySKU("m5.xlarge"))
+}
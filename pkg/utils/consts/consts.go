@@ -13,6 +13,8 @@ const (
 	DefaultReleaseNamespaceEnvVar = "RELEASE_NAMESPACE"
 	AzureCloudName                = "azure"
 	AWSCloudName                  = "aws"
+	GCPCloudName                  = "gcp"
+	CustomCloudName               = "custom"
 	GPUString                     = "gpu"
 	SKUString                     = "sku"
 	MaxRevisionHistoryLimit       = 10
@@ -22,6 +24,9 @@ const (
 	// Feature flags
 	FeatureFlagKarpenter = "Karpenter"
 	FeatureFlagVLLM      = "vLLM"
+	// FeatureFlagGPUFeatureDiscovery discovers the GPUs of the instance types from the NVIDIA GPU Feature Discovery
+	// labels of the existing nodes, the SKU tables are only used as a fallback.
+	FeatureFlagGPUFeatureDiscovery = "GPUFeatureDiscovery"
 
 	// Nodeclaim related consts
 	KaitoNodePoolName             = "kaito"
@@ -34,8 +39,15 @@ const (
 	LabelGPUProvisionerCustom = "kaito.sh/machine-type"
 	LabelProvisionerName      = "karpenter.sh/provisioner-name"
 
-	// azure gpu sku prefix
-	GpuSkuPrefix = "Standard_N"
+	// SKU catalog of the custom cloud provider
+	SKUCatalogConfigMapName = "kaito-sku-catalog"
+	SKUCatalogConfigMapKey  = "skus.yaml"
 
 	NodePluginInstallTimeout = 60 * time.Second
+
+	// NodeProvisioningTimeout is the time after which a machine/nodeClaim which is not ready is replaced.
+	NodeProvisioningTimeout = 15 * time.Minute
+
+	// NodeUnhealthyTimeout is the time after which a machine/nodeClaim whose node has become NotReady is replaced.
+	NodeUnhealthyTimeout = 5 * time.Minute
 )
@@ -0,0 +1,51 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package utils
+
+import (
+	"context"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/samber/lo"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ sku.CloudSKUHandler = &nodeDiscoverySKUHandler{}
+
+// nodeDiscoverySKUHandler discovers the GPU configurations of the instance types from the NVIDIA GPU Feature Discovery
+// labels of the existing nodes. The SKU table of the cloud provider is only used for the instance types which have no
+// labeled node, and for the prices.
+type nodeDiscoverySKUHandler struct {
+	kubeClient client.Client
+	fallback   sku.CloudSKUHandler
+}
+
+func (h *nodeDiscoverySKUHandler) GetSupportedSKUs() []string {
+	return sku.GetMapKeys(h.GetGPUConfigs())
+}
+
+func (h *nodeDiscoverySKUHandler) GetGPUConfigs() map[string]sku.GPUConfig {
+	configs := lo.Assign(h.fallback.GetGPUConfigs())
+
+	nodeList := &v1.NodeList{}
+	if err := h.kubeClient.List(context.Background(), nodeList, client.HasLabels{sku.LabelGPUCount, sku.LabelGPUMemory}); err != nil {
+		klog.ErrorS(err, "failed to list nodes for GPU discovery, falling back to the SKU table")
+		return configs
+	}
+	for i := range nodeList.Items {
+		instanceType := nodeList.Items[i].Labels[v1.LabelInstanceTypeStable]
+		if instanceType == "" {
+			continue
+		}
+		discovered, ok := sku.GetGPUConfigFromNodeLabels(instanceType, nodeList.Items[i].Labels)
+		if !ok {
+			continue
+		}
+		discovered.Price = configs[instanceType].Price
+		configs[instanceType] = discovered
+	}
+	return configs
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/samber/lo"
@@ -49,11 +50,21 @@ func GenerateMachineManifest(ctx context.Context, storageRequirement string, obj
 		namespaceLabel:              namespace,
 	}
 
+	if pool := resources.GetSharedNodePool(obj); pool != "" {
+		// Shared nodes are not owned by the workspace which creates them, they are used by all workspaces of the pool.
+		delete(machineLabels, nameLabel)
+		delete(machineLabels, namespaceLabel)
+		machineLabels[kaitov1alpha1.LabelSharedNodePool] = pool
+	}
+	if migConfig := resources.GetMIGConfig(obj); migConfig != "" {
+		// The GPU operator partitions the GPUs of the node into the MIG slices requested by the workload.
+		machineLabels[sku.LabelMIGConfig] = migConfig
+	}
 	if labelSelector != nil && len(labelSelector.MatchLabels) != 0 {
 		machineLabels = lo.Assign(machineLabels, labelSelector.MatchLabels)
 	}
 
-	return &v1alpha5.Machine{
+	machineObj := &v1alpha5.Machine{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      machineName,
 			Namespace: namespace,
@@ -104,6 +115,21 @@ func GenerateMachineManifest(ctx context.Context, storageRequirement string, obj
 			},
 		},
 	}
+
+	if requirement, found := resources.GetCapacityTypeRequirement(obj); found {
+		machineObj.Spec.Requirements = append(machineObj.Spec.Requirements, requirement)
+	}
+	if labelSelector != nil {
+		// The match labels are set as labels of the machine, the match expressions restrict the nodes it can launch.
+		for _, expression := range labelSelector.MatchExpressions {
+			machineObj.Spec.Requirements = append(machineObj.Spec.Requirements, v1.NodeSelectorRequirement{
+				Key:      expression.Key,
+				Operator: v1.NodeSelectorOperator(expression.Operator),
+				Values:   expression.Values,
+			})
+		}
+	}
+	return machineObj
 }
 
 // CreateMachine creates a machine object.
@@ -172,7 +198,8 @@ func WaitForPendingMachines(ctx context.Context, obj interface{}, kubeClient cli
 	return nil
 }
 
-// ListMachines lists all machine objects in the cluster that are created by the given workspace or RAGEngine.
+// ListMachines lists all machine objects in the cluster that are created by the given workspace or RAGEngine, or the
+// machines of the shared node pool of the workspace.
 func ListMachines(ctx context.Context, obj interface{}, kubeClient client.Client) (*v1alpha5.MachineList, error) {
 	machineList := &v1alpha5.MachineList{}
 
@@ -184,6 +211,10 @@ func ListMachines(ctx context.Context, obj interface{}, kubeClient client.Client
 			kaitov1alpha1.LabelWorkspaceName:      o.Name,
 			kaitov1alpha1.LabelWorkspaceNamespace: o.Namespace,
 		}
+		if o.Resource.SharedNodePool != "" {
+			// The nodes of a shared node pool are used by all workspaces of the pool.
+			ls = labels.Set{kaitov1alpha1.LabelSharedNodePool: o.Resource.SharedNodePool}
+		}
 	case *kaitov1alpha1.RAGEngine:
 		ls = labels.Set{
 			kaitov1alpha1.LabelRAGEngineName:      o.Name,
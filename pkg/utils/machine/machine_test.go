@@ -7,13 +7,16 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/test"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -174,4 +177,54 @@ func TestGenerateMachineManifest(t *testing.T) {
 		assert.Check(t, machine != nil, "Machine must not be nil")
 		assert.Equal(t, machine.Namespace, mockWorkspace.Namespace, "Machine must have same namespace as workspace")
 	})
+
+	t.Run("Should require the capacity type of the workspace", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.CapacityType = kaitov1alpha1.CapacityTypeSpot
+
+		machine := GenerateMachineManifest(context.Background(), "0", mockWorkspace)
+
+		requirement := machine.Spec.Requirements[len(machine.Spec.Requirements)-1]
+		assert.Equal(t, requirement.Key, v1alpha5.LabelCapacityType, "Machine must have a capacity type requirement")
+		assert.DeepEqual(t, requirement.Values, []string{v1alpha5.CapacityTypeSpot})
+	})
+
+	t.Run("Should label the machine with the shared node pool instead of the workspace", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.SharedNodePool = "small-models"
+
+		machine := GenerateMachineManifest(context.Background(), "0", mockWorkspace)
+
+		assert.Equal(t, machine.Labels[kaitov1alpha1.LabelSharedNodePool], "small-models")
+		_, found := machine.Labels[kaitov1alpha1.LabelWorkspaceName]
+		assert.Check(t, !found, "Shared machine must not be labelled with the workspace")
+	})
+
+	t.Run("Should add the match expressions of the label selector to the requirements", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.LabelSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{Key: "topology.kubernetes.io/zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"eastus-3"}},
+		}
+
+		machine := GenerateMachineManifest(context.Background(), "0", mockWorkspace)
+
+		requirement := machine.Spec.Requirements[len(machine.Spec.Requirements)-1]
+		assert.DeepEqual(t, requirement, corev1.NodeSelectorRequirement{
+			Key:      "topology.kubernetes.io/zone",
+			Operator: corev1.NodeSelectorOpNotIn,
+			Values:   []string{"eastus-3"},
+		})
+	})
+
+	t.Run("Should label the machine with the MIG config of the fractional GPU", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.FractionalGPU = &kaitov1alpha1.FractionalGPUSpec{
+			Strategy:   kaitov1alpha1.FractionalGPUStrategyMIG,
+			MIGProfile: "1g.10gb",
+		}
+
+		machine := GenerateMachineManifest(context.Background(), "0", mockWorkspace)
+
+		assert.Equal(t, machine.Labels[sku.LabelMIGConfig], "all-1g.10gb")
+	})
 }
@@ -15,13 +15,16 @@ import (
 	azurev1alpha2 "github.com/Azure/karpenter-provider-azure/pkg/apis/v1alpha2"
 	awsv1beta1 "github.com/aws/karpenter-provider-aws/pkg/apis/v1beta1"
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/samber/lo"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -33,6 +36,10 @@ import (
 var (
 	// nodeClaimStatusTimeoutInterval is the interval to check the nodeClaim status.
 	nodeClaimStatusTimeoutInterval = 240 * time.Second
+
+	// GCENodeClassGVK is the kind of the node class of the GCP Karpenter provider. Its API types are not imported, so
+	// the node class is handled as an unstructured object.
+	GCENodeClassGVK = schema.GroupVersionKind{Group: "karpenter.k8s.gcp", Version: "v1alpha1", Kind: "GCENodeClass"}
 )
 
 // GenerateNodeClaimManifest generates a nodeClaim object from the given workspace or RAGEngine.
@@ -53,6 +60,16 @@ func GenerateNodeClaimManifest(ctx context.Context, storageRequirement string, o
 		nameLabel:            name,
 		namespaceLabel:       namespace,
 	}
+	if pool := resources.GetSharedNodePool(obj); pool != "" {
+		// Shared nodes are not owned by the workspace which creates them, they are used by all workspaces of the pool.
+		delete(nodeClaimLabels, nameLabel)
+		delete(nodeClaimLabels, namespaceLabel)
+		nodeClaimLabels[kaitov1alpha1.LabelSharedNodePool] = pool
+	}
+	if migConfig := resources.GetMIGConfig(obj); migConfig != "" {
+		// The GPU operator partitions the GPUs of the node into the MIG slices requested by the workload.
+		nodeClaimLabels[sku.LabelMIGConfig] = migConfig
+	}
 	if labelSelector != nil && len(labelSelector.MatchLabels) != 0 {
 		nodeClaimLabels = lo.Assign(nodeClaimLabels, labelSelector.MatchLabels)
 	}
@@ -69,6 +86,8 @@ func GenerateNodeClaimManifest(ctx context.Context, storageRequirement string, o
 		nodeClassRefKind = "AKSNodeClass"
 	} else if cloudName == consts.AWSCloudName { //aws
 		nodeClassRefKind = "EC2NodeClass"
+	} else if cloudName == consts.GCPCloudName {
+		nodeClassRefKind = GCENodeClassGVK.Kind
 	}
 	nodeClaimObj := &v1beta1.NodeClaim{
 		ObjectMeta: metav1.ObjectMeta{
@@ -142,6 +161,23 @@ func GenerateNodeClaimManifest(ctx context.Context, storageRequirement string, o
 		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, nodeSelector)
 	}
 
+	if requirement, found := resources.GetCapacityTypeRequirement(obj); found {
+		nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements,
+			v1beta1.NodeSelectorRequirementWithMinValues{NodeSelectorRequirement: requirement})
+	}
+	if labelSelector != nil {
+		// The match labels are set as labels of the nodeClaim, the match expressions restrict the nodes it can launch.
+		for _, expression := range labelSelector.MatchExpressions {
+			nodeClaimObj.Spec.Requirements = append(nodeClaimObj.Spec.Requirements, v1beta1.NodeSelectorRequirementWithMinValues{
+				NodeSelectorRequirement: v1.NodeSelectorRequirement{
+					Key:      expression.Key,
+					Operator: v1.NodeSelectorOperator(expression.Operator),
+					Values:   expression.Values,
+				},
+			})
+		}
+	}
+
 	return nodeClaimObj
 }
 
@@ -203,6 +239,23 @@ func GenerateEC2NodeClassManifest(ctx context.Context) *awsv1beta1.EC2NodeClass
 	}
 }
 
+func GenerateGCENodeClassManifest(ctx context.Context) *unstructured.Unstructured {
+	nodeClass := &unstructured.Unstructured{}
+	nodeClass.SetGroupVersionKind(GCENodeClassGVK)
+	nodeClass.SetName(consts.NodeClassName)
+	nodeClass.SetAnnotations(map[string]string{
+		"kubernetes.io/description": "General purpose GCENodeClass for running Container-Optimized OS nodes",
+	})
+	nodeClass.Object["spec"] = map[string]interface{}{
+		"imageSelectorTerms": []interface{}{
+			map[string]interface{}{
+				"alias": "ContainerOptimizedOS@latest", // The NVIDIA drivers are installed by GKE on COS nodes.
+			},
+		},
+	}
+	return nodeClass
+}
+
 // CreateNodeClaim creates a nodeClaim object.
 func CreateNodeClaim(ctx context.Context, nodeClaimObj *v1beta1.NodeClaim, kubeClient client.Client) error {
 	klog.InfoS("CreateNodeClaim", "nodeClaim", klog.KObj(nodeClaimObj))
@@ -247,6 +300,9 @@ func CreateKarpenterNodeClass(ctx context.Context, kubeClient client.Client) err
 	} else if cloudName == consts.AWSCloudName {
 		nodeClassObj := GenerateEC2NodeClassManifest(ctx)
 		return kubeClient.Create(ctx, nodeClassObj, &client.CreateOptions{})
+	} else if cloudName == consts.GCPCloudName {
+		nodeClassObj := GenerateGCENodeClassManifest(ctx)
+		return kubeClient.Create(ctx, nodeClassObj, &client.CreateOptions{})
 	} else {
 		return errors.New("unsupported cloud provider " + cloudName)
 	}
@@ -289,7 +345,8 @@ func WaitForPendingNodeClaims(ctx context.Context, obj interface{}, kubeClient c
 	return nil
 }
 
-// ListNodeClaim lists all nodeClaim objects in the cluster that are created by the given workspace or RAGEngine.
+// ListNodeClaim lists all nodeClaim objects in the cluster that are created by the given workspace or RAGEngine, or the
+// nodeClaims of the shared node pool of the workspace.
 func ListNodeClaim(ctx context.Context, obj interface{}, kubeClient client.Client) (*v1beta1.NodeClaimList, error) {
 	nodeClaimList := &v1beta1.NodeClaimList{}
 
@@ -302,6 +359,10 @@ func ListNodeClaim(ctx context.Context, obj interface{}, kubeClient client.Clien
 			kaitov1alpha1.LabelWorkspaceName:      o.Name,
 			kaitov1alpha1.LabelWorkspaceNamespace: o.Namespace,
 		}
+		if o.Resource.SharedNodePool != "" {
+			// The nodes of a shared node pool are used by all workspaces of the pool.
+			ls = labels.Set{kaitov1alpha1.LabelSharedNodePool: o.Resource.SharedNodePool}
+		}
 	case *kaitov1alpha1.RAGEngine:
 		ls = labels.Set{
 			kaitov1alpha1.LabelRAGEngineName:      o.Name,
@@ -371,6 +432,11 @@ func IsNodeClassAvailable(ctx context.Context, cloudName string, kubeClient clie
 		err := kubeClient.Get(ctx, client.ObjectKey{Name: consts.NodeClassName},
 			&awsv1beta1.EC2NodeClass{}, &client.GetOptions{})
 		return err == nil
+	} else if cloudName == consts.GCPCloudName {
+		nodeClass := &unstructured.Unstructured{}
+		nodeClass.SetGroupVersionKind(GCENodeClassGVK)
+		err := kubeClient.Get(ctx, client.ObjectKey{Name: consts.NodeClassName}, nodeClass, &client.GetOptions{})
+		return err == nil
 	}
 	klog.Error("unsupported cloud provider ", cloudName)
 	return false
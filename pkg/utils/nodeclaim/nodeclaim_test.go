@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -210,6 +211,46 @@ func TestGenerateNodeClaimManifest(t *testing.T) {
 		assert.Check(t, nodeClaim.Spec.NodeClassRef != nil, "NodeClaim must have NodeClassRef")
 		assert.Equal(t, nodeClaim.Spec.NodeClassRef.Kind, "EC2NodeClass", "NodeClaim must have 'EC2NodeClass' kind")
 	})
+
+	t.Run("Should generate a nodeClaim object from the given workspace when cloud provider set to gcp", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset
+		t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+
+		nodeClaim := GenerateNodeClaimManifest(context.Background(), "0", mockWorkspace)
+
+		assert.Check(t, nodeClaim != nil, "NodeClaim must not be nil")
+		assert.Equal(t, nodeClaim.Labels[kaitov1alpha1.LabelWorkspaceName], mockWorkspace.Name, "label must have same workspace name as workspace")
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 3, " NodeClaim must have 3 NodeSelector Requirements")
+		assert.Equal(t, nodeClaim.Spec.Requirements[1].NodeSelectorRequirement.Values[0], mockWorkspace.Resource.InstanceType, "NodeClaim must have same instance type as workspace")
+		assert.Check(t, nodeClaim.Spec.NodeClassRef != nil, "NodeClaim must have NodeClassRef")
+		assert.Equal(t, nodeClaim.Spec.NodeClassRef.Kind, "GCENodeClass", "NodeClaim must have 'GCENodeClass' kind")
+	})
+
+	t.Run("Should generate a nodeClaim object with the capacity type of the workspace", func(t *testing.T) {
+		mockWorkspace := test.MockWorkspaceWithPreset.DeepCopy()
+		mockWorkspace.Resource.CapacityType = kaitov1alpha1.CapacityTypeSpotWithFallback
+		t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+		nodeClaim := GenerateNodeClaimManifest(context.Background(), "0", mockWorkspace)
+
+		assert.Equal(t, len(nodeClaim.Spec.Requirements), 5, " NodeClaim must have 5 NodeSelector Requirements")
+		requirement := nodeClaim.Spec.Requirements[4].NodeSelectorRequirement
+		assert.Equal(t, requirement.Key, v1beta1.CapacityTypeLabelKey, "NodeClaim must have a capacity type requirement")
+		assert.DeepEqual(t, requirement.Values, []string{v1beta1.CapacityTypeSpot, v1beta1.CapacityTypeOnDemand})
+	})
+}
+
+func TestGenerateGCENodeClassManifest(t *testing.T) {
+	t.Run("Should generate a valid GCENodeClass object with correct name and image", func(t *testing.T) {
+		nodeClass := GenerateGCENodeClassManifest(context.Background())
+
+		assert.Check(t, nodeClass != nil, "GCENodeClass must not be nil")
+		assert.Equal(t, nodeClass.GroupVersionKind(), GCENodeClassGVK, "GCENodeClass must have the correct kind")
+		assert.Equal(t, nodeClass.GetName(), consts.NodeClassName, "GCENodeClass must have the correct name")
+		terms, found, err := unstructured.NestedSlice(nodeClass.Object, "spec", "imageSelectorTerms")
+		assert.Check(t, err == nil && found, "GCENodeClass must have image selector terms")
+		assert.Equal(t, terms[0].(map[string]interface{})["alias"], "ContainerOptimizedOS@latest", "GCENodeClass must have the correct image alias")
+	})
 }
 
 func TestGenerateAKSNodeClassManifest(t *testing.T) {
@@ -299,6 +340,17 @@ func TestCreateKarpenterNodeClass(t *testing.T) {
 		mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything)
 	})
 
+	t.Run("Should create GCENodeClass when cloud provider is GCP", func(t *testing.T) {
+		t.Setenv("CLOUD_PROVIDER", consts.GCPCloudName)
+
+		mockClient := test.NewClient()
+		mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything).Return(nil)
+
+		err := CreateKarpenterNodeClass(context.Background(), mockClient)
+		assert.Check(t, err == nil, "Not expected to return error")
+		mockClient.AssertCalled(t, "Create", mock.IsType(context.Background()), mock.IsType(&unstructured.Unstructured{}), mock.Anything)
+	})
+
 	t.Run("Should return error when cloud provider is unsupported", func(t *testing.T) {
 		t.Setenv("CLOUD_PROVIDER", "unsupported")
 
@@ -6,19 +6,21 @@ package resources
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 )
 
 const (
-	LabelKeyNvidia    = "accelerator"
-	LabelValueNvidia  = "nvidia"
-	CapacityNvidiaGPU = "nvidia.com/gpu"
+	// LabelKeyAccelerator is the node label whose value is the name of the GPU vendor, e.g. accelerator=nvidia.
+	LabelKeyAccelerator = "accelerator"
 )
 
 // GetNode get kubernetes node object with a provided name
@@ -36,7 +38,7 @@ func GetNode(ctx context.Context, nodeName string, kubeClient client.Client) (*c
 }
 
 // ListNodes get list of kubernetes nodes
-func ListNodes(ctx context.Context, kubeClient client.Client, labelSelector client.MatchingLabels) (*corev1.NodeList, error) {
+func ListNodes(ctx context.Context, kubeClient client.Client, labelSelector client.ListOption) (*corev1.NodeList, error) {
 	nodeList := &corev1.NodeList{}
 
 	err := kubeClient.List(ctx, nodeList, labelSelector)
@@ -47,6 +49,45 @@ func ListNodes(ctx context.Context, kubeClient client.Client, labelSelector clie
 	return nodeList, nil
 }
 
+// GetNodeSelector returns the list option which selects the nodes matching both the match labels and the match
+// expressions of the label selector.
+func GetNodeSelector(labelSelector *metav1.LabelSelector) (client.ListOption, error) {
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	return client.MatchingLabelsSelector{Selector: selector}, nil
+}
+
+// GetNodeSelectorRequirements converts the label selector to node selector requirements. The requirements of the match
+// labels are sorted by key, followed by the requirements of the match expressions in their original order, so that
+// the generated objects do not change between reconciliations.
+func GetNodeSelectorRequirements(labelSelector *metav1.LabelSelector) []corev1.NodeSelectorRequirement {
+	if labelSelector == nil {
+		return []corev1.NodeSelectorRequirement{}
+	}
+	requirements := make([]corev1.NodeSelectorRequirement, 0, len(labelSelector.MatchLabels)+len(labelSelector.MatchExpressions))
+	for key, value := range labelSelector.MatchLabels {
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      key,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{value},
+		})
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		return requirements[i].Key < requirements[j].Key
+	})
+	for _, expression := range labelSelector.MatchExpressions {
+		// The operators of label selectors are a subset of the operators of node selectors.
+		requirements = append(requirements, corev1.NodeSelectorRequirement{
+			Key:      expression.Key,
+			Operator: corev1.NodeSelectorOperator(expression.Operator),
+			Values:   expression.Values,
+		})
+	}
+	return requirements
+}
+
 // UpdateNodeWithLabel update the node object with the label key/value
 func UpdateNodeWithLabel(ctx context.Context, nodeName, labelKey, labelValue string, kubeClient client.Client) error {
 	klog.InfoS("UpdateNodeWithLabel", "nodeName", nodeName, "labelKey", labelKey, "labelValue", labelValue)
@@ -69,18 +110,20 @@ func UpdateNodeWithLabel(ctx context.Context, nodeName, labelKey, labelValue str
 	return nil
 }
 
-func CheckNvidiaPlugin(ctx context.Context, nodeObj *corev1.Node) bool {
-	// check if label accelerator=nvidia exists in the node
+// CheckGPUPlugin checks if the node has the accelerator label of the GPU vendor and the device plugin of the vendor
+// advertises GPUs in the node capacity.
+func CheckGPUPlugin(ctx context.Context, nodeObj *corev1.Node, vendor sku.GPUVendor) bool {
+	// check if label accelerator=<vendor> exists in the node
 	var foundLabel, foundCapacity bool
-	if nvidiaLabelVal, found := nodeObj.Labels[LabelKeyNvidia]; found {
-		if nvidiaLabelVal == LabelValueNvidia {
+	if labelVal, found := nodeObj.Labels[LabelKeyAccelerator]; found {
+		if labelVal == vendor.Name {
 			foundLabel = true
 		}
 	}
 
-	// check Status.Capacity.nvidia.com/gpu has value
+	// check Status.Capacity.<vendor resource> has value
 	capacity := nodeObj.Status.Capacity
-	if capacity != nil && !capacity.Name(CapacityNvidiaGPU, "").IsZero() {
+	if capacity != nil && !capacity.Name(corev1.ResourceName(vendor.ResourceName), "").IsZero() {
 		foundCapacity = true
 	}
 
@@ -90,6 +133,61 @@ func CheckNvidiaPlugin(ctx context.Context, nodeObj *corev1.Node) bool {
 	return false
 }
 
+// GetCapacityTypeRequirement returns the node requirement on the Karpenter capacity type of the nodes provisioned for
+// the given workspace. It returns false if no capacity type is specified, RAGEngines do not support capacity types.
+func GetCapacityTypeRequirement(obj interface{}) (corev1.NodeSelectorRequirement, bool) {
+	wObj, ok := obj.(*kaitov1alpha1.Workspace)
+	if !ok {
+		return corev1.NodeSelectorRequirement{}, false
+	}
+
+	var values []string
+	switch wObj.Resource.CapacityType {
+	case kaitov1alpha1.CapacityTypeOnDemand:
+		values = []string{v1beta1.CapacityTypeOnDemand}
+	case kaitov1alpha1.CapacityTypeSpot:
+		values = []string{v1beta1.CapacityTypeSpot}
+	case kaitov1alpha1.CapacityTypeSpotWithFallback:
+		// The provisioner launches the cheapest offering, which is spot whenever spot capacity is available.
+		values = []string{v1beta1.CapacityTypeSpot, v1beta1.CapacityTypeOnDemand}
+	default:
+		return corev1.NodeSelectorRequirement{}, false
+	}
+	return corev1.NodeSelectorRequirement{
+		Key:      v1beta1.CapacityTypeLabelKey,
+		Operator: corev1.NodeSelectorOpIn,
+		Values:   values,
+	}, true
+}
+
+// GetSharedNodePool returns the shared node pool of the given workspace, or an empty string if its nodes are not shared.
+func GetSharedNodePool(obj interface{}) string {
+	if o, ok := obj.(*kaitov1alpha1.Workspace); ok {
+		return o.Resource.SharedNodePool
+	}
+	return ""
+}
+
+// GetGPUResourceName returns the extended resource of the GPUs requested by the workload of the workspace. It is the
+// resource of the GPU slices of the MIG profile for workspaces which run on MIG slices.
+func GetGPUResourceName(wObj *kaitov1alpha1.Workspace, vendor sku.GPUVendor) corev1.ResourceName {
+	if f := wObj.Resource.FractionalGPU; f != nil && f.Strategy == kaitov1alpha1.FractionalGPUStrategyMIG {
+		return corev1.ResourceName(sku.MIGResourceName(f.MIGProfile))
+	}
+	return corev1.ResourceName(vendor.ResourceName)
+}
+
+// GetMIGConfig returns the MIG config of the nodes provisioned for the given workspace, or an empty string if their
+// GPUs are not partitioned.
+func GetMIGConfig(obj interface{}) string {
+	if o, ok := obj.(*kaitov1alpha1.Workspace); ok {
+		if f := o.Resource.FractionalGPU; f != nil && f.Strategy == kaitov1alpha1.FractionalGPUStrategyMIG {
+			return sku.MIGConfig(f.MIGProfile)
+		}
+	}
+	return ""
+}
+
 func ExtractObjFields(obj interface{}) (instanceType, namespace, name string, labelSelector *metav1.LabelSelector,
 	nameLabel, namespaceLabel string, err error) {
 	switch o := obj.(type) {
@@ -8,9 +8,13 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/test"
 	"testing"
 
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -105,26 +109,86 @@ func TestListNodes(t *testing.T) {
 	}
 }
 
-func TestCheckNvidiaPlugin(t *testing.T) {
+func TestCheckGPUPlugin(t *testing.T) {
+	amdNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "amd-node",
+			Labels: map[string]string{LabelKeyAccelerator: sku.GPUVendorAMD.Name},
+		},
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceName(sku.GPUVendorAMD.ResourceName): resource.MustParse("1"),
+			},
+		},
+	}
 	testcases := map[string]struct {
-		nodeObj        *corev1.Node
-		isNvidiaPlugin bool
+		nodeObj     *corev1.Node
+		vendor      sku.GPUVendor
+		isGPUPlugin bool
 	}{
 		"Is not nvidia plugin": {
-			nodeObj:        &test.MockNodeList.Items[1],
-			isNvidiaPlugin: false,
+			nodeObj:     &test.MockNodeList.Items[1],
+			vendor:      sku.GPUVendorNvidia,
+			isGPUPlugin: false,
 		},
 		"Is nvidia plugin": {
-			nodeObj:        &test.MockNodeList.Items[0],
-			isNvidiaPlugin: true,
+			nodeObj:     &test.MockNodeList.Items[0],
+			vendor:      sku.GPUVendorNvidia,
+			isGPUPlugin: true,
+		},
+		"Is amd plugin": {
+			nodeObj:     amdNode,
+			vendor:      sku.GPUVendorAMD,
+			isGPUPlugin: true,
+		},
+		"Nvidia plugin is not amd plugin": {
+			nodeObj:     &test.MockNodeList.Items[0],
+			vendor:      sku.GPUVendorAMD,
+			isGPUPlugin: false,
 		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
-			result := CheckNvidiaPlugin(context.Background(), tc.nodeObj)
+			result := CheckGPUPlugin(context.Background(), tc.nodeObj, tc.vendor)
 
-			assert.Equal(t, result, tc.isNvidiaPlugin)
+			assert.Equal(t, result, tc.isGPUPlugin)
+		})
+	}
+}
+
+func TestGetCapacityTypeRequirement(t *testing.T) {
+	testcases := map[string]struct {
+		capacityType   kaitov1alpha1.CapacityType
+		expectedValues []string
+	}{
+		"Capacity type not specified": {},
+		"On-demand": {
+			capacityType:   kaitov1alpha1.CapacityTypeOnDemand,
+			expectedValues: []string{"on-demand"},
+		},
+		"Spot": {
+			capacityType:   kaitov1alpha1.CapacityTypeSpot,
+			expectedValues: []string{"spot"},
+		},
+		"Spot with on-demand fallback": {
+			capacityType:   kaitov1alpha1.CapacityTypeSpotWithFallback,
+			expectedValues: []string{"spot", "on-demand"},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.CapacityType = tc.capacityType
+
+			requirement, found := GetCapacityTypeRequirement(workspace)
+			assert.Equal(t, found, tc.expectedValues != nil)
+			if found {
+				assert.Equal(t, requirement.Key, "karpenter.sh/capacity-type")
+				assert.Equal(t, requirement.Operator, corev1.NodeSelectorOpIn)
+				assert.DeepEqual(t, requirement.Values, tc.expectedValues)
+			}
 		})
 	}
 }
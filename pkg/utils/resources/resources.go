@@ -8,13 +8,17 @@ import (
 	"time"
 
 	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func CreateResource(ctx context.Context, resource client.Object, kubeClient client.Client) error {
@@ -27,6 +31,14 @@ func CreateResource(ctx context.Context, resource client.Object, kubeClient clie
 		klog.InfoS("CreateService", "service", klog.KObj(r))
 	case *corev1.ConfigMap:
 		klog.InfoS("CreateConfigMap", "configmap", klog.KObj(r))
+	case *networkingv1.Ingress:
+		klog.InfoS("CreateIngress", "ingress", klog.KObj(r))
+	case *gatewayv1.HTTPRoute:
+		klog.InfoS("CreateHTTPRoute", "httproute", klog.KObj(r))
+	case *networkingv1.NetworkPolicy:
+		klog.InfoS("CreateNetworkPolicy", "networkpolicy", klog.KObj(r))
+	case *policyv1.PodDisruptionBudget:
+		klog.InfoS("CreatePodDisruptionBudget", "poddisruptionbudget", klog.KObj(r))
 	}
 
 	// Create the resource.
@@ -105,6 +117,40 @@ func CheckResourceStatus(obj client.Object, kubeClient client.Client, timeoutDur
 	}
 }
 
+// GetResourceStatus checks the status of a Deployment, StatefulSet or Job without waiting for it. It returns whether
+// the resource is ready and a message describing its progress. An error is returned if the resource has failed.
+// A Deployment or a StatefulSet is ready once all its replicas have been updated to the latest spec and are ready.
+func GetResourceStatus(obj client.Object) (bool, string, error) {
+	switch k8sResource := obj.(type) {
+	case *appsv1.Deployment:
+		for _, condition := range k8sResource.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse {
+				return false, "", fmt.Errorf("deployment %s is not progressing: %s", k8sResource.Name, condition.Message)
+			}
+		}
+		replicas := lo.FromPtr(k8sResource.Spec.Replicas)
+		ready := k8sResource.Status.ObservedGeneration >= k8sResource.Generation &&
+			k8sResource.Status.UpdatedReplicas == replicas && k8sResource.Status.ReadyReplicas == replicas
+		return ready, fmt.Sprintf("deployment %s has %d updated and %d ready replicas out of %d", k8sResource.Name,
+			k8sResource.Status.UpdatedReplicas, k8sResource.Status.ReadyReplicas, replicas), nil
+	case *appsv1.StatefulSet:
+		replicas := lo.FromPtr(k8sResource.Spec.Replicas)
+		ready := k8sResource.Status.ObservedGeneration >= k8sResource.Generation &&
+			k8sResource.Status.UpdatedReplicas == replicas && k8sResource.Status.ReadyReplicas == replicas
+		return ready, fmt.Sprintf("statefulset %s has %d updated and %d ready replicas out of %d", k8sResource.Name,
+			k8sResource.Status.UpdatedReplicas, k8sResource.Status.ReadyReplicas, replicas), nil
+	case *batchv1.Job:
+		if k8sResource.Status.Failed > 0 {
+			return false, "", fmt.Errorf("job %s has failed %d pods", k8sResource.Name, k8sResource.Status.Failed)
+		}
+		ready := k8sResource.Status.Succeeded > 0 || lo.FromPtr(k8sResource.Status.Ready) > 0
+		return ready, fmt.Sprintf("job %s has %d active and %d ready pods", k8sResource.Name,
+			k8sResource.Status.Active, lo.FromPtr(k8sResource.Status.Ready)), nil
+	default:
+		return false, "", fmt.Errorf("unsupported resource type %T", obj)
+	}
+}
+
 // EnsureConfigOrCopyFromDefault handles two scenarios:
 // 1. User provided config:
 //   - Check if it exists in the target namespace
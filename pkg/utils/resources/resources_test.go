@@ -18,6 +18,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -190,6 +191,102 @@ func TestCheckResourceStatus(t *testing.T) {
 	})
 }
 
+func TestGetResourceStatus(t *testing.T) {
+	t.Run("Should return ready for rolled out Deployment", func(t *testing.T) {
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 2,
+				UpdatedReplicas:    3,
+				ReadyReplicas:      3,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(3),
+			},
+		}
+		ready, _, err := GetResourceStatus(dep)
+		assert.Nil(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("Should return not ready for Deployment being rolled out", func(t *testing.T) {
+		dep := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Status: appsv1.DeploymentStatus{
+				ObservedGeneration: 1,
+				UpdatedReplicas:    3,
+				ReadyReplicas:      3,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(3),
+			},
+		}
+		ready, _, err := GetResourceStatus(dep)
+		assert.Nil(t, err)
+		assert.False(t, ready)
+	})
+
+	t.Run("Should return error for Deployment not progressing", func(t *testing.T) {
+		dep := &appsv1.Deployment{
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{
+						Type:   appsv1.DeploymentProgressing,
+						Status: corev1.ConditionFalse,
+					},
+				},
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: int32Ptr(1),
+			},
+		}
+		_, _, err := GetResourceStatus(dep)
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return not ready for StatefulSet with missing replicas", func(t *testing.T) {
+		ss := &appsv1.StatefulSet{
+			Status: appsv1.StatefulSetStatus{
+				UpdatedReplicas: 2,
+				ReadyReplicas:   1,
+			},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: int32Ptr(2),
+			},
+		}
+		ready, message, err := GetResourceStatus(ss)
+		assert.Nil(t, err)
+		assert.False(t, ready)
+		assert.Contains(t, message, "1 ready replicas out of 2")
+	})
+
+	t.Run("Should return ready for Job with ready pods", func(t *testing.T) {
+		job := &batchv1.Job{
+			Status: batchv1.JobStatus{
+				Ready: int32Ptr(1),
+			},
+		}
+		ready, _, err := GetResourceStatus(job)
+		assert.Nil(t, err)
+		assert.True(t, ready)
+	})
+
+	t.Run("Should return error for failed Job", func(t *testing.T) {
+		job := &batchv1.Job{
+			Status: batchv1.JobStatus{
+				Failed: 1,
+			},
+		}
+		_, _, err := GetResourceStatus(job)
+		assert.Error(t, err)
+	})
+
+	t.Run("Should return error for unsupported resource type", func(t *testing.T) {
+		_, _, err := GetResourceStatus(&corev1.Pod{})
+		assert.Error(t, err)
+	})
+}
+
 func TestCreateResource(t *testing.T) {
 	testcases := map[string]struct {
 		callMocks        func(c *test.MockClient)
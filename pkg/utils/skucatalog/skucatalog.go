@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package skucatalog
+
+import (
+	"context"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Reconciler loads the SKU catalog of the custom cloud provider from the consts.SKUCatalogConfigMapName ConfigMap in
+// the release namespace, and reloads it whenever the ConfigMap changes.
+type Reconciler struct {
+	client.Client
+	Namespace string
+	Handler   *sku.CustomSKUHandler
+}
+
+func NewReconciler(c client.Client, namespace string) *Reconciler {
+	return &Reconciler{
+		Client:    c,
+		Namespace: namespace,
+		Handler:   sku.GetCustomSKUHandler(),
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, req.NamespacedName, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.InfoS("SKU catalog is deleted, no instance type is supported", "configmap", req.NamespacedName)
+			r.Handler.SetGPUConfigs(map[string]sku.GPUConfig{})
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	configs, err := sku.ParseCustomSKUs(cm.Data[consts.SKUCatalogConfigMapKey])
+	if err != nil {
+		// Retrying does not help until the ConfigMap is fixed, which triggers another reconcile.
+		klog.ErrorS(err, "invalid SKU catalog, keeping the previous one", "configmap", req.NamespacedName)
+		return reconcile.Result{}, nil
+	}
+	r.Handler.SetGPUConfigs(configs)
+	klog.InfoS("SKU catalog is loaded", "configmap", req.NamespacedName, "skus", len(configs))
+	return reconcile.Result{}, nil
+}
+
+// SetupWithManager sets up the reconciler with the Manager. It runs on every replica regardless of leader election,
+// since the webhooks of all the replicas validate workspaces against the catalog.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("sku-catalog").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			return o.GetNamespace() == r.Namespace && o.GetName() == consts.SKUCatalogConfigMapName
+		}))).
+		WithOptions(controller.Options{NeedLeaderElection: lo.ToPtr(false)}).
+		Complete(r)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package skucatalog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestReconcile(t *testing.T) {
+	validCatalog := `
+- instanceType: dgx-a100
+  gpuCount: 8
+  gpuMemGiB: 640
+  gpuModel: NVIDIA A100
+`
+	previous := map[string]sku.GPUConfig{"previous": {SKU: "previous", GPUCount: 1, GPUMem: 16}}
+
+	testcases := map[string]struct {
+		data         *string
+		expectedSKUs []string
+	}{
+		"Catalog is loaded": {
+			data:         lo.ToPtr(validCatalog),
+			expectedSKUs: []string{"dgx-a100"},
+		},
+		"Invalid catalog keeps the previous one": {
+			data:         lo.ToPtr(`- instanceType: dgx-a100`),
+			expectedSKUs: []string{"previous"},
+		},
+		"Deleted catalog supports no SKU": {
+			expectedSKUs: []string{},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			key := client.ObjectKey{Name: consts.SKUCatalogConfigMapName, Namespace: "kaito-workspace"}
+			if tc.data != nil {
+				mockClient.CreateOrUpdateObjectInMap(&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+					Data:       map[string]string{consts.SKUCatalogConfigMapKey: *tc.data},
+				})
+				mockClient.On("Get", mock.IsType(context.Background()), key, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), key, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(test.NotFoundError())
+			}
+
+			handler := &sku.CustomSKUHandler{}
+			handler.SetGPUConfigs(previous)
+			reconciler := &Reconciler{Client: mockClient, Namespace: key.Namespace, Handler: handler}
+
+			_, err := reconciler.Reconcile(context.Background(), reconcile.Request{NamespacedName: key})
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.expectedSKUs, handler.GetSupportedSKUs())
+		})
+	}
+}
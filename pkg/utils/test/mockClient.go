@@ -8,9 +8,11 @@ import (
 	"reflect"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/stretchr/testify/mock"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -129,6 +131,38 @@ func (m *MockClient) getObjectListFromMap(list k8sClient.ObjectList) k8sClient.O
 			}
 		}
 		return controllerRevisionList
+	case *corev1.PodList:
+		podList := &corev1.PodList{}
+		for _, obj := range relevantMap {
+			if p, ok := obj.(*corev1.Pod); ok {
+				podList.Items = append(podList.Items, *p)
+			}
+		}
+		return podList
+	case *corev1.ServiceList:
+		serviceList := &corev1.ServiceList{}
+		for _, obj := range relevantMap {
+			if svc, ok := obj.(*corev1.Service); ok {
+				serviceList.Items = append(serviceList.Items, *svc)
+			}
+		}
+		return serviceList
+	case *discoveryv1.EndpointSliceList:
+		endpointSliceList := &discoveryv1.EndpointSliceList{}
+		for _, obj := range relevantMap {
+			if slice, ok := obj.(*discoveryv1.EndpointSlice); ok {
+				endpointSliceList.Items = append(endpointSliceList.Items, *slice)
+			}
+		}
+		return endpointSliceList
+	case *v1alpha1.WorkspaceList:
+		workspaceList := &v1alpha1.WorkspaceList{}
+		for _, obj := range relevantMap {
+			if ws, ok := obj.(*v1alpha1.Workspace); ok {
+				workspaceList.Items = append(workspaceList.Items, *ws)
+			}
+		}
+		return workspaceList
 	}
 	//add additional object lists as needed
 	return nil
@@ -163,7 +197,33 @@ func (m *MockClient) DeleteAllOf(ctx context.Context, obj k8sClient.Object, opts
 
 // SubResource implements client.Client
 func (m *MockClient) SubResource(subResource string) k8sClient.SubResourceClient {
-	panic("unimplemented")
+	return &MockSubResourceClient{client: m, subResource: subResource}
+}
+
+// MockSubResourceClient records the calls on the mock client as SubResourceGet, SubResourceCreate, etc.
+type MockSubResourceClient struct {
+	client      *MockClient
+	subResource string
+}
+
+func (s *MockSubResourceClient) Get(ctx context.Context, obj k8sClient.Object, subResource k8sClient.Object, opts ...k8sClient.SubResourceGetOption) error {
+	args := s.client.MethodCalled("SubResourceGet", ctx, s.subResource, obj, subResource, opts)
+	return args.Error(0)
+}
+
+func (s *MockSubResourceClient) Create(ctx context.Context, obj k8sClient.Object, subResource k8sClient.Object, opts ...k8sClient.SubResourceCreateOption) error {
+	args := s.client.MethodCalled("SubResourceCreate", ctx, s.subResource, obj, subResource, opts)
+	return args.Error(0)
+}
+
+func (s *MockSubResourceClient) Update(ctx context.Context, obj k8sClient.Object, opts ...k8sClient.SubResourceUpdateOption) error {
+	args := s.client.MethodCalled("SubResourceUpdate", ctx, s.subResource, obj, opts)
+	return args.Error(0)
+}
+
+func (s *MockSubResourceClient) Patch(ctx context.Context, obj k8sClient.Object, patch k8sClient.Patch, opts ...k8sClient.SubResourcePatchOption) error {
+	args := s.client.MethodCalled("SubResourcePatch", ctx, s.subResource, obj, patch, opts)
+	return args.Error(0)
 }
 
 // GroupVersionKindFor implements client.Client
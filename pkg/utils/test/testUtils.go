@@ -634,7 +634,8 @@ var (
 			},
 		},
 		Status: appsv1.DeploymentStatus{
-			ReadyReplicas: 1,
+			ReadyReplicas:   1,
+			UpdatedReplicas: 1,
 		},
 	}
 	MockDeploymentWithAnnotationsAndContainer1 = appsv1.Deployment{
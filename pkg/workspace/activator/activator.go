@@ -0,0 +1,291 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package activator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// inferencePort is the port of the inference API in the inference pods.
+	inferencePort = 5000
+
+	defaultWakeTimeout = 30 * time.Minute
+	pollInterval       = 5 * time.Second
+)
+
+var errWorkspaceNotFound = errors.New("no idle workspace matches the request")
+
+// Activator receives the requests sent to idle workspaces. While a workspace is idle, the controller adds the
+// activator as an endpoint of the workspace Service. The activator wakes the workspace up, holds the requests
+// until the inference workload is ready again and forwards them to the inference pods.
+type Activator struct {
+	Client      client.Client
+	Port        int
+	WakeTimeout time.Duration
+}
+
+func NewActivator(c client.Client, port int) *Activator {
+	return &Activator{
+		Client:      c,
+		Port:        port,
+		WakeTimeout: defaultWakeTimeout,
+	}
+}
+
+// Start runs the activator HTTP server until the context is done. It implements manager.Runnable, so that
+// the activator only runs in the elected controller replica whose address is used by the Service endpoints.
+func (a *Activator) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", a.Port),
+		Handler:           a,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.ErrorS(err, "failed to shut down the activator")
+		}
+	}()
+	klog.InfoS("starting activator", "port", a.Port)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (a *Activator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	wObj, svc, err := a.lookupWorkspace(ctx, r.Host, r.URL.Path)
+	if err != nil {
+		klog.ErrorS(err, "failed to find the workspace of the request", "host", r.Host)
+		status := http.StatusInternalServerError
+		if errors.Is(err, errWorkspaceNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if err := a.wake(ctx, wObj); err != nil {
+		klog.ErrorS(err, "failed to wake up the workspace", "workspace", klog.KObj(wObj))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	target, err := a.waitForInferencePod(ctx, wObj, svc)
+	if err != nil {
+		klog.ErrorS(err, "inference workload is not ready", "workspace", klog.KObj(wObj))
+		w.Header().Set("Retry-After", strconv.Itoa(int(pollInterval.Seconds())))
+		http.Error(w, fmt.Sprintf("workspace %s is waking up, please retry later", wObj.Name), http.StatusServiceUnavailable)
+		return
+	}
+
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ServeHTTP(w, r)
+}
+
+// lookupWorkspace finds the workspace whose Service the request was sent to. Only the Services whose endpoints
+// point at the activator are considered, i.e., those of workspaces scaled to zero, so that a request cannot wake
+// up any other workspace. The Service is identified by the host of the request, either its cluster IP or its DNS
+// name, i.e., <name>[.<namespace>[.svc...]]. The requests proxied by the Ingress or the HTTPRoute of an exposed
+// workspace keep the external host name, they are matched against the expose spec of the workspaces instead.
+func (a *Activator) lookupWorkspace(ctx context.Context, host, path string) (*kaitov1alpha1.Workspace, *corev1.Service, error) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	// The EndpointSlices of the activator are served from the cache of the controller manager.
+	sliceList := &discoveryv1.EndpointSliceList{}
+	if err := a.Client.List(ctx, sliceList, client.MatchingLabels{discoveryv1.LabelManagedBy: manifests.ActivatorEndpointSliceManager}); err != nil {
+		return nil, nil, err
+	}
+	candidates := lo.FilterMap(sliceList.Items, func(slice discoveryv1.EndpointSlice, _ int) (client.ObjectKey, bool) {
+		name, ok := slice.Labels[discoveryv1.LabelServiceName]
+		return client.ObjectKey{Name: name, Namespace: slice.Namespace}, ok
+	})
+
+	var svc *corev1.Service
+	if net.ParseIP(host) != nil {
+		for _, key := range candidates {
+			candidate := &corev1.Service{}
+			if err := a.Client.Get(ctx, key, candidate); err != nil {
+				if client.IgnoreNotFound(err) == nil {
+					continue
+				}
+				return nil, nil, err
+			}
+			if lo.Contains(candidate.Spec.ClusterIPs, host) {
+				svc = candidate
+				break
+			}
+		}
+	} else {
+		labels := strings.Split(host, ".")
+		// The namespace of a short name is unknown, it has to match a single workspace being idle or waking up.
+		matches := lo.Filter(candidates, func(key client.ObjectKey, _ int) bool {
+			return key.Name == labels[0] && (len(labels) == 1 || key.Namespace == labels[1])
+		})
+		if len(matches) == 0 {
+			var err error
+			if matches, err = a.lookupExposedWorkspaces(ctx, candidates, host, path); err != nil {
+				return nil, nil, err
+			}
+		}
+		if len(matches) == 1 {
+			svc = &corev1.Service{}
+			if err := a.Client.Get(ctx, matches[0], svc); err != nil {
+				if client.IgnoreNotFound(err) == nil {
+					return nil, nil, errWorkspaceNotFound
+				}
+				return nil, nil, err
+			}
+		}
+	}
+	if svc == nil {
+		return nil, nil, errWorkspaceNotFound
+	}
+
+	wObj := &kaitov1alpha1.Workspace{}
+	if err := a.Client.Get(ctx, client.ObjectKeyFromObject(svc), wObj); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil, nil, errWorkspaceNotFound
+		}
+		return nil, nil, err
+	}
+	return wObj, svc, nil
+}
+
+// lookupExposedWorkspaces returns the candidate workspaces exposed at the host name whose path prefix is the
+// longest one matching the path of the request.
+func (a *Activator) lookupExposedWorkspaces(ctx context.Context, candidates []client.ObjectKey, host, path string) ([]client.ObjectKey, error) {
+	var matches []client.ObjectKey
+	longestPrefix := -1
+	for _, key := range candidates {
+		wObj := &kaitov1alpha1.Workspace{}
+		if err := a.Client.Get(ctx, key, wObj); err != nil {
+			if client.IgnoreNotFound(err) == nil {
+				continue
+			}
+			return nil, err
+		}
+		if wObj.Expose == nil || !strings.EqualFold(wObj.Expose.Hostname, host) {
+			continue
+		}
+		prefix := lo.Ternary(wObj.Expose.PathPrefix == "", "/", wObj.Expose.PathPrefix)
+		if !strings.HasPrefix(path, prefix) || len(prefix) < longestPrefix {
+			continue
+		}
+		if len(prefix) > longestPrefix {
+			matches, longestPrefix = nil, len(prefix)
+		}
+		matches = append(matches, key)
+	}
+	return matches, nil
+}
+
+// wake marks an idle workspace as waking up, which triggers the controller to bring the workload back.
+func (a *Activator) wake(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &kaitov1alpha1.Workspace{}
+		if err := a.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return err
+		}
+		if !isIdle(latest) {
+			return nil
+		}
+		klog.InfoS("Waking up workspace", "workspace", klog.KObj(latest))
+		SetIdleConditions(latest, false, "RequestReceived", "an inference request has been received")
+		return a.Client.Status().Update(ctx, latest)
+	})
+}
+
+// waitForInferencePod waits until the workspace is awake and returns the address of a ready inference pod
+// selected by the workspace Service. The request is forwarded to the pod directly because the Service may
+// still route to the activator until the endpoints are updated.
+func (a *Activator) waitForInferencePod(ctx context.Context, wObj *kaitov1alpha1.Workspace, svc *corev1.Service) (*url.URL, error) {
+	var target *url.URL
+	err := wait.PollUntilContextTimeout(ctx, pollInterval, a.WakeTimeout, true, func(ctx context.Context) (bool, error) {
+		latest := &kaitov1alpha1.Workspace{}
+		if err := a.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if isIdle(latest) || isWaking(latest) ||
+			!meta.IsStatusConditionTrue(latest.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeInferenceStatus)) {
+			return false, nil
+		}
+
+		podList := &corev1.PodList{}
+		if err := a.Client.List(ctx, podList, client.InNamespace(svc.Namespace), client.MatchingLabels(svc.Spec.Selector)); err != nil {
+			return false, err
+		}
+		pod, found := lo.Find(podList.Items, func(pod corev1.Pod) bool {
+			return pod.Status.PodIP != "" && lo.ContainsBy(pod.Status.Conditions, func(cond corev1.PodCondition) bool {
+				return cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue
+			})
+		})
+		if !found {
+			return false, nil
+		}
+		target = &url.URL{Scheme: "http", Host: net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(inferencePort))}
+		return true, nil
+	})
+	return target, err
+}
+
+func isIdle(wObj *kaitov1alpha1.Workspace) bool {
+	return meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeIdle))
+}
+
+func isWaking(wObj *kaitov1alpha1.Workspace) bool {
+	return meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeWaking))
+}
+
+// SetIdleConditions updates the status conditions of a workspace entering or leaving the idle state.
+// Leaving the idle state starts waking the workspace up and counts as an activity.
+func SetIdleConditions(wObj *kaitov1alpha1.Workspace, idle bool, reason, message string) {
+	setCondition := func(cType kaitov1alpha1.ConditionType, status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&wObj.Status.Conditions, metav1.Condition{
+			Type:               string(cType),
+			Status:             status,
+			Reason:             reason,
+			ObservedGeneration: wObj.GetGeneration(),
+			Message:            message,
+		})
+	}
+	if idle {
+		setCondition(kaitov1alpha1.WorkspaceConditionTypeIdle, metav1.ConditionTrue, reason, message)
+		setCondition(kaitov1alpha1.WorkspaceConditionTypeWaking, metav1.ConditionFalse, reason, message)
+		setCondition(kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse, "WorkspaceIdle", "Inference workload has been scaled to zero")
+		setCondition(kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse, "WorkspaceIdle", "Nodes have been released")
+		setCondition(kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse, "WorkspaceIdle", "workspace has been scaled to zero")
+		return
+	}
+	setCondition(kaitov1alpha1.WorkspaceConditionTypeIdle, metav1.ConditionFalse, reason, message)
+	setCondition(kaitov1alpha1.WorkspaceConditionTypeWaking, metav1.ConditionTrue, reason, message)
+	wObj.Status.LastActivityTime = lo.ToPtr(metav1.Now())
+}
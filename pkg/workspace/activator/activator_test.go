@@ -0,0 +1,217 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package activator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func idleWorkspace(name, namespace string) *v1alpha1.Workspace {
+	return &v1alpha1.Workspace{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status: v1alpha1.WorkspaceStatus{
+			Conditions: []metav1.Condition{{Type: string(v1alpha1.WorkspaceConditionTypeIdle), Status: metav1.ConditionTrue}},
+		},
+	}
+}
+
+// addActivatorEndpoints adds the workspace and its Service to the mock client, and the activator EndpointSlice
+// if the workspace is idle.
+func addActivatorEndpoints(mockClient *test.MockClient, ws *v1alpha1.Workspace, clusterIP string) {
+	mockClient.CreateOrUpdateObjectInMap(ws)
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: ws.Name, Namespace: ws.Namespace},
+		Spec:       corev1.ServiceSpec{ClusterIPs: []string{clusterIP}},
+	}
+	mockClient.CreateOrUpdateObjectInMap(svc)
+	sliceMap := mockClient.CreateMapWithType(&discoveryv1.EndpointSliceList{})
+	if isIdle(ws) {
+		slice := manifests.GenerateActivatorEndpointSliceManifest(ws, "10.0.0.100", 8090)
+		sliceMap[client.ObjectKeyFromObject(slice)] = slice
+	}
+}
+
+func exposedWorkspace(name, hostname, pathPrefix string, idle bool) *v1alpha1.Workspace {
+	ws := &v1alpha1.Workspace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	if idle {
+		ws = idleWorkspace(name, "default")
+	}
+	ws.Expose = &v1alpha1.ExposeSpec{Hostname: hostname, PathPrefix: pathPrefix}
+	return ws
+}
+
+func TestLookupWorkspace(t *testing.T) {
+	testcases := map[string]struct {
+		host          string
+		path          string
+		workspaces    []*v1alpha1.Workspace
+		expected      client.ObjectKey
+		expectedError error
+	}{
+		"Cluster IP": {
+			host:       "10.0.0.10:80",
+			workspaces: []*v1alpha1.Workspace{idleWorkspace("ws", "default")},
+			expected:   client.ObjectKey{Name: "ws", Namespace: "default"},
+		},
+		"Unknown cluster IP": {
+			host:          "10.0.0.11",
+			workspaces:    []*v1alpha1.Workspace{idleWorkspace("ws", "default")},
+			expectedError: errWorkspaceNotFound,
+		},
+		"Fully qualified name": {
+			host:       "ws.default.svc.cluster.local",
+			workspaces: []*v1alpha1.Workspace{idleWorkspace("ws", "default")},
+			expected:   client.ObjectKey{Name: "ws", Namespace: "default"},
+		},
+		"Short name of a single idle workspace": {
+			host:       "ws",
+			workspaces: []*v1alpha1.Workspace{idleWorkspace("ws", "default"), {ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "other"}}},
+			expected:   client.ObjectKey{Name: "ws", Namespace: "default"},
+		},
+		"Workspace which is not scaled to zero": {
+			host:          "ws.other",
+			workspaces:    []*v1alpha1.Workspace{idleWorkspace("ws", "default"), {ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "other"}}},
+			expectedError: errWorkspaceNotFound,
+		},
+		"Cluster IP of a workspace which is not scaled to zero": {
+			host:          "10.0.0.20",
+			workspaces:    []*v1alpha1.Workspace{idleWorkspace("ws", "default"), {ObjectMeta: metav1.ObjectMeta{Name: "ws", Namespace: "other"}}},
+			expectedError: errWorkspaceNotFound,
+		},
+		"Ingress host name": {
+			host:       "llm.example.com",
+			path:       "/falcon/v1/completions",
+			workspaces: []*v1alpha1.Workspace{exposedWorkspace("falcon", "llm.example.com", "/falcon", true), exposedWorkspace("phi", "llm.example.com", "/", true)},
+			expected:   client.ObjectKey{Name: "falcon", Namespace: "default"},
+		},
+		"Ingress host name of a workspace which is not scaled to zero": {
+			host:          "llm.example.com",
+			path:          "/falcon/v1/completions",
+			workspaces:    []*v1alpha1.Workspace{exposedWorkspace("falcon", "llm.example.com", "/falcon", false)},
+			expectedError: errWorkspaceNotFound,
+		},
+		"Ambiguous ingress host name": {
+			host:          "llm.example.com",
+			path:          "/v1/completions",
+			workspaces:    []*v1alpha1.Workspace{exposedWorkspace("falcon", "llm.example.com", "", true), exposedWorkspace("phi", "llm.example.com", "/", true)},
+			expectedError: errWorkspaceNotFound,
+		},
+		"Ambiguous short name": {
+			host:          "ws",
+			workspaces:    []*v1alpha1.Workspace{idleWorkspace("ws", "default"), idleWorkspace("ws", "other")},
+			expectedError: errWorkspaceNotFound,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			for i, ws := range tc.workspaces {
+				addActivatorEndpoints(mockClient, ws, []string{"10.0.0.10", "10.0.0.20"}[i])
+			}
+			mockClient.On("List", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			a := NewActivator(mockClient, 8090)
+			wObj, svc, err := a.lookupWorkspace(context.Background(), tc.host, tc.path)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, client.ObjectKeyFromObject(wObj))
+			assert.Equal(t, tc.expected, client.ObjectKeyFromObject(svc))
+		})
+	}
+}
+
+func TestWake(t *testing.T) {
+	mockClient := test.NewClient()
+	wObj := idleWorkspace("ws", "default")
+	mockClient.CreateOrUpdateObjectInMap(wObj)
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	var updated *v1alpha1.Workspace
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*v1alpha1.Workspace)
+	}).Return(nil)
+
+	a := NewActivator(mockClient, 8090)
+	assert.NoError(t, a.wake(context.Background(), wObj))
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	assert.False(t, meta.IsStatusConditionTrue(updated.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeIdle)))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeWaking)))
+	assert.NotNil(t, updated.Status.LastActivityTime)
+
+	// A workspace that is not idle is left untouched.
+	mockClient.CreateOrUpdateObjectInMap(updated)
+	assert.NoError(t, a.wake(context.Background(), updated))
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestServeHTTPWithUnknownWorkspace(t *testing.T) {
+	mockClient := test.NewClient()
+	mockClient.CreateMapWithType(&discoveryv1.EndpointSliceList{})
+	mockClient.On("List", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+
+	a := NewActivator(mockClient, 8090)
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://ws/v1/completions", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServeHTTPWakesWorkspaceThroughIngress(t *testing.T) {
+	mockClient := test.NewClient()
+	addActivatorEndpoints(mockClient, exposedWorkspace("ws", "llm.example.com", "/falcon", true), "10.0.0.10")
+	mockClient.On("List", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+	mockClient.On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	var updated *v1alpha1.Workspace
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*v1alpha1.Workspace)
+	}).Return(nil)
+
+	a := NewActivator(mockClient, 8090)
+	a.WakeTimeout = time.Second
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://llm.example.com/falcon/v1/completions", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, client.ObjectKey{Name: "ws", Namespace: "default"}, client.ObjectKeyFromObject(updated))
+	assert.True(t, meta.IsStatusConditionTrue(updated.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeWaking)))
+}
+
+func TestServeHTTPWakeTimeout(t *testing.T) {
+	mockClient := test.NewClient()
+	addActivatorEndpoints(mockClient, idleWorkspace("ws", "default"), "10.0.0.10")
+	mockClient.On("List", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+	mockClient.On("Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(errors.New("failed to update status"))
+
+	a := NewActivator(mockClient, 8090)
+	a.WakeTimeout = time.Second
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://ws.default/v1/completions", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	mockClient.StatusMock = &test.MockStatusClient{}
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	rec = httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://ws.default/v1/completions", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "5", rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,273 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Metric names exposed by the vLLM runtime.
+	vllmNumRequestsWaiting = "vllm:num_requests_waiting"
+	vllmGPUCacheUsage      = "vllm:gpu_cache_usage_perc"
+	vllmE2ERequestLatency  = "vllm:e2e_request_latency_seconds"
+	vllmNumRequestsRunning = "vllm:num_requests_running"
+	vllmRequestSuccess     = "vllm:request_success_total"
+
+	// scaleTolerance is the relative difference between the metric value and the target value
+	// within which no scaling is performed, same as the HorizontalPodAutoscaler default.
+	scaleTolerance = 0.1
+
+	// scrapeTimeout bounds the scrape of all the pods of a workspace.
+	scrapeTimeout = 5 * time.Second
+)
+
+// Sample is a metric value scraped from a single inference pod.
+type Sample struct {
+	// Value is the value of a gauge metric.
+	Value float64
+	// Sum and Count are the cumulative sum and count of a histogram metric.
+	Sum   float64
+	Count float64
+}
+
+// ParseMetric extracts the given autoscaling metric from a Prometheus text exposition.
+// Values of all the series of the metric, e.g., one per model or adapter, are added up.
+func ParseMetric(r io.Reader, name kaitov1alpha1.AutoscalingMetricName) (Sample, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	var sample Sample
+	switch name {
+	case kaitov1alpha1.AutoscalingMetricQueuedRequests, kaitov1alpha1.AutoscalingMetricKVCacheUtilization:
+		metricName := vllmNumRequestsWaiting
+		if name == kaitov1alpha1.AutoscalingMetricKVCacheUtilization {
+			metricName = vllmGPUCacheUsage
+		}
+		family, ok := families[metricName]
+		if !ok {
+			return Sample{}, fmt.Errorf("metric %s not found", metricName)
+		}
+		for _, m := range family.GetMetric() {
+			sample.Value += gaugeValue(m)
+		}
+	case kaitov1alpha1.AutoscalingMetricRequestLatency:
+		family, ok := families[vllmE2ERequestLatency]
+		if !ok {
+			return Sample{}, fmt.Errorf("metric %s not found", vllmE2ERequestLatency)
+		}
+		for _, m := range family.GetMetric() {
+			sample.Sum += m.GetHistogram().GetSampleSum()
+			sample.Count += float64(m.GetHistogram().GetSampleCount())
+		}
+	default:
+		return Sample{}, fmt.Errorf("unsupported autoscaling metric %s", name)
+	}
+	return sample, nil
+}
+
+// Activity is the request activity of an inference pod.
+type Activity struct {
+	// Completed is the total number of requests completed by the pod.
+	Completed float64
+	// InFlight is the number of requests being processed or waiting to be processed.
+	InFlight float64
+}
+
+// ParseActivity extracts the request activity from a Prometheus text exposition.
+func ParseActivity(r io.Reader) (Activity, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return Activity{}, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	if _, ok := families[vllmRequestSuccess]; !ok {
+		return Activity{}, fmt.Errorf("metric %s not found", vllmRequestSuccess)
+	}
+
+	var activity Activity
+	for _, m := range families[vllmRequestSuccess].GetMetric() {
+		activity.Completed += m.GetCounter().GetValue()
+	}
+	for _, name := range []string{vllmNumRequestsRunning, vllmNumRequestsWaiting} {
+		for _, m := range families[name].GetMetric() {
+			activity.InFlight += gaugeValue(m)
+		}
+	}
+	return activity, nil
+}
+
+func gaugeValue(m *dto.Metric) float64 {
+	if m.GetGauge() != nil {
+		return m.GetGauge().GetValue()
+	}
+	return m.GetUntyped().GetValue()
+}
+
+// DesiredReplicas computes the number of replicas needed to bring the average metric value
+// to the target value, bounded by minReplicas and maxReplicas.
+func DesiredReplicas(currentReplicas int, metricValue, targetValue float64, minReplicas, maxReplicas int) int {
+	desired := currentReplicas
+	if currentReplicas > 0 && targetValue > 0 {
+		ratio := metricValue / targetValue
+		if math.Abs(ratio-1.0) > scaleTolerance {
+			desired = int(math.Ceil(ratio * float64(currentReplicas)))
+		}
+	}
+	if desired < minReplicas {
+		desired = minReplicas
+	}
+	if desired > maxReplicas {
+		desired = maxReplicas
+	}
+	return desired
+}
+
+// Scraper fetches autoscaling metrics from inference pods.
+type Scraper struct {
+	HTTPClient *http.Client
+
+	mu sync.Mutex
+	// lastLatency keeps the previous latency histogram of each pod so that the average latency
+	// is computed over the last scrape interval instead of the pod lifetime.
+	lastLatency map[types.UID]Sample
+	// lastCompleted keeps the previous number of completed requests of each pod.
+	lastCompleted map[types.UID]float64
+}
+
+func NewScraper() *Scraper {
+	return &Scraper{
+		HTTPClient:    &http.Client{Timeout: scrapeTimeout},
+		lastLatency:   map[types.UID]Sample{},
+		lastCompleted: map[types.UID]float64{},
+	}
+}
+
+// AverageMetric returns the average value of the metric across the given pods and the number of pods sampled.
+// Pods whose metrics cannot be scraped are skipped.
+func (s *Scraper) AverageMetric(ctx context.Context, pods []corev1.Pod, port int, name kaitov1alpha1.AutoscalingMetricName) (float64, int) {
+	values := make([]float64, len(pods))
+	sampled := make([]bool, len(pods))
+	s.scrapeAll(ctx, pods, port, func(i int, r io.Reader) error {
+		sample, err := ParseMetric(r, name)
+		if err != nil {
+			return err
+		}
+		if name == kaitov1alpha1.AutoscalingMetricRequestLatency {
+			values[i], sampled[i] = s.intervalLatency(pods[i].UID, sample)
+		} else {
+			values[i], sampled[i] = sample.Value, true
+		}
+		return nil
+	})
+
+	var total float64
+	var count int
+	for i := range pods {
+		if sampled[i] {
+			total += values[i]
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return total / float64(count), count
+}
+
+// Active reports whether any of the given pods has completed a request since the previous call
+// or is processing requests. The first observation of a pod only records its completed requests.
+func (s *Scraper) Active(ctx context.Context, pods []corev1.Pod, port int) bool {
+	active := make([]bool, len(pods))
+	s.scrapeAll(ctx, pods, port, func(i int, r io.Reader) error {
+		activity, err := ParseActivity(r)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		last, found := s.lastCompleted[pods[i].UID]
+		s.lastCompleted[pods[i].UID] = activity.Completed
+		s.mu.Unlock()
+		active[i] = activity.InFlight > 0 || (found && activity.Completed != last)
+		return nil
+	})
+	return lo.Contains(active, true)
+}
+
+// scrapeAll scrapes the metrics of the pods concurrently under a single deadline, so that unreachable pods do not
+// add up their timeouts in the reconcile loop. The metrics of the i-th pod are passed to parse, which is called
+// concurrently. Pods whose metrics cannot be scraped are skipped.
+func (s *Scraper) scrapeAll(ctx context.Context, pods []corev1.Pod, port int, parse func(i int, r io.Reader) error) {
+	ctx, cancel := context.WithTimeout(ctx, scrapeTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := range pods {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pod := &pods[i]
+			if err := s.scrape(ctx, metricsURL(pod, port), func(r io.Reader) error {
+				return parse(i, r)
+			}); err != nil {
+				klog.ErrorS(err, "failed to scrape inference metrics", "pod", klog.KObj(pod))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func metricsURL(pod *corev1.Pod, port int) string {
+	return fmt.Sprintf("http://%s:%d/metrics", pod.Status.PodIP, port)
+}
+
+func (s *Scraper) scrape(ctx context.Context, url string, parse func(io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+	return parse(resp.Body)
+}
+
+// intervalLatency returns the average latency of the requests completed since the previous scrape of the pod.
+// It returns false if no request has completed in the interval.
+func (s *Scraper) intervalLatency(uid types.UID, sample Sample) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, found := s.lastLatency[uid]
+	s.lastLatency[uid] = sample
+	if found && sample.Count >= last.Count {
+		sample.Sum -= last.Sum
+		sample.Count -= last.Count
+	}
+	if sample.Count == 0 {
+		return 0, false
+	}
+	return sample.Sum / sample.Count, true
+}
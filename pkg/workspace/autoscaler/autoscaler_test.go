@@ -0,0 +1,218 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package autoscaler
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const testMetrics = `# HELP vllm:num_requests_waiting Number of requests waiting to be processed.
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="falcon-7b"} 4.0
+vllm:num_requests_waiting{model_name="adapter"} 2.0
+# HELP vllm:gpu_cache_usage_perc GPU KV-cache usage. 1 means 100 percent usage.
+# TYPE vllm:gpu_cache_usage_perc gauge
+vllm:gpu_cache_usage_perc{model_name="falcon-7b"} 0.75
+# HELP vllm:e2e_request_latency_seconds Histogram of end to end request latency in seconds.
+# TYPE vllm:e2e_request_latency_seconds histogram
+vllm:e2e_request_latency_seconds_bucket{model_name="falcon-7b",le="1.0"} 2.0
+vllm:e2e_request_latency_seconds_bucket{model_name="falcon-7b",le="+Inf"} 4.0
+vllm:e2e_request_latency_seconds_sum{model_name="falcon-7b"} 10.0
+vllm:e2e_request_latency_seconds_count{model_name="falcon-7b"} 4.0
+`
+
+func TestParseMetric(t *testing.T) {
+	testcases := map[string]struct {
+		input         string
+		metric        kaitov1alpha1.AutoscalingMetricName
+		expected      Sample
+		expectedError string
+	}{
+		"Queued requests are added up across series": {
+			input:    testMetrics,
+			metric:   kaitov1alpha1.AutoscalingMetricQueuedRequests,
+			expected: Sample{Value: 6},
+		},
+		"KV-cache utilization": {
+			input:    testMetrics,
+			metric:   kaitov1alpha1.AutoscalingMetricKVCacheUtilization,
+			expected: Sample{Value: 0.75},
+		},
+		"Request latency histogram": {
+			input:    testMetrics,
+			metric:   kaitov1alpha1.AutoscalingMetricRequestLatency,
+			expected: Sample{Sum: 10, Count: 4},
+		},
+		"Metric not found": {
+			input:         "# TYPE other gauge\nother 1\n",
+			metric:        kaitov1alpha1.AutoscalingMetricQueuedRequests,
+			expectedError: "metric vllm:num_requests_waiting not found",
+		},
+		"Unsupported metric": {
+			input:         testMetrics,
+			metric:        "foo",
+			expectedError: "unsupported autoscaling metric foo",
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			sample, err := ParseMetric(strings.NewReader(tc.input), tc.metric)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, sample)
+		})
+	}
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	testcases := map[string]struct {
+		current  int
+		value    float64
+		target   float64
+		min      int
+		max      int
+		expected int
+	}{
+		"Scale up":                      {current: 2, value: 10, target: 5, min: 1, max: 10, expected: 4},
+		"Scale up rounds up":            {current: 1, value: 5.5, target: 5, min: 1, max: 10, expected: 2},
+		"Scale down":                    {current: 4, value: 1, target: 4, min: 1, max: 10, expected: 1},
+		"Within tolerance":              {current: 3, value: 5.2, target: 5, min: 1, max: 10, expected: 3},
+		"Bounded by max replicas":       {current: 2, value: 100, target: 1, min: 1, max: 5, expected: 5},
+		"Bounded by min replicas":       {current: 4, value: 0, target: 1, min: 2, max: 5, expected: 2},
+		"Current replicas out of range": {current: 8, value: 5, target: 5, min: 1, max: 5, expected: 5},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DesiredReplicas(tc.current, tc.value, tc.target, tc.min, tc.max))
+		})
+	}
+}
+
+func TestAverageMetric(t *testing.T) {
+	latencySum, latencyCount := 10.0, 4.0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := strings.ReplaceAll(testMetrics, "_sum{model_name=\"falcon-7b\"} 10.0", "_sum{model_name=\"falcon-7b\"} "+strconv.FormatFloat(latencySum, 'f', -1, 64))
+		body = strings.ReplaceAll(body, "_count{model_name=\"falcon-7b\"} 4.0", "_count{model_name=\"falcon-7b\"} "+strconv.FormatFloat(latencyCount, 'f', -1, 64))
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	port, _ := strconv.Atoi(portStr)
+
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", UID: "uid-1"}, Status: corev1.PodStatus{PodIP: host}},
+		// Unreachable pods are skipped.
+		{ObjectMeta: metav1.ObjectMeta{Name: "pod-2", UID: "uid-2"}, Status: corev1.PodStatus{PodIP: "256.0.0.1"}},
+	}
+	s := NewScraper()
+
+	value, sampled := s.AverageMetric(context.Background(), pods, port, kaitov1alpha1.AutoscalingMetricQueuedRequests)
+	assert.Equal(t, 1, sampled)
+	assert.Equal(t, 6.0, value)
+
+	// The first latency sample is averaged over the pod lifetime.
+	value, sampled = s.AverageMetric(context.Background(), pods, port, kaitov1alpha1.AutoscalingMetricRequestLatency)
+	assert.Equal(t, 1, sampled)
+	assert.Equal(t, 2.5, value)
+
+	// Subsequent samples are averaged over the requests completed since the previous scrape.
+	latencySum, latencyCount = 16, 6
+	value, sampled = s.AverageMetric(context.Background(), pods, port, kaitov1alpha1.AutoscalingMetricRequestLatency)
+	assert.Equal(t, 1, sampled)
+	assert.Equal(t, 3.0, value)
+
+	// No request has completed since the previous scrape.
+	value, sampled = s.AverageMetric(context.Background(), pods, port, kaitov1alpha1.AutoscalingMetricRequestLatency)
+	assert.Equal(t, 0, sampled)
+	assert.Equal(t, 0.0, value)
+}
+
+func TestAverageMetricScrapesPodsConcurrently(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(testMetrics))
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	port, _ := strconv.Atoi(portStr)
+
+	var pods []corev1.Pod
+	for i := 0; i < 10; i++ {
+		pods = append(pods, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pod-%d", i), UID: types.UID(fmt.Sprintf("uid-%d", i))},
+			Status:     corev1.PodStatus{PodIP: host},
+		})
+	}
+
+	start := time.Now()
+	value, sampled := NewScraper().AverageMetric(context.Background(), pods, port, kaitov1alpha1.AutoscalingMetricQueuedRequests)
+	assert.Equal(t, 10, sampled)
+	assert.Equal(t, 6.0, value)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestParseActivity(t *testing.T) {
+	input := `# TYPE vllm:request_success_total counter
+vllm:request_success_total{finished_reason="stop",model_name="falcon-7b"} 7.0
+vllm:request_success_total{finished_reason="length",model_name="falcon-7b"} 3.0
+# TYPE vllm:num_requests_running gauge
+vllm:num_requests_running{model_name="falcon-7b"} 1.0
+# TYPE vllm:num_requests_waiting gauge
+vllm:num_requests_waiting{model_name="falcon-7b"} 2.0
+`
+	activity, err := ParseActivity(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, Activity{Completed: 10, InFlight: 3}, activity)
+
+	_, err = ParseActivity(strings.NewReader(testMetrics))
+	assert.EqualError(t, err, "metric vllm:request_success_total not found")
+}
+
+func TestActive(t *testing.T) {
+	completed, inFlight := "5", "0"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("# TYPE vllm:request_success_total counter\nvllm:request_success_total " + completed +
+			"\n# TYPE vllm:num_requests_running gauge\nvllm:num_requests_running " + inFlight + "\n"))
+	}))
+	defer server.Close()
+
+	host, portStr, err := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	assert.NoError(t, err)
+	port, _ := strconv.Atoi(portStr)
+	pods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", UID: "uid-1"}, Status: corev1.PodStatus{PodIP: host}}}
+	s := NewScraper()
+
+	// The first observation only records the completed requests.
+	assert.False(t, s.Active(context.Background(), pods, port))
+	assert.False(t, s.Active(context.Background(), pods, port))
+
+	completed = "6"
+	assert.True(t, s.Active(context.Background(), pods, port))
+
+	inFlight = "1"
+	assert.True(t, s.Active(context.Background(), pods, port))
+}
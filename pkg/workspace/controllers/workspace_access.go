@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ensureNetworkPolicy creates or updates the NetworkPolicy which restricts the clients of the inference workload
+// to the access spec of the workspace. The NetworkPolicy is deleted once the access spec is removed.
+func (c *WorkspaceReconciler) ensureNetworkPolicy(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	existing := &networkingv1.NetworkPolicy{}
+	err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if wObj.Access == nil {
+		if !found {
+			return nil
+		}
+		klog.InfoS("Deleting NetworkPolicy", "workspace", klog.KObj(wObj))
+		return client.IgnoreNotFound(c.Delete(ctx, existing))
+	}
+
+	kaitoNamespace, err := utils.GetReleaseNamespace()
+	if err != nil {
+		return err
+	}
+	supportsDistributedInference := false
+	if presetName := getPresetName(wObj); presetName != "" {
+		supportsDistributedInference = plugin.KaitoModelRegister.MustGet(presetName).SupportDistributedInference()
+	}
+	desired := manifests.GenerateNetworkPolicyManifest(ctx, wObj, kaitoNamespace, supportsDistributedInference)
+
+	if !found {
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+	if equality.Semantic.DeepEqual(desired.Spec, existing.Spec) {
+		return nil
+	}
+	klog.InfoS("Updating NetworkPolicy", "workspace", klog.KObj(wObj))
+	existing.Spec = desired.Spec
+	return c.Update(ctx, existing)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestEnsureNetworkPolicy(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv(consts.DefaultReleaseNamespaceEnvVar, "kaito-workspace")
+	access := &v1alpha1.AccessSpec{Namespaces: []string{"clients"}}
+	testcases := map[string]struct {
+		access          *v1alpha1.AccessSpec
+		existingAccess  *v1alpha1.AccessSpec
+		expectedCreates int
+		expectedUpdates int
+		expectedDeletes int
+	}{
+		"Workspace without access spec": {},
+		"Creates the network policy": {
+			access:          access,
+			expectedCreates: 1,
+		},
+		"Existing network policy is up to date": {
+			access:         access,
+			existingAccess: access,
+		},
+		"Existing network policy is updated": {
+			access:          &v1alpha1.AccessSpec{Namespaces: []string{"clients", "gateway"}},
+			existingAccess:  access,
+			expectedUpdates: 1,
+		},
+		"Network policy is deleted once the access spec is removed": {
+			existingAccess:  access,
+			expectedDeletes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			if tc.existingAccess != nil {
+				wObj.Access = tc.existingAccess
+				mockClient.CreateOrUpdateObjectInMap(manifests.GenerateNetworkPolicyManifest(context.Background(), wObj, "kaito-workspace", false))
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(test.NotFoundError())
+			}
+			wObj.Access = tc.access
+			mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&networkingv1.NetworkPolicy{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			err := reconciler.ensureNetworkPolicy(context.Background(), wObj)
+			assert.Check(t, err == nil, "Not expected to return error")
+
+			mockClient.AssertNumberOfCalls(t, "Create", tc.expectedCreates)
+			mockClient.AssertNumberOfCalls(t, "Update", tc.expectedUpdates)
+			mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+		})
+	}
+}
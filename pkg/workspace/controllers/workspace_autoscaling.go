@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const autoscalingSyncPeriod = 30 * time.Second
+
+func isAutoscalingEnabled(wObj *kaitov1alpha1.Workspace) bool {
+	return wObj.Inference != nil && wObj.Inference.Autoscaling != nil
+}
+
+// applyAutoscaling computes the desired number of replicas from the inference metrics and overrides
+// Resource.Count of the in-memory workspace object with it. The workspace spec is never updated.
+func (c *WorkspaceReconciler) applyAutoscaling(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	spec := wObj.Inference.Autoscaling
+	minReplicas := lo.FromPtrOr(spec.MinReplicas, 1)
+	maxReplicas := spec.MaxReplicas
+
+	var current int
+	var lastScaleTime *metav1.Time
+	if status := wObj.Status.Autoscaling; status != nil && status.DesiredReplicas > 0 {
+		current = status.DesiredReplicas
+		lastScaleTime = status.LastScaleTime
+	} else {
+		current = lo.FromPtrOr(wObj.Resource.Count, 1)
+	}
+	current = lo.Clamp(current, minReplicas, maxReplicas)
+
+	desired := current
+	var metricValue string
+	pods, err := c.getReadyInferencePods(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	if len(pods) > 0 {
+		value, sampled := c.Scraper.AverageMetric(ctx, pods, inference.Port5000, spec.Metric.Name)
+		if sampled > 0 {
+			// The target value has been validated by the webhook.
+			target, _ := strconv.ParseFloat(spec.Metric.TargetValue, 64)
+			metricValue = strconv.FormatFloat(value, 'f', -1, 64)
+			desired = autoscaler.DesiredReplicas(current, value, target, minReplicas, maxReplicas)
+		}
+	}
+
+	if desired < current && lastScaleTime != nil {
+		delay := time.Duration(lo.FromPtrOr(spec.ScaleDownDelaySeconds, 300)) * time.Second
+		if time.Since(lastScaleTime.Time) < delay {
+			klog.InfoS("Scale down is delayed", "workspace", klog.KObj(wObj), "current", current, "desired", desired)
+			desired = current
+		}
+	}
+
+	newStatus := &kaitov1alpha1.AutoscalingStatus{
+		DesiredReplicas:    desired,
+		CurrentMetricValue: metricValue,
+		LastScaleTime:      lastScaleTime,
+	}
+	if desired != current {
+		klog.InfoS("Autoscaling inference workload", "workspace", klog.KObj(wObj), "from", current, "to", desired, "metricValue", metricValue)
+	}
+	if wObj.Status.Autoscaling == nil || desired != wObj.Status.Autoscaling.DesiredReplicas {
+		newStatus.LastScaleTime = lo.ToPtr(metav1.Now())
+	}
+	if err := c.updateStatusAutoscalingIfNotMatch(ctx, wObj, newStatus); err != nil {
+		return err
+	}
+
+	wObj.Resource.Count = lo.ToPtr(desired)
+	return nil
+}
+
+// getReadyInferencePods returns the running and ready inference pods of the workspace.
+func (c *WorkspaceReconciler) getReadyInferencePods(ctx context.Context, wObj *kaitov1alpha1.Workspace) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := c.Client.List(ctx, podList, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, err
+	}
+	return lo.Filter(podList.Items, func(pod corev1.Pod, _ int) bool {
+		if pod.Status.Phase != corev1.PodRunning || pod.Status.PodIP == "" || !pod.DeletionTimestamp.IsZero() {
+			return false
+		}
+		_, found := lo.Find(pod.Status.Conditions, func(cond corev1.PodCondition) bool {
+			return cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue
+		})
+		return found
+	}), nil
+}
+
+// ignorePeriodicStatusUpdates filters out the workspace updates that only refresh the status fields
+// written periodically by the controller itself, so that they do not trigger another reconcile immediately.
+func ignorePeriodicStatusUpdates() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldObj, ok := e.ObjectOld.(*kaitov1alpha1.Workspace)
+			if !ok {
+				return true
+			}
+			newObj, ok := e.ObjectNew.(*kaitov1alpha1.Workspace)
+			if !ok {
+				return true
+			}
+			return !equality.Semantic.DeepEqual(withoutPeriodicStatus(oldObj), withoutPeriodicStatus(newObj))
+		},
+	}
+}
+
+func withoutPeriodicStatus(wObj *kaitov1alpha1.Workspace) *kaitov1alpha1.Workspace {
+	w := wObj.DeepCopy()
+	w.ResourceVersion = ""
+	w.ManagedFields = nil
+	w.Status.Autoscaling = nil
+	w.Status.LastActivityTime = nil
+	return w
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func mockMetricsScraper(queuedRequests string) *autoscaler.Scraper {
+	s := autoscaler.NewScraper()
+	s.HTTPClient = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("# TYPE vllm:num_requests_waiting gauge\nvllm:num_requests_waiting " + queuedRequests + "\n")),
+		}, nil
+	})}
+	return s
+}
+
+func mockInferencePod(name string, ready bool) *corev1.Pod {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kaito", UID: "uid-" + types.UID(name)},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			PodIP:      "10.0.0.1",
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func TestApplyAutoscaling(t *testing.T) {
+	testcases := map[string]struct {
+		count            int
+		status           *v1alpha1.AutoscalingStatus
+		pods             []*corev1.Pod
+		queuedRequests   string
+		expectedReplicas int
+		expectScaleTime  bool
+	}{
+		"Initial replicas without metrics": {
+			count:            2,
+			expectedReplicas: 2,
+			expectScaleTime:  true,
+		},
+		"Initial replicas are bounded by max replicas": {
+			count:            8,
+			expectedReplicas: 4,
+			expectScaleTime:  true,
+		},
+		"Unready pods are not sampled": {
+			count:            2,
+			status:           &v1alpha1.AutoscalingStatus{DesiredReplicas: 2},
+			pods:             []*corev1.Pod{mockInferencePod("pod-1", false)},
+			queuedRequests:   "20",
+			expectedReplicas: 2,
+		},
+		"Scale up based on queued requests": {
+			count:            1,
+			status:           &v1alpha1.AutoscalingStatus{DesiredReplicas: 1},
+			pods:             []*corev1.Pod{mockInferencePod("pod-1", true)},
+			queuedRequests:   "9",
+			expectedReplicas: 3,
+			expectScaleTime:  true,
+		},
+		"Scale down is delayed": {
+			count:            1,
+			status:           &v1alpha1.AutoscalingStatus{DesiredReplicas: 3, LastScaleTime: lo.ToPtr(metav1.Now())},
+			pods:             []*corev1.Pod{mockInferencePod("pod-1", true), mockInferencePod("pod-2", true)},
+			queuedRequests:   "0",
+			expectedReplicas: 3,
+		},
+		"Scale down after the delay": {
+			count:            1,
+			status:           &v1alpha1.AutoscalingStatus{DesiredReplicas: 3, LastScaleTime: lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour)))},
+			pods:             []*corev1.Pod{mockInferencePod("pod-1", true), mockInferencePod("pod-2", true)},
+			queuedRequests:   "0",
+			expectedReplicas: 1,
+			expectScaleTime:  true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			relevantMap := mockClient.CreateMapWithType(&corev1.PodList{})
+			for _, pod := range tc.pods {
+				relevantMap[client.ObjectKeyFromObject(pod)] = pod
+			}
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client:  mockClient,
+				Scheme:  test.NewTestScheme(),
+				Scraper: mockMetricsScraper(tc.queuedRequests),
+			}
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Resource.Count = lo.ToPtr(tc.count)
+			wObj.Inference.Autoscaling = &v1alpha1.AutoscalingSpec{
+				MinReplicas: lo.ToPtr(1),
+				MaxReplicas: 4,
+				Metric: v1alpha1.AutoscalingMetricSpec{
+					Name:        v1alpha1.AutoscalingMetricQueuedRequests,
+					TargetValue: "3",
+				},
+				ScaleDownDelaySeconds: lo.ToPtr(int32(300)),
+			}
+			wObj.Status.Autoscaling = tc.status.DeepCopy()
+			var previousScaleTime *metav1.Time
+			if tc.status != nil {
+				previousScaleTime = tc.status.LastScaleTime
+			}
+
+			err := reconciler.applyAutoscaling(context.Background(), wObj)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedReplicas, *wObj.Resource.Count)
+			assert.Equal(t, tc.expectedReplicas, wObj.Status.Autoscaling.DesiredReplicas)
+			if tc.expectScaleTime {
+				assert.Assert(t, wObj.Status.Autoscaling.LastScaleTime != nil && wObj.Status.Autoscaling.LastScaleTime != previousScaleTime)
+			} else {
+				assert.DeepEqual(t, previousScaleTime, wObj.Status.Autoscaling.LastScaleTime)
+			}
+		})
+	}
+}
+
+func TestIgnorePeriodicStatusUpdates(t *testing.T) {
+	oldObj := test.MockWorkspaceWithPreset.DeepCopy()
+	oldObj.ResourceVersion = "1"
+
+	autoscalingUpdate := oldObj.DeepCopy()
+	autoscalingUpdate.ResourceVersion = "2"
+	autoscalingUpdate.Status.Autoscaling = &v1alpha1.AutoscalingStatus{DesiredReplicas: 2, CurrentMetricValue: "3"}
+	assert.Equal(t, false, ignorePeriodicStatusUpdates().Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: autoscalingUpdate}))
+
+	conditionUpdate := oldObj.DeepCopy()
+	conditionUpdate.ResourceVersion = "2"
+	conditionUpdate.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.WorkspaceConditionTypeSucceeded), Status: metav1.ConditionTrue}}
+	assert.Equal(t, true, ignorePeriodicStatusUpdates().Update(event.UpdateEvent{ObjectOld: oldObj, ObjectNew: conditionUpdate}))
+}
@@ -11,12 +11,12 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
-	"strings"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/kaito-project/kaito/pkg/featuregates"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
 	"github.com/kaito-project/kaito/pkg/workspace/tuning"
@@ -30,27 +30,32 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/machine"
 	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
 	"github.com/kaito-project/kaito/pkg/workspace/inference"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/clock"
-	"knative.dev/pkg/apis"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 const (
@@ -64,6 +69,11 @@ type WorkspaceReconciler struct {
 	Log      logr.Logger
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// Scraper collects inference metrics for autoscaling and scale-to-zero workspaces.
+	Scraper *autoscaler.Scraper
+	// ActivatorAddress and ActivatorPort are where the activator receives the requests sent to idle workspaces.
+	ActivatorAddress string
+	ActivatorPort    int32
 }
 
 func NewWorkspaceReconciler(client client.Client, scheme *runtime.Scheme, log logr.Logger, Recorder record.EventRecorder) *WorkspaceReconciler {
@@ -72,6 +82,7 @@ func NewWorkspaceReconciler(client client.Client, scheme *runtime.Scheme, log lo
 		Scheme:   scheme,
 		Log:      log,
 		Recorder: Recorder,
+		Scraper:  autoscaler.NewScraper(),
 	}
 }
 
@@ -94,6 +105,10 @@ func (c *WorkspaceReconciler) Reconcile(ctx context.Context, req reconcile.Reque
 		return c.deleteWorkspace(ctx, workspaceObj)
 	}
 
+	if err := c.rollbackWorkspace(ctx, workspaceObj); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	if err := c.syncControllerRevision(ctx, workspaceObj); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -126,28 +141,59 @@ func (c *WorkspaceReconciler) ensureFinalizer(ctx context.Context, workspaceObj
 }
 
 func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
-	// Read ResourceSpec
-	err := c.applyWorkspaceResource(ctx, wObj)
+	suspended, err := c.syncSuspendState(ctx, wObj)
 	if err != nil {
-		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-			"workspaceFailed", err.Error()); updateErr != nil {
-			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return reconcile.Result{}, updateErr
+		klog.ErrorS(err, "failed to sync suspend state", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, err
+	}
+	if suspended {
+		return reconcile.Result{}, nil
+	}
+
+	if err = c.resolveInstanceType(ctx, wObj); err != nil {
+		klog.ErrorS(err, "failed to resolve instance type", "workspace", klog.KObj(wObj))
+		return c.failWorkspace(ctx, wObj, err)
+	}
+
+	if wObj.Inference != nil {
+		idle, err := c.syncIdleState(ctx, wObj)
+		if err != nil {
+			klog.ErrorS(err, "failed to sync idle state", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
 		}
-		// If the error is due to machine/nodeClaim instance types unavailability, stop reconcile.
-		if err.Error() == consts.ErrorInstanceTypesUnavailable {
-			return reconcile.Result{Requeue: false}, err
+		// An idle workspace is woken up by the activator, there is nothing else to reconcile.
+		if idle {
+			return reconcile.Result{}, nil
 		}
-		return reconcile.Result{}, err
+	}
+
+	if isAutoscalingEnabled(wObj) {
+		// The desired replica count overrides Resource.Count for the rest of the reconcile loop.
+		if err := c.applyAutoscaling(ctx, wObj); err != nil {
+			klog.ErrorS(err, "failed to apply autoscaling", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Read ResourceSpec
+	err = c.applyWorkspaceResource(ctx, wObj)
+	if isPending(err) {
+		return c.requeuePendingWorkspace(ctx, wObj, err)
+	}
+	if err != nil {
+		return c.failWorkspace(ctx, wObj, err)
 	}
 
 	if wObj.Tuning != nil {
-		if err = c.applyTuning(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return reconcile.Result{}, updateErr
-			}
+		err = c.applyTuning(ctx, wObj)
+		if isPending(err) {
+			return c.requeuePendingWorkspace(ctx, wObj, err)
+		}
+		if err != nil {
+			return c.failWorkspace(ctx, wObj, err)
+		}
+		if err = c.updateStatusCurrentRevisionIfNotMatch(ctx, wObj); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return reconcile.Result{}, err
 		}
 		// Only mark workspace succeeded when job completes.
@@ -175,33 +221,62 @@ func (c *WorkspaceReconciler) addOrUpdateWorkspace(ctx context.Context, wObj *ka
 			return reconcile.Result{}, err
 		}
 	} else if wObj.Inference != nil {
-		if err := c.ensureService(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return reconcile.Result{}, updateErr
+		for _, ensure := range []func(context.Context, *kaitov1alpha1.Workspace) error{
+			c.ensureService, c.ensureExposure, c.ensureNetworkPolicy, c.ensurePodDisruptionBudget,
+		} {
+			if err := ensure(ctx, wObj); err != nil {
+				return c.failWorkspace(ctx, wObj, err)
 			}
-			return reconcile.Result{}, err
 		}
-		if err = c.applyInference(ctx, wObj); err != nil {
-			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
-				"workspaceFailed", err.Error()); updateErr != nil {
-				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return reconcile.Result{}, updateErr
-			}
+		err = c.applyInference(ctx, wObj)
+		if isPending(err) {
+			return c.requeuePendingWorkspace(ctx, wObj, err)
+		}
+		if err != nil {
+			return c.failWorkspace(ctx, wObj, err)
+		}
+		if err = c.updateStatusCurrentRevisionIfNotMatch(ctx, wObj); err != nil {
+			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return reconcile.Result{}, err
 		}
+		// Nodes are released only after the workload has been scaled down to the selected nodes.
+		if err = c.releaseSurplusNodes(ctx, wObj); err != nil {
+			return c.failWorkspace(ctx, wObj, err)
+		}
 
 		if err = c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionTrue,
 			"workspaceSucceeded", "workspace succeeds"); err != nil {
 			klog.ErrorS(err, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return reconcile.Result{}, err
 		}
+		if err = c.finishWaking(ctx, wObj); err != nil {
+			klog.ErrorS(err, "failed to finish waking up workspace", "workspace", klog.KObj(wObj))
+			return reconcile.Result{}, err
+		}
+
+		// Metrics are not watched, poll them periodically.
+		if isAutoscalingEnabled(wObj) {
+			return reconcile.Result{RequeueAfter: autoscalingSyncPeriod}, nil
+		}
+		if isScaleToZeroEnabled(wObj) {
+			return reconcile.Result{RequeueAfter: idleSyncPeriod}, nil
+		}
 	}
 
 	return reconcile.Result{}, nil
 }
 
+// failWorkspace records the error in the WorkspaceSucceeded condition and returns it, so that the workspace is
+// reconciled again with backoff.
+func (c *WorkspaceReconciler) failWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace, err error) (reconcile.Result, error) {
+	if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+		"workspaceFailed", err.Error()); updateErr != nil {
+		klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, updateErr
+	}
+	return reconcile.Result{}, err
+}
+
 func (c *WorkspaceReconciler) deleteWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) (reconcile.Result, error) {
 	klog.InfoS("deleteWorkspace", "workspace", klog.KObj(wObj))
 	err := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeDeleting, metav1.ConditionTrue, "workspaceDeleted", "workspace is being deleted")
@@ -299,9 +374,10 @@ func (c *WorkspaceReconciler) syncControllerRevision(ctx context.Context, wObj *
 
 func marshalSelectedFields(wObj *kaitov1alpha1.Workspace) ([]byte, error) {
 	partialMap := map[string]interface{}{
-		"resource":  wObj.Resource,
-		"inference": wObj.Inference,
-		"tuning":    wObj.Tuning,
+		"resource":   wObj.Resource,
+		"scheduling": wObj.Scheduling,
+		"inference":  wObj.Inference,
+		"tuning":     wObj.Tuning,
 	}
 
 	jsonData, err := json.Marshal(partialMap)
@@ -318,19 +394,39 @@ func computeHash(w *kaitov1alpha1.Workspace) string {
 	encoder.Encode(w.Resource)
 	encoder.Encode(w.Inference)
 	encoder.Encode(w.Tuning)
+	// The scheduling spec is only hashed if it is set, so that the hash of the workspaces without it is unchanged.
+	if w.Scheduling != nil {
+		encoder.Encode(w.Scheduling)
+	}
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
-// applyWorkspaceResource applies workspace resource spec.
+// applyWorkspaceResource applies workspace resource spec. All the missing nodes are created at once and the
+// function does not wait for them to be ready, a pending error is returned instead and the workspace is reconciled
+// again when the machines/nodeClaims change.
 func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
-	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-		// Wait for pending nodeClaims if any before we decide whether to create new node or not.
-		if err := nodeclaim.WaitForPendingNodeClaims(ctx, wObj, c.Client); err != nil {
-			return err
-		}
-	} else {
-		// Wait for pending machines if any before we decide whether to create new machine or not.
-		if err := machine.WaitForPendingMachines(ctx, wObj, c.Client); err != nil {
+	// List the machines/nodeClaims created for the workspace, including the ones still being provisioned.
+	provisioningNodes, err := c.listProvisioningNodes(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	if wObj.Resource.SharedNodePool != "" {
+		// The shared nodes of other instance types are provisioned for other workspaces of the pool.
+		provisioningNodes = lo.Filter(provisioningNodes, func(node provisioningNode, _ int) bool {
+			return node.instanceType == wObj.Resource.InstanceType
+		})
+	}
+
+	// Replace the nodes which have been deleted or have become NotReady, new ones are created below.
+	provisioningNodes, err = c.replaceUnhealthyNodes(ctx, wObj, provisioningNodes)
+	if err != nil {
+		return err
+	}
+
+	// Fall back to the next instance type if nodes of the current one cannot be launched. The nodes of the previous
+	// instance type are replaced below.
+	if isInstanceTypeUnavailable(wObj, provisioningNodes) {
+		if err := c.fallbackInstanceType(ctx, wObj); err != nil {
 			return err
 		}
 	}
@@ -341,9 +437,22 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		return err
 	}
 
+	pendingCount, err := c.syncNodeProvisioning(ctx, wObj, provisioningNodes, validNodes)
+	if err != nil {
+		return err
+	}
+
+	// Shared nodes whose GPUs are used up by other workspaces cannot be selected, new nodes are created instead.
+	if wObj.Resource.SharedNodePool != "" {
+		validNodes, err = c.getSharedNodesWithFreeGPUs(ctx, wObj, validNodes)
+		if err != nil {
+			return err
+		}
+	}
+
 	selectedNodes := utils.SelectNodes(validNodes, wObj.Resource.PreferredNodes, wObj.Status.WorkerNodes, lo.FromPtr(wObj.Resource.Count))
 
-	newNodesCount := lo.FromPtr(wObj.Resource.Count) - len(selectedNodes)
+	newNodesCount := lo.FromPtr(wObj.Resource.Count) - len(selectedNodes) - pendingCount
 
 	if newNodesCount > 0 {
 		klog.InfoS("need to create more nodes", "NodeCount", newNodesCount)
@@ -362,8 +471,7 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		}
 
 		for i := 0; i < newNodesCount; i++ {
-			newNode, err := c.createAndValidateNode(ctx, wObj)
-			if err != nil {
+			if err := c.createNode(ctx, wObj); err != nil {
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
 					"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
@@ -371,17 +479,30 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 				}
 				return err
 			}
-			selectedNodes = append(selectedNodes, newNode)
 		}
 	}
 
+	if missing := lo.FromPtr(wObj.Resource.Count) - len(selectedNodes); missing > 0 {
+		message := fmt.Sprintf("waiting for %d nodes to be ready", missing)
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
+			"workspaceResourceStatusPending", message); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return updateErr
+		}
+		return newPendingError("%s", message)
+	}
+
 	// Ensure all gpu plugins are running successfully.
-	if strings.Contains(wObj.Resource.InstanceType, consts.GpuSkuPrefix) { // GPU skus
+	if utils.GetGPUConfigBySKU(wObj.Resource.InstanceType) != nil { // GPU skus
 		for i := range selectedNodes {
 			err = c.ensureNodePlugins(ctx, wObj, selectedNodes[i])
 			if err != nil {
+				reason := "workspaceResourceStatusFailed"
+				if isPending(err) {
+					reason = "workspaceResourceStatusPending"
+				}
 				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeResourceStatus, metav1.ConditionFalse,
-					"workspaceResourceStatusFailed", err.Error()); updateErr != nil {
+					reason, err.Error()); updateErr != nil {
 					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
 					return updateErr
 				}
@@ -421,13 +542,18 @@ func (c *WorkspaceReconciler) applyWorkspaceResource(ctx context.Context, wObj *
 		return err
 	}
 
-	return nil
+	// All the nodes are ready, including the ones which replaced unhealthy nodes.
+	return c.resetNodeReplacedCondition(ctx, wObj)
 }
 
 func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) ([]*corev1.Node, error) {
 	var qualifiedNodes []*corev1.Node
 
-	nodeList, err := resources.ListNodes(ctx, c.Client, wObj.Resource.LabelSelector.MatchLabels)
+	nodeSelector, err := resources.GetNodeSelector(wObj.Resource.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodeList, err := resources.ListNodes(ctx, c.Client, nodeSelector)
 	if err != nil {
 		return nil, err
 	}
@@ -469,8 +595,8 @@ func (c *WorkspaceReconciler) getAllQualifiedNodes(ctx context.Context, wObj *ka
 	return qualifiedNodes, nil
 }
 
-// createAndValidateNode creates a new node and validates status.
-func (c *WorkspaceReconciler) createAndValidateNode(ctx context.Context, wObj *kaitov1alpha1.Workspace) (*corev1.Node, error) {
+// createNode creates a new machine/nodeClaim, its readiness is checked in the following reconciles.
+func (c *WorkspaceReconciler) createNode(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
 	var nodeOSDiskSize string
 	if wObj.Inference != nil && wObj.Inference.Preset != nil && wObj.Inference.Preset.Name != "" {
 		presetName := string(wObj.Inference.Preset.Name)
@@ -488,7 +614,7 @@ func (c *WorkspaceReconciler) createAndValidateNode(ctx context.Context, wObj *k
 	}
 }
 
-func (c *WorkspaceReconciler) CreateMachine(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) (*corev1.Node, error) {
+func (c *WorkspaceReconciler) CreateMachine(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) error {
 RetryWithDifferentName:
 	newMachine := machine.GenerateMachineManifest(ctx, nodeOSDiskSize, wObj)
 
@@ -502,28 +628,15 @@ RetryWithDifferentName:
 			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
 				"machineFailedCreation", err.Error()); updateErr != nil {
 				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return nil, updateErr
+				return updateErr
 			}
-			return nil, err
-		}
-	}
-
-	// check machine status until it is ready
-	err := machine.CheckMachineStatus(ctx, newMachine, c.Client)
-	if err != nil {
-		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
-			"checkMachineStatusFailed", err.Error()); updateErr != nil {
-			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return nil, updateErr
+			return err
 		}
-		return nil, err
 	}
-
-	// get the node object from the machine status nodeName.
-	return resources.GetNode(ctx, newMachine.Status.NodeName, c.Client)
+	return nil
 }
 
-func (c *WorkspaceReconciler) CreateNodeClaim(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) (*corev1.Node, error) {
+func (c *WorkspaceReconciler) CreateNodeClaim(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeOSDiskSize string) error {
 RetryWithDifferentName:
 	newNodeClaim := nodeclaim.GenerateNodeClaimManifest(ctx, nodeOSDiskSize, wObj)
 
@@ -537,66 +650,45 @@ RetryWithDifferentName:
 			if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
 				"nodeClaimFailedCreation", err.Error()); updateErr != nil {
 				klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-				return nil, updateErr
+				return updateErr
 			}
-			return nil, err
-		}
-	}
-
-	// check nodeClaim status until it is ready
-	err := nodeclaim.CheckNodeClaimStatus(ctx, newNodeClaim, c.Client)
-	if err != nil {
-		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
-			"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
-			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-			return nil, updateErr
+			return err
 		}
-		return nil, err
 	}
-
-	// get the node object from the nodeClaim status nodeName.
-	return resources.GetNode(ctx, newNodeClaim.Status.NodeName, c.Client)
+	return nil
 }
 
-// ensureNodePlugins ensures node plugins are installed.
+// ensureNodePlugins ensures node plugins are installed. The node is labeled with the GPU vendor so that the device
+// plugin is deployed to it, and a pending error is returned until the node advertises the GPU resource. The node
+// status update triggers the next reconcile.
 func (c *WorkspaceReconciler) ensureNodePlugins(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeObj *corev1.Node) error {
-	timeClock := clock.RealClock{}
-	tick := timeClock.NewTicker(consts.NodePluginInstallTimeout)
-	defer tick.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-tick.C():
-			return fmt.Errorf("node plugin installation timed out. node %s is not ready", nodeObj.Name)
-		default:
-			//Nvidia Plugin
-			if found := resources.CheckNvidiaPlugin(ctx, nodeObj); !found {
-				if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyNvidia, resources.LabelValueNvidia, c.Client); err != nil {
-					if apierrors.IsNotFound(err) {
-						klog.ErrorS(err, "nvidia plugin cannot be installed, node not found", "node", nodeObj.Name)
-						if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-							if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
-								"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
-								klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-								return updateErr
-							}
-						} else {
-							if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
-								"checkMachineStatusFailed", err.Error()); updateErr != nil {
-								klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
-								return updateErr
-							}
-						}
-						return err
-					}
+	// Device plugin of the GPU vendor
+	vendor := utils.GetGPUVendor(wObj.Resource.InstanceType)
+	// Nodes partitioned into MIG slices advertise the slices instead of whole GPUs.
+	vendor.ResourceName = string(resources.GetGPUResourceName(wObj, vendor))
+	if resources.CheckGPUPlugin(ctx, nodeObj, vendor) {
+		return nil
+	}
+	if err := resources.UpdateNodeWithLabel(ctx, nodeObj.Name, resources.LabelKeyAccelerator, vendor.Name, c.Client); err != nil {
+		if apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "gpu plugin cannot be installed, node not found", "node", nodeObj.Name, "vendor", vendor.Name)
+			if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeNodeClaimStatus, metav1.ConditionFalse,
+					"checkNodeClaimStatusFailed", err.Error()); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+					return updateErr
+				}
+			} else {
+				if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.ConditionTypeMachineStatus, metav1.ConditionFalse,
+					"checkMachineStatusFailed", err.Error()); updateErr != nil {
+					klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+					return updateErr
 				}
-				time.Sleep(1 * time.Second)
 			}
-			return nil
 		}
+		return err
 	}
+	return newPendingError("waiting for the %s device plugin on node %s", vendor.Name, nodeObj.Name)
 }
 
 // getPresetName returns the preset name from wObj if available
@@ -681,7 +773,7 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alph
 					existingObj = workloadObj.(*batchv1.Job)
 				}
 
-				if err = resources.CheckResourceStatus(existingObj, c.Client, tuningParam.ReadinessTimeout); err != nil {
+				if err = checkWorkloadStatus(wObj, existingObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus, tuningParam.ReadinessTimeout); err != nil {
 					return
 				}
 			} else if apierrors.IsNotFound(err) {
@@ -691,7 +783,7 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alph
 				if err != nil {
 					return
 				}
-				if err = resources.CheckResourceStatus(workloadObj, c.Client, tuningParam.ReadinessTimeout); err != nil {
+				if err = checkWorkloadStatus(wObj, workloadObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus, tuningParam.ReadinessTimeout); err != nil {
 					return
 				}
 			}
@@ -699,8 +791,12 @@ func (c *WorkspaceReconciler) applyTuning(ctx context.Context, wObj *kaitov1alph
 	}()
 
 	if err != nil {
+		reason := "WorkspaceTuningJobStatusFailed"
+		if isPending(err) {
+			reason = "WorkspaceTuningJobStatusPending"
+		}
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus, metav1.ConditionFalse,
-			"WorkspaceTuningJobStatusFailed", err.Error()); updateErr != nil {
+			reason, err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return updateErr
 		}
@@ -722,12 +818,25 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 	func() {
 		if wObj.Inference.Template != nil {
 			var workloadObj client.Object
-			// TODO: handle update
-			workloadObj, err = inference.CreateTemplateInference(ctx, wObj, c.Client)
-			if err != nil {
+			revisionStr := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
+			deployment := &appsv1.Deployment{}
+			if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, deployment); err == nil {
+				if err = c.updateTemplateInferenceIfNotMatch(ctx, wObj, deployment, revisionStr); err != nil {
+					return
+				}
+				workloadObj = deployment
+			} else if apierrors.IsNotFound(err) {
+				workloadObj, err = inference.CreateTemplateInference(ctx, wObj, revisionStr, c.Client)
+				if err != nil {
+					return
+				}
+			} else {
 				return
 			}
-			if err = resources.CheckResourceStatus(workloadObj, c.Client, time.Duration(10)*time.Minute); err != nil {
+			if err = c.syncInferenceReplicas(ctx, wObj); err != nil {
+				return
+			}
+			if err = checkWorkloadStatus(wObj, workloadObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, time.Duration(10)*time.Minute); err != nil {
 				return
 			}
 		} else if wObj.Inference != nil && wObj.Inference.Preset != nil {
@@ -746,43 +855,10 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 			revisionStr := wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation]
 			if err = resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existingObj); err == nil {
 				klog.InfoS("An inference workload already exists for workspace", "workspace", klog.KObj(wObj))
-				if !model.SupportDistributedInference() {
-					deployment := existingObj.(*appsv1.Deployment)
-					if deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] != revisionStr {
-						// TODO: respect updates to other fields
-						var volumes []corev1.Volume
-						var volumeMounts []corev1.VolumeMount
-						shmVolume, shmVolumeMount := utils.ConfigSHMVolume(*wObj.Resource.Count)
-						if shmVolume.Name != "" {
-							volumes = append(volumes, shmVolume)
-						}
-						if shmVolumeMount.Name != "" {
-							volumeMounts = append(volumeMounts, shmVolumeMount)
-						}
-
-						if len(wObj.Inference.Adapters) > 0 {
-							adapterVolume, adapterVolumeMount := utils.ConfigAdapterVolume()
-							volumes = append(volumes, adapterVolume)
-							volumeMounts = append(volumeMounts, adapterVolumeMount)
-						}
-						initContainers, envs := manifests.GenerateInitContainers(wObj, volumeMounts)
-						spec := &deployment.Spec
-
-						spec.Template.Spec.InitContainers = initContainers
-						spec.Template.Spec.Containers[0].Env = envs
-						spec.Template.Spec.Containers[0].VolumeMounts = volumeMounts
-						deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] = revisionStr
-						spec.Template.Spec.Volumes = volumes
-
-						_, imagePullSecrets := inference.GetInferenceImageInfo(ctx, wObj, inferenceParam)
-						deployment.Spec.Template.Spec.ImagePullSecrets = imagePullSecrets
-
-						if err := c.Update(ctx, deployment); err != nil {
-							return
-						}
-					}
+				if err = c.updatePresetInferenceIfNotMatch(ctx, wObj, existingObj, revisionStr, model); err != nil {
+					return
 				}
-				if err = resources.CheckResourceStatus(existingObj, c.Client, inferenceParam.ReadinessTimeout); err != nil {
+				if err = checkWorkloadStatus(wObj, existingObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, inferenceParam.ReadinessTimeout); err != nil {
 					return
 				}
 			} else if apierrors.IsNotFound(err) {
@@ -792,7 +868,7 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 				if err != nil {
 					return
 				}
-				if err = resources.CheckResourceStatus(workloadObj, c.Client, inferenceParam.ReadinessTimeout); err != nil {
+				if err = checkWorkloadStatus(wObj, workloadObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, inferenceParam.ReadinessTimeout); err != nil {
 					return
 				}
 			}
@@ -800,8 +876,12 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 	}()
 
 	if err != nil {
+		reason := "WorkspaceInferenceStatusFailed"
+		if isPending(err) {
+			reason = "WorkspaceInferenceStatusPending"
+		}
 		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
-			"WorkspaceInferenceStatusFailed", err.Error()); updateErr != nil {
+			reason, err.Error()); updateErr != nil {
 			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
 			return updateErr
 		} else {
@@ -817,6 +897,103 @@ func (c *WorkspaceReconciler) applyInference(ctx context.Context, wObj *kaitov1a
 	return nil
 }
 
+// updatePresetInferenceIfNotMatch updates the existing preset inference workload to the manifest generated from the
+// workspace spec. The manifest is compared with the live object ignoring the fields defaulted by the API server, and
+// with the template hash recorded on the workload, so that changes made by a new controller version, e.g., a new
+// image tag or a removed argument, are rolled out as well. Deployments are updated with a rolling update and
+// StatefulSets update their pods one at a time in reverse ordinal order.
+func (c *WorkspaceReconciler) updatePresetInferenceIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, existingObj client.Object,
+	revisionStr string, model pkgmodel.Model) error {
+	desiredObj, err := inference.GeneratePresetInference(ctx, wObj, revisionStr, model, c.Client)
+	if err != nil {
+		return err
+	}
+
+	templateHash := desiredObj.GetAnnotations()[kaitov1alpha1.WorkspaceTemplateHashAnnotation]
+	upToDate := existingObj.GetAnnotations()[kaitov1alpha1.WorkspaceRevisionAnnotation] == revisionStr &&
+		existingObj.GetAnnotations()[kaitov1alpha1.WorkspaceTemplateHashAnnotation] == templateHash
+	switch existing := existingObj.(type) {
+	case *appsv1.Deployment:
+		desired, ok := desiredObj.(*appsv1.Deployment)
+		if !ok {
+			return fmt.Errorf("inference workload of workspace %s is a Deployment, expected %T", wObj.Name, desiredObj)
+		}
+		upToDate = upToDate && lo.FromPtr(existing.Spec.Replicas) == lo.FromPtr(desired.Spec.Replicas) &&
+			equality.Semantic.DeepDerivative(desired.Spec.Strategy, existing.Spec.Strategy) &&
+			equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template)
+		existing.Spec.Replicas = desired.Spec.Replicas
+		existing.Spec.Strategy = desired.Spec.Strategy
+		existing.Spec.Template = desired.Spec.Template
+	case *appsv1.StatefulSet:
+		desired, ok := desiredObj.(*appsv1.StatefulSet)
+		if !ok {
+			return fmt.Errorf("inference workload of workspace %s is a StatefulSet, expected %T", wObj.Name, desiredObj)
+		}
+		// The selector, the service name and the pod management policy are immutable.
+		upToDate = upToDate && lo.FromPtr(existing.Spec.Replicas) == lo.FromPtr(desired.Spec.Replicas) &&
+			equality.Semantic.DeepDerivative(desired.Spec.UpdateStrategy, existing.Spec.UpdateStrategy) &&
+			equality.Semantic.DeepDerivative(desired.Spec.Template, existing.Spec.Template)
+		existing.Spec.Replicas = desired.Spec.Replicas
+		existing.Spec.UpdateStrategy = desired.Spec.UpdateStrategy
+		existing.Spec.Template = desired.Spec.Template
+	default:
+		return fmt.Errorf("unsupported inference workload type %T", existingObj)
+	}
+	if upToDate {
+		return nil
+	}
+
+	klog.InfoS("Updating inference workload", "workspace", klog.KObj(wObj), "revision", revisionStr)
+	annotations := existingObj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] = revisionStr
+	annotations[kaitov1alpha1.WorkspaceTemplateHashAnnotation] = templateHash
+	existingObj.SetAnnotations(annotations)
+	return c.Update(ctx, existingObj)
+}
+
+// updateTemplateInferenceIfNotMatch patches the inference Deployment of a template workspace when the pod template
+// has changed, which is detected through the revision annotation. The node affinity and the tolerations injected by
+// KAITO are kept. The rollout progress is reported in the InferenceReady condition.
+func (c *WorkspaceReconciler) updateTemplateInferenceIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, deployment *appsv1.Deployment,
+	revisionStr string) error {
+	if deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] == revisionStr {
+		return nil
+	}
+
+	klog.InfoS("Updating template inference workload", "workspace", klog.KObj(wObj), "revision", revisionStr)
+	desired := inference.GenerateTemplateInference(ctx, wObj, revisionStr)
+	patch := client.MergeFrom(deployment.DeepCopy())
+	deployment.Spec.Replicas = desired.Spec.Replicas
+	deployment.Spec.Template = desired.Spec.Template
+	if deployment.Annotations == nil {
+		deployment.Annotations = make(map[string]string)
+	}
+	deployment.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] = revisionStr
+	if err := c.Patch(ctx, deployment, patch); err != nil {
+		return err
+	}
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInferenceStatus, metav1.ConditionFalse,
+		"WorkspaceInferenceRolloutInProgress", fmt.Sprintf("Rolling out revision %s of the inference template", revisionStr))
+}
+
+// syncInferenceReplicas scales the inference Deployment to the workspace node count.
+func (c *WorkspaceReconciler) syncInferenceReplicas(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	deployment := &appsv1.Deployment{}
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, deployment); err != nil {
+		return err
+	}
+	replicas := int32(lo.FromPtr(wObj.Resource.Count))
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == replicas {
+		return nil
+	}
+	klog.InfoS("Scaling inference workload", "workspace", klog.KObj(wObj), "replicas", replicas)
+	deployment.Spec.Replicas = lo.ToPtr(replicas)
+	return c.Update(ctx, deployment)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	c.Recorder = mgr.GetEventRecorderFor("Workspace")
@@ -828,20 +1005,34 @@ func (c *WorkspaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
-	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&kaitov1alpha1.Workspace{}).
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&kaitov1alpha1.Workspace{}, builder.WithPredicates(ignorePeriodicStatusUpdates())).
 		Owns(&appsv1.ControllerRevision{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
 		Owns(&batchv1.Job{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&networkingv1.Ingress{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: 5})
 
+	// HTTPRoutes can only be watched if the Gateway API CRDs are installed.
+	httpRouteKind := schema.GroupKind{Group: gatewayv1.GroupName, Kind: "HTTPRoute"}
+	if _, err := mgr.GetRESTMapper().RESTMapping(httpRouteKind, gatewayv1.GroupVersion.Version); err == nil {
+		b.Owns(&gatewayv1.HTTPRoute{})
+	} else if meta.IsNoMatchError(err) {
+		klog.InfoS("Gateway API CRDs are not installed, HTTPRoutes are not watched")
+	} else {
+		return err
+	}
+
 	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
-		builder.Watches(&v1beta1.NodeClaim{}, c.watchNodeClaims()) // watches for nodeClaim with labels indicating workspace name.
+		b.Watches(&v1beta1.NodeClaim{}, c.watchNodeClaims()) // watches for nodeClaim with labels indicating workspace name.
 	} else {
-		builder.Watches(&v1alpha5.Machine{}, c.watchMachines())
+		b.Watches(&v1alpha5.Machine{}, c.watchMachines())
 	}
-	return builder.Complete(c)
+	b.Watches(&corev1.Node{}, c.watchNodes(), builder.WithPredicates(nodeStatusChanged()))
+	return b.Complete(c)
 }
 
 // watches for machine with labels indicating workspace name.
@@ -849,6 +1040,9 @@ func (c *WorkspaceReconciler) watchMachines() handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(
 		func(ctx context.Context, o client.Object) []reconcile.Request {
 			machineObj := o.(*v1alpha5.Machine)
+			if pool, ok := machineObj.Labels[kaitov1alpha1.LabelSharedNodePool]; ok {
+				return c.getSharedNodePoolRequests(ctx, pool)
+			}
 			name, ok := machineObj.Labels[kaitov1alpha1.LabelWorkspaceName]
 			if !ok {
 				return nil
@@ -857,14 +1051,6 @@ func (c *WorkspaceReconciler) watchMachines() handler.EventHandler {
 			if !ok {
 				return nil
 			}
-			_, conditionFound := lo.Find(machineObj.GetConditions(), func(condition apis.Condition) bool {
-				return condition.Type == apis.ConditionReady &&
-					condition.Status == v1.ConditionTrue
-			})
-			if conditionFound && machineObj.DeletionTimestamp.IsZero() {
-				// No need to reconcile workspace if the machine is in READY state unless machine is deleted.
-				return nil
-			}
 			return []reconcile.Request{
 				{
 					NamespacedName: client.ObjectKey{
@@ -881,6 +1067,9 @@ func (c *WorkspaceReconciler) watchNodeClaims() handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(
 		func(ctx context.Context, o client.Object) []reconcile.Request {
 			nodeClaimObj := o.(*v1beta1.NodeClaim)
+			if pool, ok := nodeClaimObj.Labels[kaitov1alpha1.LabelSharedNodePool]; ok {
+				return c.getSharedNodePoolRequests(ctx, pool)
+			}
 			name, ok := nodeClaimObj.Labels[kaitov1alpha1.LabelWorkspaceName]
 			if !ok {
 				return nil
@@ -21,11 +21,15 @@ import (
 	"github.com/kaito-project/kaito/pkg/featuregates"
 	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
 	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -320,11 +324,10 @@ func TestSelectWorkspaceNodes(t *testing.T) {
 	}
 }
 
-func TestCreateAndValidateMachineNode(t *testing.T) {
+func TestCreateMachineNode(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
 		callMocks             func(c *test.MockClient)
-		objectConditions      apis.Conditions
 		workspace             v1alpha1.Workspace
 		karpenterFeatureGates bool
 		expectedError         error
@@ -332,32 +335,17 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 	}{
 		"Node is not created because machine creation fails": {
 			callMocks: func(c *test.MockClient) {
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(errors.New("failed to create machine"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
-			objectConditions: apis.Conditions{
-				{
-					Type:    v1alpha5.MachineLaunched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
 			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+			expectedError: errors.New("failed to create machine"),
 		},
 		"A machine is successfully created": {
 			callMocks: func(c *test.MockClient) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
-			},
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
 			},
 			workspace:     *test.MockWorkspaceDistributedModel,
 			expectedError: nil,
@@ -368,15 +356,8 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
-			},
-			cloudProvider: consts.AzureCloudName,
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
 			},
+			cloudProvider:         consts.AzureCloudName,
 			workspace:             *test.MockWorkspaceDistributedModel,
 			karpenterFeatureGates: true,
 			expectedError:         nil,
@@ -387,60 +368,17 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&awsv1beta1.EC2NodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
-			},
-			cloudProvider: consts.AWSCloudName,
-			objectConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
 			},
+			cloudProvider:         consts.AWSCloudName,
 			workspace:             *test.MockWorkspaceDistributedModel,
 			karpenterFeatureGates: true,
 			expectedError:         nil,
 		},
-		"Node is not created because nodeClaim creation fails": {
-			callMocks: func(c *test.MockClient) {
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
-				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
-			},
-			cloudProvider: consts.AzureCloudName,
-			objectConditions: apis.Conditions{
-				{
-					Type:    v1beta1.Launched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
-			workspace:             *test.MockWorkspaceWithPreset,
-			karpenterFeatureGates: true,
-			expectedError:         errors.New(consts.ErrorInstanceTypesUnavailable),
-		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
-			mockMachine := &v1alpha5.Machine{}
-			mockNodeClaim := &v1beta1.NodeClaim{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(mockMachine, key)
-				mockMachine.Status.Conditions = tc.objectConditions
-				mockClient.CreateOrUpdateObjectInMap(mockMachine)
-
-				if tc.karpenterFeatureGates {
-					mockClient.GetObjectFromMap(mockNodeClaim, key)
-					mockNodeClaim.Status.Conditions = tc.objectConditions
-					mockClient.CreateOrUpdateObjectInMap(mockNodeClaim)
-				}
-			}
-
 			if tc.cloudProvider != "" {
 				t.Setenv("CLOUD_PROVIDER", tc.cloudProvider)
 
@@ -455,10 +393,9 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 			ctx := context.Background()
 			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = tc.karpenterFeatureGates
 
-			node, err := reconciler.createAndValidateNode(ctx, &tc.workspace)
+			err := reconciler.createNode(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
-				assert.Check(t, node != nil, "Response node should not be nil")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -466,13 +403,12 @@ func TestCreateAndValidateMachineNode(t *testing.T) {
 	}
 }
 
-func TestCreateAndValidateNodeClaimNode(t *testing.T) {
+func TestCreateNodeClaimNode(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
 		callMocks             func(c *test.MockClient)
 		cloudProvider         string
 		karpenterFeatureGates bool
-		nodeClaimConditions   apis.Conditions
 		workspace             v1alpha1.Workspace
 		expectedError         error
 	}{
@@ -480,22 +416,14 @@ func TestCreateAndValidateNodeClaimNode(t *testing.T) {
 			callMocks: func(c *test.MockClient) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
-				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(errors.New("failed to create nodeClaim"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
 			cloudProvider:         consts.AzureCloudName,
 			karpenterFeatureGates: true,
-			nodeClaimConditions: apis.Conditions{
-				{
-					Type:    v1beta1.Launched,
-					Status:  corev1.ConditionFalse,
-					Message: consts.ErrorInstanceTypesUnavailable,
-				},
-			},
-			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+			workspace:             *test.MockWorkspaceWithPreset,
+			expectedError:         errors.New("failed to create nodeClaim"),
 		},
 		"A nodeClaim is successfully created": {
 			callMocks: func(c *test.MockClient) {
@@ -503,32 +431,17 @@ func TestCreateAndValidateNodeClaimNode(t *testing.T) {
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&azurev1alpha2.AKSNodeClass{}), mock.Anything).Return(nil)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
-				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
 			},
 			cloudProvider:         consts.AzureCloudName,
 			karpenterFeatureGates: true,
-			nodeClaimConditions: apis.Conditions{
-				{
-					Type:   apis.ConditionReady,
-					Status: corev1.ConditionTrue,
-				},
-			},
-			workspace:     *test.MockWorkspaceDistributedModel,
-			expectedError: nil,
+			workspace:             *test.MockWorkspaceDistributedModel,
+			expectedError:         nil,
 		},
 	}
 
 	for k, tc := range testcases {
 		t.Run(k, func(t *testing.T) {
 			mockClient := test.NewClient()
-			mockNodeClaim := &v1beta1.NodeClaim{}
-
-			mockClient.UpdateCb = func(key types.NamespacedName) {
-				mockClient.GetObjectFromMap(mockNodeClaim, key)
-				mockNodeClaim.Status.Conditions = tc.nodeClaimConditions
-				mockClient.CreateOrUpdateObjectInMap(mockNodeClaim)
-			}
-
 			if tc.cloudProvider != "" {
 				t.Setenv("CLOUD_PROVIDER", tc.cloudProvider)
 
@@ -544,10 +457,9 @@ func TestCreateAndValidateNodeClaimNode(t *testing.T) {
 			}
 			ctx := context.Background()
 
-			node, err := reconciler.createAndValidateNode(ctx, &tc.workspace)
+			err := reconciler.createNode(ctx, &tc.workspace)
 			if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
-				assert.Check(t, node != nil, "Response node should not be nil")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
@@ -620,9 +532,10 @@ func TestEnsureService(t *testing.T) {
 func TestApplyInferenceWithPreset(t *testing.T) {
 	test.RegisterTestModel()
 	testcases := map[string]struct {
-		callMocks     func(c *test.MockClient)
-		workspace     v1alpha1.Workspace
-		expectedError error
+		callMocks       func(c *test.MockClient)
+		workspace       v1alpha1.Workspace
+		expectedError   error
+		expectedPending bool
 	}{
 		"Fail to get inference because associated workload with workspace cannot be retrieved": {
 			callMocks: func(c *test.MockClient) {
@@ -652,8 +565,8 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
-			workspace:     *test.MockWorkspaceWithPreset,
-			expectedError: nil,
+			workspace:       *test.MockWorkspaceWithPreset,
+			expectedPending: true,
 		},
 		"Apply inference from existing workload": {
 			callMocks: func(c *test.MockClient) {
@@ -661,11 +574,16 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 					depObj := &appsv1.StatefulSet{}
 					key := client.ObjectKey{Namespace: "kaito", Name: "testWorkspace"}
 					c.GetObjectFromMap(depObj, key)
-					numRep := int32(1)
+					numRep := int32(*test.MockWorkspaceDistributedModel.Resource.Count)
 					depObj.Status.ReadyReplicas = numRep
+					depObj.Status.UpdatedReplicas = numRep
 					depObj.Spec.Replicas = &numRep
 					c.CreateOrUpdateObjectInMap(depObj)
+					*args.Get(2).(*appsv1.StatefulSet) = *depObj
 				})
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Service{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(nil)
 
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -683,6 +601,7 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 						*dep = test.MockDeploymentUpdated
 					}).
 					Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
 
 				c.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
 
@@ -708,7 +627,9 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 			t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
 
 			err := reconciler.applyInference(ctx, &tc.workspace)
-			if tc.expectedError == nil {
+			if tc.expectedPending {
+				assert.Check(t, isPending(err), fmt.Sprintf("Expected a pending error: %v", err))
+			} else if tc.expectedError == nil {
 				assert.Check(t, err == nil, fmt.Sprintf("Not expected to return error: %v", err))
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
@@ -717,14 +638,124 @@ func TestApplyInferenceWithPreset(t *testing.T) {
 	}
 }
 
+func TestUpdatePresetInferenceIfNotMatch(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+	wObj := test.MockWorkspaceWithPreset.DeepCopy()
+	wObj.Annotations = map[string]string{v1alpha1.WorkspaceRevisionAnnotation: "2"}
+	model := plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name))
+
+	mockClient := test.NewClient()
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+	reconciler := &WorkspaceReconciler{
+		Client: mockClient,
+		Scheme: test.NewTestScheme(),
+	}
+	desiredObj, err := inference.GeneratePresetInference(context.Background(), wObj, "2", model, mockClient)
+	assert.NilError(t, err)
+	desired := desiredObj.(*appsv1.Deployment)
+
+	testcases := map[string]struct {
+		mutate         func(dep *appsv1.Deployment)
+		expectedUpdate bool
+	}{
+		"Fields defaulted by the API server are ignored": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.DNSPolicy = corev1.DNSClusterFirst
+				dep.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullIfNotPresent
+				dep.Spec.Template.Spec.Containers[0].TerminationMessagePath = corev1.TerminationMessagePathDefault
+			},
+			expectedUpdate: false,
+		},
+		"Revision has changed": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Annotations[v1alpha1.WorkspaceRevisionAnnotation] = "1"
+			},
+			expectedUpdate: true,
+		},
+		"Image tag has changed": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Image = "test-registry/test-image:0.0.1"
+			},
+			expectedUpdate: true,
+		},
+		"Resources have changed": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{}
+			},
+			expectedUpdate: true,
+		},
+		"Probes have changed": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].ReadinessProbe.InitialDelaySeconds = 5
+			},
+			expectedUpdate: true,
+		},
+		"Argument has been removed from the manifest": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Template.Spec.Containers[0].Args = append(dep.Spec.Template.Spec.Containers[0].Args, "--removed-flag")
+				dep.Spec.Template.Spec.Containers[0].Env = append(dep.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "REMOVED", Value: "true"})
+				dep.Annotations[v1alpha1.WorkspaceTemplateHashAnnotation] = "previous-template-hash"
+			},
+			expectedUpdate: true,
+		},
+		"Template hash is missing": {
+			mutate: func(dep *appsv1.Deployment) {
+				delete(dep.Annotations, v1alpha1.WorkspaceTemplateHashAnnotation)
+			},
+			expectedUpdate: true,
+		},
+		"Replicas have changed": {
+			mutate: func(dep *appsv1.Deployment) {
+				dep.Spec.Replicas = lo.ToPtr(int32(5))
+			},
+			expectedUpdate: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient.ExpectedCalls = lo.Filter(mockClient.ExpectedCalls, func(call *mock.Call, _ int) bool { return call.Method != "Update" })
+			mockClient.Calls = nil
+			var updated *appsv1.Deployment
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Run(func(args mock.Arguments) {
+				updated = args.Get(1).(*appsv1.Deployment)
+			}).Return(nil)
+
+			existing := desired.DeepCopy()
+			tc.mutate(existing)
+			assert.NilError(t, reconciler.updatePresetInferenceIfNotMatch(context.Background(), wObj, existing, "2", model))
+
+			if tc.expectedUpdate {
+				mockClient.AssertNumberOfCalls(t, "Update", 1)
+				assert.Equal(t, "2", updated.Annotations[v1alpha1.WorkspaceRevisionAnnotation])
+				assert.Equal(t, desired.Annotations[v1alpha1.WorkspaceTemplateHashAnnotation], updated.Annotations[v1alpha1.WorkspaceTemplateHashAnnotation])
+				assert.Assert(t, equality.Semantic.DeepEqual(desired.Spec, updated.Spec))
+			} else {
+				mockClient.AssertNumberOfCalls(t, "Update", 0)
+			}
+		})
+	}
+}
+
 func TestApplyInferenceWithTemplate(t *testing.T) {
+	existingDeployment := func(revision string) *appsv1.Deployment {
+		dep := inference.GenerateTemplateInference(context.Background(), test.MockWorkspaceWithInferenceTemplate, revision)
+		dep.Status.ReadyReplicas = *dep.Spec.Replicas
+		dep.Status.UpdatedReplicas = *dep.Spec.Replicas
+		return dep
+	}
+	var patchedDeployment *appsv1.Deployment
 	testcases := map[string]struct {
-		callMocks     func(c *test.MockClient)
-		workspace     v1alpha1.Workspace
-		expectedError error
+		callMocks       func(c *test.MockClient)
+		workspace       v1alpha1.Workspace
+		expectedError   error
+		expectedPending bool
+		expectedPatches int
 	}{
 		"Fail to apply inference from workspace template": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(test.NotFoundError())
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(errors.New("Failed to create deployment"))
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
@@ -734,13 +765,50 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 		},
 		"Apply inference from workspace template": {
 			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(test.NotFoundError()).Times(4)
 				c.On("Create", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
 				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
 				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
 			},
-			workspace:     *test.MockWorkspaceWithInferenceTemplate,
-			expectedError: nil,
+			workspace:       *test.MockWorkspaceWithInferenceTemplate,
+			expectedPending: true,
+		},
+		"Workspace template has not changed": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(existingDeployment("1"))
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace: func() v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithInferenceTemplate.DeepCopy()
+				ws.Annotations = map[string]string{v1alpha1.WorkspaceRevisionAnnotation: "1"}
+				return *ws
+			}(),
+			expectedError:   nil,
+			expectedPatches: 0,
+		},
+		"Update inference from changed workspace template": {
+			callMocks: func(c *test.MockClient) {
+				c.CreateOrUpdateObjectInMap(existingDeployment("1"))
+				c.On("Get", mock.Anything, mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+				c.On("Patch", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+					patched := args.Get(1).(*appsv1.Deployment)
+					patched.Generation++
+					patchedDeployment = patched.DeepCopy()
+				}).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace: func() v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithInferenceTemplate.DeepCopy()
+				ws.Annotations = map[string]string{v1alpha1.WorkspaceRevisionAnnotation: "2"}
+				ws.Inference.Template = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx:1.27"}}}}
+				return *ws
+			}(),
+			expectedPending: true,
+			expectedPatches: 1,
 		},
 	}
 
@@ -764,11 +832,21 @@ func TestApplyInferenceWithTemplate(t *testing.T) {
 			ctx := context.Background()
 
 			err := reconciler.applyInference(ctx, &tc.workspace)
-			if tc.expectedError == nil {
+			if tc.expectedPending {
+				assert.Check(t, isPending(err), "Expected a pending error")
+			} else if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
+			mockClient.AssertNumberOfCalls(t, "Patch", tc.expectedPatches)
+			if tc.expectedPatches > 0 {
+				assert.Equal(t, tc.workspace.Annotations[v1alpha1.WorkspaceRevisionAnnotation], patchedDeployment.Annotations[v1alpha1.WorkspaceRevisionAnnotation])
+				assert.Equal(t, "nginx:1.27", patchedDeployment.Spec.Template.Spec.Containers[0].Image)
+				// The node affinity and the tolerations injected by KAITO are kept.
+				assert.Assert(t, patchedDeployment.Spec.Template.Spec.Affinity != nil)
+				assert.Assert(t, len(patchedDeployment.Spec.Template.Spec.Tolerations) > 0)
+			}
 		})
 	}
 }
@@ -926,6 +1004,7 @@ func TestApplyWorkspaceResource(t *testing.T) {
 		callMocks                   func(c *test.MockClient)
 		karpenterFeatureGateEnabled bool
 		expectedError               error
+		expectedPending             bool
 		workspace                   v1alpha1.Workspace
 	}{
 		"Fail to apply workspace because associated machines cannot be retrieved": {
@@ -1042,6 +1121,40 @@ func TestApplyWorkspaceResource(t *testing.T) {
 			workspace:                   *test.MockWorkspaceDistributedModel,
 			expectedError:               nil,
 		},
+		"Wait for the machine being provisioned without creating a new one": {
+			callMocks: func(c *test.MockClient) {
+				relevantMap := c.CreateMapWithType(test.MockMachineList)
+				m := test.MockMachine
+				relevantMap[client.ObjectKeyFromObject(&m)] = &m
+				c.CreateMapWithType(&corev1.NodeList{})
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.NodeList{}), mock.Anything).Return(nil)
+
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			workspace:       *test.MockWorkspaceDistributedModel,
+			expectedPending: true,
+		},
+		"Fail to apply workspace because the machine instance type is unavailable": {
+			callMocks: func(c *test.MockClient) {
+				relevantMap := c.CreateMapWithType(test.MockMachineList)
+				m := *test.MockMachine.DeepCopy()
+				m.Status.Conditions = apis.Conditions{
+					{
+						Type:    v1alpha5.MachineLaunched,
+						Status:  corev1.ConditionFalse,
+						Message: consts.ErrorInstanceTypesUnavailable,
+					},
+				}
+				relevantMap[client.ObjectKeyFromObject(&m)] = &m
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			},
+			workspace:     *test.MockWorkspaceDistributedModel,
+			expectedError: errors.New(consts.ErrorInstanceTypesUnavailable),
+		},
 	}
 
 	for k, tc := range testcases {
@@ -1080,11 +1193,74 @@ func TestApplyWorkspaceResource(t *testing.T) {
 			ctx := context.Background()
 
 			err := reconciler.applyWorkspaceResource(ctx, &tc.workspace)
-			if tc.expectedError == nil {
+			if tc.expectedPending {
+				assert.Check(t, isPending(err), "Expected a pending error")
+				mockClient.AssertNumberOfCalls(t, "Create", 0)
+			} else if tc.expectedError == nil {
+				assert.Check(t, err == nil, "Not expected to return error")
+			} else {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			}
+		})
+	}
+}
+
+func TestEnsureNodePlugins(t *testing.T) {
+	testcases := map[string]struct {
+		callMocks       func(c *test.MockClient)
+		node            *corev1.Node
+		expectedPending bool
+		expectedError   error
+		expectedUpdates int
+	}{
+		"Node advertises the GPUs of the device plugin": {
+			callMocks:       func(c *test.MockClient) {},
+			node:            test.MockNodeList.Items[0].DeepCopy(),
+			expectedUpdates: 0,
+		},
+		"Label the node and wait for the device plugin": {
+			callMocks: func(c *test.MockClient) {
+				node := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-without-plugin"}}
+				c.CreateOrUpdateObjectInMap(node)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+				c.On("Update", mock.IsType(context.Background()), mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+			},
+			node:            &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-without-plugin"}},
+			expectedPending: true,
+			expectedUpdates: 1,
+		},
+		"Node has been deleted": {
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).
+					Return(apierrors.NewNotFound(corev1.Resource("nodes"), "deleted-node"))
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			},
+			node:          &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "deleted-node"}},
+			expectedError: apierrors.NewNotFound(corev1.Resource("nodes"), "deleted-node"),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = false
+
+			err := reconciler.ensureNodePlugins(context.Background(), test.MockWorkspaceDistributedModel.DeepCopy(), tc.node)
+			if tc.expectedPending {
+				assert.Check(t, isPending(err), "Expected a pending error")
+			} else if tc.expectedError == nil {
 				assert.Check(t, err == nil, "Not expected to return error")
 			} else {
 				assert.Equal(t, tc.expectedError.Error(), err.Error())
 			}
+			mockClient.AssertNumberOfCalls(t, "Update", tc.expectedUpdates)
 		})
 	}
 }
@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+)
+
+// ensurePodDisruptionBudget creates the PodDisruptionBudget of the inference workload, or updates it if the
+// disruption budget of the workspace has changed. It is owned by the workspace and deleted along with it.
+func (c *WorkspaceReconciler) ensurePodDisruptionBudget(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	supportsDistributedInference := false
+	if presetName := getPresetName(wObj); presetName != "" {
+		supportsDistributedInference = plugin.KaitoModelRegister.MustGet(presetName).SupportDistributedInference()
+	}
+	desired := manifests.GeneratePodDisruptionBudgetManifest(ctx, wObj, supportsDistributedInference)
+
+	existing := &policyv1.PodDisruptionBudget{}
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := resources.CreateResource(ctx, desired, c.Client); err != nil {
+			return err
+		}
+		c.warnIfEvictionsBlocked(wObj, desired)
+		return nil
+	}
+	if equality.Semantic.DeepEqual(desired.Spec, existing.Spec) {
+		return nil
+	}
+
+	klog.InfoS("Updating PodDisruptionBudget", "workspace", klog.KObj(wObj))
+	existing.Spec = desired.Spec
+	if err := c.Update(ctx, existing); err != nil {
+		return err
+	}
+	c.warnIfEvictionsBlocked(wObj, existing)
+	return nil
+}
+
+// warnIfEvictionsBlocked records a warning event when the budget allows no eviction of the inference pods, since
+// the nodes running them can then neither be drained, upgraded nor consolidated.
+func (c *WorkspaceReconciler) warnIfEvictionsBlocked(wObj *kaitov1alpha1.Workspace, pdb *policyv1.PodDisruptionBudget) {
+	blocked := false
+	if maxUnavailable := pdb.Spec.MaxUnavailable; maxUnavailable != nil {
+		blocked = maxUnavailable.String() == "0" || maxUnavailable.String() == "0%"
+	}
+	if minAvailable := pdb.Spec.MinAvailable; minAvailable != nil {
+		blocked = minAvailable.String() == "100%"
+	}
+	if !blocked {
+		return
+	}
+	c.Recorder.Event(wObj, corev1.EventTypeWarning, "EvictionsBlocked",
+		fmt.Sprintf("PodDisruptionBudget %s allows no eviction, the nodes of the workspace cannot be drained, upgraded or consolidated, "+
+			"set inference.disruptionBudget.maxUnavailable to 1 to allow it", pdb.Name))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEnsurePodDisruptionBudget(t *testing.T) {
+	test.RegisterTestModel()
+	testcases := map[string]struct {
+		workspace              *v1alpha1.Workspace
+		existing               func(wObj *v1alpha1.Workspace) *policyv1.PodDisruptionBudget
+		expectedCreate         bool
+		expectedUpdate         bool
+		expectedMaxUnavailable *intstr.IntOrString
+		expectedMinAvailable   *intstr.IntOrString
+		expectedWarning        bool
+	}{
+		"Creates the default budget of a Deployment": {
+			workspace:              test.MockWorkspaceWithPreset.DeepCopy(),
+			expectedCreate:         true,
+			expectedMaxUnavailable: lo.ToPtr(intstr.FromInt32(1)),
+		},
+		"Creates the default budget of a distributed StatefulSet": {
+			workspace:              test.MockWorkspaceDistributedModel.DeepCopy(),
+			expectedCreate:         true,
+			expectedMaxUnavailable: lo.ToPtr(intstr.FromInt32(0)),
+			expectedWarning:        true,
+		},
+		"Distributed StatefulSet opts out of the budget which blocks evictions": {
+			workspace: func() *v1alpha1.Workspace {
+				w := test.MockWorkspaceDistributedModel.DeepCopy()
+				w.Inference.DisruptionBudget = &v1alpha1.DisruptionBudgetSpec{MaxUnavailable: lo.ToPtr(intstr.FromInt32(1))}
+				return w
+			}(),
+			expectedCreate:         true,
+			expectedMaxUnavailable: lo.ToPtr(intstr.FromInt32(1)),
+		},
+		"Creates the budget of the workspace spec": {
+			workspace: func() *v1alpha1.Workspace {
+				w := test.MockWorkspaceWithPreset.DeepCopy()
+				w.Inference.DisruptionBudget = &v1alpha1.DisruptionBudgetSpec{MinAvailable: lo.ToPtr(intstr.FromString("50%"))}
+				return w
+			}(),
+			expectedCreate:       true,
+			expectedMinAvailable: lo.ToPtr(intstr.FromString("50%")),
+		},
+		"Existing budget is up to date": {
+			workspace: test.MockWorkspaceWithPreset.DeepCopy(),
+			existing: func(wObj *v1alpha1.Workspace) *policyv1.PodDisruptionBudget {
+				return manifests.GeneratePodDisruptionBudgetManifest(context.Background(), wObj, false)
+			},
+			expectedMaxUnavailable: lo.ToPtr(intstr.FromInt32(1)),
+		},
+		"Existing budget is updated": {
+			workspace: func() *v1alpha1.Workspace {
+				w := test.MockWorkspaceWithPreset.DeepCopy()
+				w.Inference.DisruptionBudget = &v1alpha1.DisruptionBudgetSpec{MaxUnavailable: lo.ToPtr(intstr.FromInt32(2))}
+				return w
+			}(),
+			existing: func(wObj *v1alpha1.Workspace) *policyv1.PodDisruptionBudget {
+				return manifests.GeneratePodDisruptionBudgetManifest(context.Background(), test.MockWorkspaceWithPreset, false)
+			},
+			expectedUpdate:         true,
+			expectedMaxUnavailable: lo.ToPtr(intstr.FromInt32(2)),
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			if tc.existing != nil {
+				mockClient.CreateOrUpdateObjectInMap(tc.existing(tc.workspace))
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&policyv1.PodDisruptionBudget{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&policyv1.PodDisruptionBudget{}), mock.Anything).Return(test.NotFoundError())
+			}
+			var applied *policyv1.PodDisruptionBudget
+			mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&policyv1.PodDisruptionBudget{}), mock.Anything).
+				Run(func(args mock.Arguments) { applied = args.Get(1).(*policyv1.PodDisruptionBudget) }).Return(nil)
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&policyv1.PodDisruptionBudget{}), mock.Anything).
+				Run(func(args mock.Arguments) { applied = args.Get(1).(*policyv1.PodDisruptionBudget) }).Return(nil)
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+			err := reconciler.ensurePodDisruptionBudget(context.Background(), tc.workspace)
+			assert.Check(t, err == nil, "Not expected to return error")
+
+			if tc.expectedCreate {
+				mockClient.AssertCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				mockClient.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tc.expectedUpdate {
+				mockClient.AssertCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				mockClient.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+			}
+			assert.Equal(t, tc.expectedWarning, len(recorder.Events) == 1)
+			if applied != nil {
+				assert.DeepEqual(t, tc.expectedMaxUnavailable, applied.Spec.MaxUnavailable)
+				assert.DeepEqual(t, tc.expectedMinAvailable, applied.Spec.MinAvailable)
+				assert.Equal(t, tc.workspace.Name, applied.Spec.Selector.MatchLabels[v1alpha1.LabelWorkspaceName])
+			}
+		})
+	}
+}
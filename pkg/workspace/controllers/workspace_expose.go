@@ -0,0 +1,131 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	pkgmodel "github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ensureExposure creates or updates the Ingress, or the HTTPRoute if a Gateway is referenced, which exposes the
+// inference service, and records its external URL in the workspace status. The route which is no longer used,
+// e.g., after switching from an Ingress to a Gateway or removing the expose spec, is deleted.
+func (c *WorkspaceReconciler) ensureExposure(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	expose := wObj.Expose
+	useIngress := expose != nil && expose.GatewayRef == nil
+	useHTTPRoute := expose != nil && expose.GatewayRef != nil
+
+	if useIngress {
+		if err := c.applyRoute(ctx, wObj, manifests.GenerateIngressManifest(ctx, wObj), &networkingv1.Ingress{}); err != nil {
+			return err
+		}
+	} else if err := c.deleteRouteIfExists(ctx, wObj, &networkingv1.Ingress{}); err != nil {
+		return err
+	}
+
+	if useHTTPRoute {
+		route := manifests.GenerateHTTPRouteManifest(ctx, wObj, getServedModelName(wObj))
+		if err := c.applyRoute(ctx, wObj, route, &gatewayv1.HTTPRoute{}); err != nil {
+			return err
+		}
+	} else if err := c.deleteRouteIfExists(ctx, wObj, &gatewayv1.HTTPRoute{}); err != nil {
+		return err
+	}
+
+	externalURL := ""
+	if expose != nil {
+		externalURL = fmt.Sprintf("%s://%s%s", c.getExposeScheme(ctx, wObj), expose.Hostname, lo.Ternary(expose.PathPrefix == "", "/", expose.PathPrefix))
+	}
+	return c.updateStatusExternalURLIfNotMatch(ctx, wObj, externalURL)
+}
+
+// applyRoute creates the desired Ingress or HTTPRoute, or updates the existing one if its spec does not match. The
+// fields defaulted by the API server, e.g., the group, kind and weight of the backend refs, are not compared.
+func (c *WorkspaceReconciler) applyRoute(ctx context.Context, wObj *kaitov1alpha1.Workspace, desired, existing client.Object) error {
+	if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+
+	switch existingRoute := existing.(type) {
+	case *networkingv1.Ingress:
+		desiredRoute := desired.(*networkingv1.Ingress)
+		if equality.Semantic.DeepDerivative(desiredRoute.Spec, existingRoute.Spec) {
+			return nil
+		}
+		existingRoute.Spec = desiredRoute.Spec
+	case *gatewayv1.HTTPRoute:
+		desiredRoute := desired.(*gatewayv1.HTTPRoute)
+		if equality.Semantic.DeepDerivative(desiredRoute.Spec, existingRoute.Spec) {
+			return nil
+		}
+		existingRoute.Spec = desiredRoute.Spec
+	}
+	klog.InfoS("Updating route of the inference service", "workspace", klog.KObj(wObj), "kind", fmt.Sprintf("%T", existing))
+	return c.Update(ctx, existing)
+}
+
+// deleteRouteIfExists deletes the Ingress or HTTPRoute of the workspace. The HTTPRoute kind is not served if the
+// Gateway API CRDs are not installed, in which case there is nothing to delete.
+func (c *WorkspaceReconciler) deleteRouteIfExists(ctx context.Context, wObj *kaitov1alpha1.Workspace, route client.Object) error {
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, route); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	klog.InfoS("Deleting route of the inference service", "workspace", klog.KObj(wObj), "kind", fmt.Sprintf("%T", route))
+	return client.IgnoreNotFound(c.Delete(ctx, route))
+}
+
+// getExposeScheme returns the scheme of the external URL. An Ingress serves HTTPS if a TLS secret is specified, and a
+// Gateway if the listeners which the HTTPRoute is attached to use the HTTPS protocol.
+func (c *WorkspaceReconciler) getExposeScheme(ctx context.Context, wObj *kaitov1alpha1.Workspace) string {
+	expose := wObj.Expose
+	if expose.GatewayRef == nil {
+		return lo.Ternary(expose.TLSSecretName != "", "https", "http")
+	}
+
+	gateway := &gatewayv1.Gateway{}
+	namespace := lo.Ternary(expose.GatewayRef.Namespace != "", expose.GatewayRef.Namespace, wObj.Namespace)
+	if err := c.Client.Get(ctx, client.ObjectKey{Name: expose.GatewayRef.Name, Namespace: namespace}, gateway); err != nil {
+		klog.ErrorS(err, "failed to get gateway, assuming HTTP", "workspace", klog.KObj(wObj), "gateway", expose.GatewayRef.Name)
+		return "http"
+	}
+	httpsListener := lo.ContainsBy(gateway.Spec.Listeners, func(listener gatewayv1.Listener) bool {
+		return (expose.GatewayRef.SectionName == "" || string(listener.Name) == expose.GatewayRef.SectionName) &&
+			listener.Protocol == gatewayv1.HTTPSProtocolType
+	})
+	return lo.Ternary(httpsListener, "https", "http")
+}
+
+// getServedModelName returns the model name which the preset inference service serves, i.e., the model field of
+// the OpenAI-compatible requests it accepts.
+func getServedModelName(wObj *kaitov1alpha1.Workspace) string {
+	presetName := getPresetName(wObj)
+	if presetName == "" {
+		return ""
+	}
+	params := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters()
+	if kaitov1alpha1.GetWorkspaceRuntimeName(wObj) == pkgmodel.RuntimeNameVLLM && params.VLLM.ModelName != "" {
+		return params.VLLM.ModelName
+	}
+	return presetName
+}
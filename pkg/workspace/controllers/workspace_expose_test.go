@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestEnsureExposure(t *testing.T) {
+	test.RegisterTestModel()
+	ingress := &v1alpha1.ExposeSpec{Hostname: "llm.example.com", TLSSecretName: "llm-tls"}
+	gateway := &v1alpha1.ExposeSpec{
+		Hostname:   "llm.example.com",
+		PathPrefix: "/falcon",
+		GatewayRef: &v1alpha1.GatewayReference{Name: "inference-gateway", SectionName: "https"},
+	}
+	testcases := map[string]struct {
+		expose          *v1alpha1.ExposeSpec
+		existingIngress *v1alpha1.ExposeSpec
+		existingRoute   *v1alpha1.ExposeSpec
+		routeDefaulted  bool
+		gatewayProtocol gatewayv1.ProtocolType
+		expectedCreates int
+		expectedUpdates int
+		expectedDeletes int
+		expectedURL     string
+	}{
+		"Workspace without expose spec": {},
+		"Creates the ingress": {
+			expose:          ingress,
+			expectedCreates: 1,
+			expectedURL:     "https://llm.example.com/",
+		},
+		"Existing ingress is up to date": {
+			expose:          ingress,
+			existingIngress: ingress,
+			expectedURL:     "https://llm.example.com/",
+		},
+		"Existing ingress is updated": {
+			expose:          &v1alpha1.ExposeSpec{Hostname: "chat.example.com"},
+			existingIngress: ingress,
+			expectedUpdates: 1,
+			expectedURL:     "http://chat.example.com/",
+		},
+		"Creates the http route of an HTTPS listener and deletes the ingress": {
+			expose:          gateway,
+			existingIngress: ingress,
+			gatewayProtocol: gatewayv1.HTTPSProtocolType,
+			expectedCreates: 1,
+			expectedDeletes: 1,
+			expectedURL:     "https://llm.example.com/falcon",
+		},
+		"Creates the http route of an HTTP listener": {
+			expose:          gateway,
+			gatewayProtocol: gatewayv1.HTTPProtocolType,
+			expectedCreates: 1,
+			expectedURL:     "http://llm.example.com/falcon",
+		},
+		"Existing http route defaulted by the API server is up to date": {
+			expose:          gateway,
+			existingRoute:   gateway,
+			routeDefaulted:  true,
+			gatewayProtocol: gatewayv1.HTTPSProtocolType,
+			expectedURL:     "https://llm.example.com/falcon",
+		},
+		"Http route is deleted once the expose spec is removed": {
+			existingRoute:   gateway,
+			expectedDeletes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			mockClient.CreateOrUpdateObjectInMap(wObj)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			if tc.existingIngress != nil {
+				wObj.Expose = tc.existingIngress
+				mockClient.CreateOrUpdateObjectInMap(manifests.GenerateIngressManifest(context.Background(), wObj))
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.Ingress{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&networkingv1.Ingress{}), mock.Anything).Return(test.NotFoundError())
+			}
+			if tc.existingRoute != nil {
+				wObj.Expose = tc.existingRoute
+				route := manifests.GenerateHTTPRouteManifest(context.Background(), wObj, "test-model")
+				if tc.routeDefaulted {
+					for i := range route.Spec.Rules {
+						for j := range route.Spec.Rules[i].BackendRefs {
+							backendRef := &route.Spec.Rules[i].BackendRefs[j]
+							backendRef.Group = lo.ToPtr(gatewayv1.Group(""))
+							backendRef.Kind = lo.ToPtr(gatewayv1.Kind("Service"))
+							backendRef.Weight = lo.ToPtr(int32(1))
+						}
+					}
+				}
+				mockClient.CreateOrUpdateObjectInMap(route)
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&gatewayv1.HTTPRoute{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&gatewayv1.HTTPRoute{}), mock.Anything).Return(test.NotFoundError())
+			}
+			mockClient.CreateOrUpdateObjectInMap(&gatewayv1.Gateway{
+				ObjectMeta: metav1.ObjectMeta{Name: "inference-gateway", Namespace: wObj.Namespace},
+				Spec: gatewayv1.GatewaySpec{
+					Listeners: []gatewayv1.Listener{{Name: "https", Protocol: tc.gatewayProtocol}},
+				},
+			})
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&gatewayv1.Gateway{}), mock.Anything).Return(nil)
+			wObj.Expose = tc.expose
+
+			mockClient.On("Create", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("Update", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.Anything, mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			err := reconciler.ensureExposure(context.Background(), wObj)
+			assert.Check(t, err == nil, "Not expected to return error")
+
+			mockClient.AssertNumberOfCalls(t, "Create", tc.expectedCreates)
+			mockClient.AssertNumberOfCalls(t, "Update", tc.expectedUpdates)
+			mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+			assert.Equal(t, tc.expectedURL, wObj.Status.ExternalURL)
+		})
+	}
+}
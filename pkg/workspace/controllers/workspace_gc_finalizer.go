@@ -11,6 +11,9 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/consts"
 	"github.com/kaito-project/kaito/pkg/utils/machine"
 	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -21,44 +24,142 @@ import (
 func (c *WorkspaceReconciler) garbageCollectWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) (ctrl.Result, error) {
 	klog.InfoS("garbageCollectWorkspace", "workspace", klog.KObj(wObj))
 
+	if err := c.deleteWorkspaceNodes(ctx, wObj); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if controllerutil.RemoveFinalizer(wObj, consts.WorkspaceFinalizer) {
+		if updateErr := c.Update(ctx, wObj, &client.UpdateOptions{}); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to remove the finalizer from the workspace",
+				"workspace", klog.KObj(wObj))
+			return ctrl.Result{}, updateErr
+		}
+	}
+
+	klog.InfoS("successfully removed the workspace finalizers",
+		"workspace", klog.KObj(wObj))
+	return ctrl.Result{}, nil
+}
+
+// deleteWorkspaceNodes deletes all the machines or nodeClaims created by the workspace.
+func (c *WorkspaceReconciler) deleteWorkspaceNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	// Shared nodes are only deleted once no other workspace uses them.
+	if wObj.Resource.SharedNodePool != "" {
+		return c.releaseSharedNodes(ctx, wObj)
+	}
 	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
 		// Check if there are any nodeClaims associated with this workspace.
 		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
 
 		// We should delete all the nodeClaims that are created by this workspace
 		for i := range ncList.Items {
 			if deleteErr := c.Delete(ctx, &ncList.Items[i], &client.DeleteOptions{}); deleteErr != nil {
 				klog.ErrorS(deleteErr, "failed to delete the nodeClaim", "nodeClaim", klog.KObj(&ncList.Items[i]))
-				return ctrl.Result{}, deleteErr
+				return deleteErr
 			}
 		}
 	} else {
 		// Check if there are any machines associated with this workspace.
 		mList, err := machine.ListMachines(ctx, wObj, c.Client)
 		if err != nil {
-			return ctrl.Result{}, err
+			return err
 		}
 		// We should delete all the machines that are created by this workspace
 		for i := range mList.Items {
 			if deleteErr := c.Delete(ctx, &mList.Items[i], &client.DeleteOptions{}); deleteErr != nil {
 				klog.ErrorS(deleteErr, "failed to delete the machine", "machine", klog.KObj(&mList.Items[i]))
-				return ctrl.Result{}, deleteErr
+				return deleteErr
 			}
 		}
 	}
+	return nil
+}
 
-	if controllerutil.RemoveFinalizer(wObj, consts.WorkspaceFinalizer) {
-		if updateErr := c.Update(ctx, wObj, &client.UpdateOptions{}); updateErr != nil {
-			klog.ErrorS(updateErr, "failed to remove the finalizer from the workspace",
-				"workspace", klog.KObj(wObj))
-			return ctrl.Result{}, updateErr
+// releaseSurplusNodes deletes the machines/nodeClaims created by the workspace whose nodes are no longer
+// selected to run the workload, e.g., after the workspace node count is scaled down. The workload controller
+// chooses which pods to remove on scale down, so once it is done the selected nodes follow the nodes which still
+// host pods of the workspace, and a node is only released if no pod of the workspace runs on it.
+func (c *WorkspaceReconciler) releaseSurplusNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	// Never release every node of a workspace, the selected node list must be known first.
+	if len(wObj.Status.WorkerNodes) == 0 {
+		return nil
+	}
+	if wObj.Resource.SharedNodePool != "" {
+		return c.releaseSharedNodes(ctx, wObj)
+	}
+
+	hostingNodes, settled, err := c.getWorkspacePodNodes(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	workerNodes := sets.New(wObj.Status.WorkerNodes...)
+	if settled && hostingNodes.Len() == lo.FromPtr(wObj.Resource.Count) && !hostingNodes.Equal(workerNodes) {
+		if err := c.updateStatusWorkerNodes(ctx, wObj, sets.List(hostingNodes)); err != nil {
+			return err
 		}
+		workerNodes = hostingNodes
 	}
 
-	klog.InfoS("successfully removed the workspace finalizers",
-		"workspace", klog.KObj(wObj))
-	return ctrl.Result{}, nil
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return err
+		}
+		for i := range ncList.Items {
+			nodeName := ncList.Items[i].Status.NodeName
+			if nodeName == "" || workerNodes.Has(nodeName) || hostingNodes.Has(nodeName) || !ncList.Items[i].DeletionTimestamp.IsZero() {
+				continue
+			}
+			klog.InfoS("releasing surplus nodeClaim", "nodeClaim", klog.KObj(&ncList.Items[i]), "node", nodeName, "workspace", klog.KObj(wObj))
+			if deleteErr := c.Delete(ctx, &ncList.Items[i], &client.DeleteOptions{}); client.IgnoreNotFound(deleteErr) != nil {
+				klog.ErrorS(deleteErr, "failed to delete the nodeClaim", "nodeClaim", klog.KObj(&ncList.Items[i]))
+				return deleteErr
+			}
+		}
+		return nil
+	}
+
+	mList, err := machine.ListMachines(ctx, wObj, c.Client)
+	if err != nil {
+		return err
+	}
+	for i := range mList.Items {
+		nodeName := mList.Items[i].Status.NodeName
+		if nodeName == "" || workerNodes.Has(nodeName) || hostingNodes.Has(nodeName) || !mList.Items[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		klog.InfoS("releasing surplus machine", "machine", klog.KObj(&mList.Items[i]), "node", nodeName, "workspace", klog.KObj(wObj))
+		if deleteErr := c.Delete(ctx, &mList.Items[i], &client.DeleteOptions{}); client.IgnoreNotFound(deleteErr) != nil {
+			klog.ErrorS(deleteErr, "failed to delete the machine", "machine", klog.KObj(&mList.Items[i]))
+			return deleteErr
+		}
+	}
+	return nil
+}
+
+// getWorkspacePodNodes returns the nodes which run pods of the workspace that have not terminated. The pod
+// placement is settled if no pod of the workspace is being deleted or waits to be scheduled.
+func (c *WorkspaceReconciler) getWorkspacePodNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) (sets.Set[string], bool, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace), client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name}); err != nil {
+		return nil, false, err
+	}
+	nodes := sets.New[string]()
+	settled := true
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isWorkspacePod(wObj, pod) || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if pod.Spec.NodeName == "" || !pod.DeletionTimestamp.IsZero() {
+			settled = false
+		}
+		if pod.Spec.NodeName != "" {
+			nodes.Insert(pod.Spec.NodeName)
+		}
+	}
+	return nodes, settled, nil
 }
@@ -3,7 +3,9 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
 	"github.com/kaito-project/kaito/api/v1alpha1"
@@ -12,6 +14,8 @@ import (
 	"github.com/kaito-project/kaito/pkg/utils/test"
 	"github.com/stretchr/testify/mock"
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
 )
@@ -81,6 +85,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(errors.New("failed to delete nodeClaim"))
 
 			},
@@ -145,6 +150,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 			},
 			karpenterFeatureGates: true,
@@ -170,6 +176,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 			},
 			karpenterFeatureGates: true,
@@ -202,6 +209,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 			},
 			karpenterFeatureGates: true,
@@ -234,6 +242,7 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
 				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
 				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
 			},
 			karpenterFeatureGates: true,
@@ -264,3 +273,169 @@ func TestGarbageCollectWorkspace(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseSurplusNodes(t *testing.T) {
+	testcases := map[string]struct {
+		callMocks             func(c *test.MockClient)
+		workerNodes           []string
+		karpenterFeatureGates bool
+		expectedWorkerNodes   []string
+		expectedDeletes       int
+		expectedError         error
+	}{
+		"No worker nodes selected, nothing is released": {
+			callMocks:       func(c *test.MockClient) {},
+			workerNodes:     nil,
+			expectedDeletes: 0,
+			expectedError:   nil,
+		},
+		"Fails to release nodes because associated machines cannot be retrieved": {
+			callMocks: func(c *test.MockClient) {
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(errors.New("failed to list machines"))
+			},
+			workerNodes:   []string{"node1"},
+			expectedError: errors.New("failed to list machines"),
+		},
+		"Release machine whose node is no longer selected": {
+			callMocks: func(c *test.MockClient) {
+				kept := test.MockMachine.DeepCopy()
+				kept.Name = "kept"
+				kept.Status.NodeName = "node1"
+				surplus := test.MockMachine.DeepCopy()
+				surplus.Name = "surplus"
+				surplus.Status.NodeName = "node2"
+				pending := test.MockMachine.DeepCopy()
+				pending.Name = "pending"
+				relevantMap := c.CreateMapWithType(&v1alpha5.MachineList{})
+				for _, m := range []*v1alpha5.Machine{kept, surplus, pending} {
+					relevantMap[client.ObjectKeyFromObject(m)] = m
+				}
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+			},
+			workerNodes:     []string{"node1"},
+			expectedDeletes: 1,
+			expectedError:   nil,
+		},
+		"Keep machine whose node still runs a pod of the workspace during scale down": {
+			callMocks: func(c *test.MockClient) {
+				surplus := test.MockMachine.DeepCopy()
+				surplus.Name = "surplus"
+				surplus.Status.NodeName = "node2"
+				c.CreateMapWithType(&v1alpha5.MachineList{})[client.ObjectKeyFromObject(surplus)] = surplus
+				podMap := c.CreateMapWithType(&corev1.PodList{})
+				for i, nodeName := range []string{"node1", "node2"} {
+					pod := &corev1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      fmt.Sprintf("testWorkspace-%d", i),
+							Namespace: test.MockWorkspaceWithPreset.Namespace,
+							Labels:    map[string]string{v1alpha1.LabelWorkspaceName: test.MockWorkspaceWithPreset.Name},
+						},
+						Spec:   corev1.PodSpec{NodeName: nodeName},
+						Status: corev1.PodStatus{Phase: corev1.PodRunning},
+					}
+					if nodeName == "node2" {
+						pod.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+					}
+					podMap[client.ObjectKeyFromObject(pod)] = pod
+				}
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			},
+			workerNodes:         []string{"node1"},
+			expectedWorkerNodes: []string{"node1"},
+			expectedDeletes:     0,
+			expectedError:       nil,
+		},
+		"Release the selected machine if the pod left after scale down runs on another node": {
+			callMocks: func(c *test.MockClient) {
+				empty := test.MockMachine.DeepCopy()
+				empty.Name = "empty"
+				empty.Status.NodeName = "node1"
+				used := test.MockMachine.DeepCopy()
+				used.Name = "used"
+				used.Status.NodeName = "node2"
+				relevantMap := c.CreateMapWithType(&v1alpha5.MachineList{})
+				for _, m := range []*v1alpha5.Machine{empty, used} {
+					relevantMap[client.ObjectKeyFromObject(m)] = m
+				}
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "testWorkspace-1",
+						Namespace: test.MockWorkspaceWithPreset.Namespace,
+						Labels:    map[string]string{v1alpha1.LabelWorkspaceName: test.MockWorkspaceWithPreset.Name},
+					},
+					Spec:   corev1.PodSpec{NodeName: "node2"},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				}
+				c.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(pod)] = pod
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.MatchedBy(func(m *v1alpha5.Machine) bool {
+					return m.Name == "empty"
+				}), mock.Anything).Return(nil)
+			},
+			workerNodes:         []string{"node1"},
+			expectedWorkerNodes: []string{"node2"},
+			expectedDeletes:     1,
+			expectedError:       nil,
+		},
+		"Release nodeClaim whose node is no longer selected": {
+			callMocks: func(c *test.MockClient) {
+				kept := test.MockNodeClaim.DeepCopy()
+				kept.Name = "kept"
+				kept.Status.NodeName = "node1"
+				surplus := test.MockNodeClaim.DeepCopy()
+				surplus.Name = "surplus"
+				surplus.Status.NodeName = "node2"
+				relevantMap := c.CreateMapWithType(&v1beta1.NodeClaimList{})
+				for _, m := range []*v1beta1.NodeClaim{kept, surplus} {
+					relevantMap[client.ObjectKeyFromObject(m)] = m
+				}
+
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaimList{}), mock.Anything).Return(nil)
+				c.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+				c.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1beta1.NodeClaim{}), mock.Anything).Return(nil)
+			},
+			workerNodes:           []string{"node1"},
+			karpenterFeatureGates: true,
+			expectedDeletes:       1,
+			expectedError:         nil,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			tc.callMocks(mockClient)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			ctx := context.Background()
+
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = tc.karpenterFeatureGates
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Status.WorkerNodes = tc.workerNodes
+
+			err := reconciler.releaseSurplusNodes(ctx, wObj)
+			if tc.expectedError == nil {
+				assert.Check(t, err == nil, "Not expected to return error")
+				mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+				if tc.expectedWorkerNodes != nil {
+					assert.DeepEqual(t, tc.expectedWorkerNodes, wObj.Status.WorkerNodes)
+				}
+			} else {
+				assert.Equal(t, tc.expectedError.Error(), err.Error())
+			}
+		})
+	}
+}
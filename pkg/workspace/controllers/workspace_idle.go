@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/kaito-project/kaito/pkg/workspace/activator"
+	"github.com/kaito-project/kaito/pkg/workspace/inference"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	idleSyncPeriod = time.Minute
+	// activityResolution is the minimum interval between two updates of the last activity time.
+	activityResolution = time.Minute
+)
+
+func isScaleToZeroEnabled(wObj *kaitov1alpha1.Workspace) bool {
+	return wObj.Inference != nil && wObj.Inference.IdleTimeout != nil
+}
+
+// syncIdleState scales the inference workload to zero once no request has been observed for the idle timeout.
+// It returns true if the workspace is idle, in which case the rest of the reconcile loop is skipped.
+func (c *WorkspaceReconciler) syncIdleState(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, error) {
+	if meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeIdle)) {
+		if isScaleToZeroEnabled(wObj) {
+			return true, c.scaleToZero(ctx, wObj)
+		}
+		// Scale-to-zero has been disabled, bring the workload back.
+		return false, c.updateIdleStatus(ctx, wObj, false, "ScaleToZeroDisabled", "scale-to-zero is disabled")
+	}
+	if !isScaleToZeroEnabled(wObj) {
+		return false, nil
+	}
+	// The last activity time is refreshed by the activator when the workspace is woken up.
+	if meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeWaking)) {
+		return false, nil
+	}
+
+	// A workload that is not serving yet is never considered idle.
+	active := !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeInferenceStatus))
+	if !active {
+		pods, err := c.getReadyInferencePods(ctx, wObj)
+		if err != nil {
+			return false, err
+		}
+		active = len(pods) > 0 && c.Scraper.Active(ctx, pods, inference.Port5000)
+	}
+
+	lastActivity := wObj.Status.LastActivityTime
+	if lastActivity == nil || (active && time.Since(lastActivity.Time) >= activityResolution) {
+		return false, c.updateStatusLastActivityTime(ctx, wObj, metav1.Now())
+	}
+	if active || time.Since(lastActivity.Time) < wObj.Inference.IdleTimeout.Duration {
+		return false, nil
+	}
+
+	klog.InfoS("Workspace is idle, scaling to zero", "workspace", klog.KObj(wObj), "lastActivityTime", lastActivity.Time)
+	if err := c.scaleToZero(ctx, wObj); err != nil {
+		return true, err
+	}
+	return true, c.updateIdleStatus(ctx, wObj, true, "IdleTimeoutExpired",
+		fmt.Sprintf("no inference request has been received since %s", lastActivity.Format(time.RFC3339)))
+}
+
+// scaleToZero scales the inference workload to zero, releases the nodes created for the workspace and
+// points the workspace Service at the activator. The Service and the workload object are kept.
+func (c *WorkspaceReconciler) scaleToZero(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	for _, workloadObj := range []client.Object{&appsv1.Deployment{}, &appsv1.StatefulSet{}} {
+		if err := resources.GetResource(ctx, wObj.Name, wObj.Namespace, c.Client, workloadObj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		var replicas **int32
+		switch w := workloadObj.(type) {
+		case *appsv1.Deployment:
+			replicas = &w.Spec.Replicas
+		case *appsv1.StatefulSet:
+			replicas = &w.Spec.Replicas
+		}
+		if lo.FromPtr(*replicas) == 0 {
+			continue
+		}
+		*replicas = lo.ToPtr(int32(0))
+		if err := c.Update(ctx, workloadObj); err != nil {
+			return err
+		}
+	}
+
+	if err := c.deleteWorkspaceNodes(ctx, wObj); err != nil {
+		return err
+	}
+	if len(wObj.Status.WorkerNodes) > 0 {
+		if err := c.updateStatusWorkerNodes(ctx, wObj, []string{}); err != nil {
+			return err
+		}
+	}
+
+	if c.ActivatorAddress == "" {
+		klog.InfoS("Activator is not configured, the idle workspace can only be woken up by disabling scale-to-zero", "workspace", klog.KObj(wObj))
+		return nil
+	}
+	return c.ensureActivatorEndpointSlice(ctx, wObj)
+}
+
+// ensureActivatorEndpointSlice creates the EndpointSlice which adds the activator to the workspace Service endpoints,
+// or updates it once the activator has moved to another address, e.g., after the controller pod was recreated.
+func (c *WorkspaceReconciler) ensureActivatorEndpointSlice(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	desired := manifests.GenerateActivatorEndpointSliceManifest(wObj, c.ActivatorAddress, c.ActivatorPort)
+	existing := &discoveryv1.EndpointSlice{}
+	if err := resources.GetResource(ctx, desired.Name, desired.Namespace, c.Client, existing); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+	// The address type of an EndpointSlice is immutable.
+	if existing.AddressType != desired.AddressType {
+		if err := c.Delete(ctx, existing); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		return resources.CreateResource(ctx, desired, c.Client)
+	}
+	if equality.Semantic.DeepEqual(desired.Endpoints, existing.Endpoints) && equality.Semantic.DeepEqual(desired.Ports, existing.Ports) {
+		return nil
+	}
+	klog.InfoS("Updating activator endpoints", "workspace", klog.KObj(wObj), "address", c.ActivatorAddress)
+	existing.Endpoints = desired.Endpoints
+	existing.Ports = desired.Ports
+	return c.Update(ctx, existing)
+}
+
+// finishWaking removes the activator from the workspace Service endpoints once the woken up workload is ready.
+func (c *WorkspaceReconciler) finishWaking(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	if !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeWaking)) {
+		return nil
+	}
+	endpointSlice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-activator", wObj.Name),
+			Namespace: wObj.Namespace,
+		},
+	}
+	if err := c.Delete(ctx, endpointSlice); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeWaking, metav1.ConditionFalse,
+		"WorkspaceAwake", "Inference workload has been brought back")
+}
+
+// updateIdleStatus sets the Idle condition, and the Waking condition when the workspace leaves the idle state.
+func (c *WorkspaceReconciler) updateIdleStatus(ctx context.Context, wObj *kaitov1alpha1.Workspace, idle bool, reason, message string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &kaitov1alpha1.Workspace{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		activator.SetIdleConditions(latest, idle, reason, message)
+		if err := c.Client.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		wObj.Status.Conditions = latest.Status.Conditions
+		wObj.Status.LastActivityTime = latest.Status.LastActivityTime
+		return nil
+	})
+}
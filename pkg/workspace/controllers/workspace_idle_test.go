@@ -0,0 +1,215 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/kaito-project/kaito/pkg/workspace/autoscaler"
+	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func mockActivityScraper(completed string) *autoscaler.Scraper {
+	s := autoscaler.NewScraper()
+	s.HTTPClient = &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("# TYPE vllm:request_success_total counter\nvllm:request_success_total " + completed + "\n")),
+		}, nil
+	})}
+	return s
+}
+
+func TestSyncIdleState(t *testing.T) {
+	inferenceReady := metav1.Condition{Type: string(v1alpha1.WorkspaceConditionTypeInferenceStatus), Status: metav1.ConditionTrue}
+	testcases := map[string]struct {
+		idleTimeout          *metav1.Duration
+		conditions           []metav1.Condition
+		lastActivityTime     *metav1.Time
+		activatorAddress     string
+		existingActivator    string
+		expectedIdle         bool
+		expectActivityUpdate bool
+		expectScaleToZero    bool
+	}{
+		"Scale-to-zero is disabled": {
+			conditions:   []metav1.Condition{inferenceReady},
+			expectedIdle: false,
+		},
+		"Initial activity time is recorded": {
+			idleTimeout:          &metav1.Duration{Duration: 10 * time.Minute},
+			conditions:           []metav1.Condition{inferenceReady},
+			expectedIdle:         false,
+			expectActivityUpdate: true,
+		},
+		"Workload that is not ready is not idle": {
+			idleTimeout:          &metav1.Duration{Duration: 10 * time.Minute},
+			lastActivityTime:     lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+			expectedIdle:         false,
+			expectActivityUpdate: true,
+		},
+		"Idle timeout has not expired": {
+			idleTimeout:      &metav1.Duration{Duration: 10 * time.Minute},
+			conditions:       []metav1.Condition{inferenceReady},
+			lastActivityTime: lo.ToPtr(metav1.NewTime(time.Now().Add(-5 * time.Minute))),
+			expectedIdle:     false,
+		},
+		"Waking workspace is not idle": {
+			idleTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			conditions: []metav1.Condition{inferenceReady,
+				{Type: string(v1alpha1.WorkspaceConditionTypeWaking), Status: metav1.ConditionTrue}},
+			lastActivityTime: lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+			expectedIdle:     false,
+		},
+		"Idle timeout has expired": {
+			idleTimeout:       &metav1.Duration{Duration: 10 * time.Minute},
+			conditions:        []metav1.Condition{inferenceReady},
+			lastActivityTime:  lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+			activatorAddress:  "10.0.0.100",
+			expectedIdle:      true,
+			expectScaleToZero: true,
+		},
+		"Idle workspace stays scaled to zero": {
+			idleTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			conditions: []metav1.Condition{
+				{Type: string(v1alpha1.WorkspaceConditionTypeIdle), Status: metav1.ConditionTrue}},
+			lastActivityTime:  lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+			activatorAddress:  "10.0.0.100",
+			existingActivator: "10.0.0.100",
+			expectedIdle:      true,
+			expectScaleToZero: true,
+		},
+		"Idle workspace follows the activator address": {
+			idleTimeout: &metav1.Duration{Duration: 10 * time.Minute},
+			conditions: []metav1.Condition{
+				{Type: string(v1alpha1.WorkspaceConditionTypeIdle), Status: metav1.ConditionTrue}},
+			lastActivityTime:  lo.ToPtr(metav1.NewTime(time.Now().Add(-time.Hour))),
+			activatorAddress:  "10.0.0.100",
+			existingActivator: "10.0.0.99",
+			expectedIdle:      true,
+			expectScaleToZero: true,
+		},
+		"Idle workspace is woken up when scale-to-zero is disabled": {
+			conditions: []metav1.Condition{
+				{Type: string(v1alpha1.WorkspaceConditionTypeIdle), Status: metav1.ConditionTrue}},
+			expectedIdle: false,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = false
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Inference.IdleTimeout = tc.idleTimeout
+			wObj.Status.Conditions = tc.conditions
+			wObj.Status.LastActivityTime = tc.lastActivityTime
+			wObj.Status.WorkerNodes = []string{"node-1"}
+			mockClient.CreateOrUpdateObjectInMap(wObj.DeepCopy())
+
+			mockClient.CreateOrUpdateObjectInMap(test.MockDeploymentUpdated.DeepCopy())
+			machine := test.MockMachine.DeepCopy()
+			mockClient.CreateMapWithType(&v1alpha5.MachineList{})[client.ObjectKeyFromObject(machine)] = machine
+			mockClient.CreateMapWithType(&corev1.PodList{})
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(test.NotFoundError())
+			var updatedDeployment *appsv1.Deployment
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Run(func(args mock.Arguments) {
+				updatedDeployment = args.Get(1).(*appsv1.Deployment)
+			}).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+			if tc.existingActivator != "" {
+				mockClient.CreateOrUpdateObjectInMap(manifests.GenerateActivatorEndpointSliceManifest(wObj, tc.existingActivator, 8090))
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Return(test.NotFoundError())
+			}
+			mockClient.On("Create", mock.IsType(context.Background()), mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Return(nil)
+			var updatedEndpointSlice *discoveryv1.EndpointSlice
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Run(func(args mock.Arguments) {
+				updatedEndpointSlice = args.Get(1).(*discoveryv1.EndpointSlice)
+			}).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client:           mockClient,
+				Scheme:           test.NewTestScheme(),
+				Scraper:          mockActivityScraper("10"),
+				ActivatorAddress: tc.activatorAddress,
+				ActivatorPort:    8090,
+			}
+
+			idle, err := reconciler.syncIdleState(context.Background(), wObj)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedIdle, idle)
+
+			if tc.expectActivityUpdate {
+				assert.Assert(t, wObj.Status.LastActivityTime != nil && time.Since(wObj.Status.LastActivityTime.Time) < time.Minute)
+			}
+			if tc.expectScaleToZero {
+				mockClient.AssertNumberOfCalls(t, "Update", lo.Ternary(tc.existingActivator != tc.activatorAddress && tc.existingActivator != "", 2, 1))
+				mockClient.AssertNumberOfCalls(t, "Delete", 1)
+				assert.Equal(t, int32(0), *updatedDeployment.Spec.Replicas)
+				assert.Equal(t, 0, len(wObj.Status.WorkerNodes))
+				assert.Equal(t, true, meta.IsStatusConditionTrue(wObj.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeIdle)))
+				assert.Equal(t, false, meta.IsStatusConditionTrue(wObj.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeInferenceStatus)))
+			} else {
+				mockClient.AssertNumberOfCalls(t, "Update", 0)
+				mockClient.AssertNumberOfCalls(t, "Delete", 0)
+			}
+			if tc.activatorAddress != "" && tc.existingActivator == "" {
+				mockClient.AssertNumberOfCalls(t, "Create", 1)
+			} else {
+				mockClient.AssertNumberOfCalls(t, "Create", 0)
+			}
+			if tc.existingActivator != "" && tc.existingActivator != tc.activatorAddress {
+				assert.DeepEqual(t, []string{tc.activatorAddress}, updatedEndpointSlice.Endpoints[0].Addresses)
+			}
+		})
+	}
+}
+
+func TestFinishWaking(t *testing.T) {
+	mockClient := test.NewClient()
+	wObj := test.MockWorkspaceWithPreset.DeepCopy()
+	wObj.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.WorkspaceConditionTypeWaking), Status: metav1.ConditionTrue}}
+	mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Return(test.NotFoundError())
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+	reconciler := &WorkspaceReconciler{
+		Client: mockClient,
+		Scheme: test.NewTestScheme(),
+	}
+	assert.NilError(t, reconciler.finishWaking(context.Background(), wObj))
+	mockClient.AssertNumberOfCalls(t, "Delete", 1)
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+
+	// Nothing to do once the workspace is awake.
+	wObj.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.WorkspaceConditionTypeWaking), Status: metav1.ConditionFalse}}
+	assert.NilError(t, reconciler.finishWaking(context.Background(), wObj))
+	mockClient.AssertNumberOfCalls(t, "Delete", 1)
+}
@@ -0,0 +1,126 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/sku"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/samber/lo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// resolveInstanceType sets the instance type used for the rest of the reconcile loop. The instance type in use is
+// recorded in the status: it is the specified one or, if that was unavailable, one of the fallback instance types.
+// If the workspace does not specify one, the instance type is selected from the GPU requirements of the preset when
+// the workspace is first reconciled, so that the same one is used afterwards.
+func (c *WorkspaceReconciler) resolveInstanceType(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	if wObj.Resource.InstanceType != "" {
+		if wObj.Status.InstanceType == wObj.Resource.InstanceType {
+			return nil
+		}
+		// Keep using the fallback instance type as long as it is still in the list.
+		if lo.Contains(wObj.Resource.FallbackInstanceTypes, wObj.Status.InstanceType) {
+			wObj.Resource.InstanceType = wObj.Status.InstanceType
+			return nil
+		}
+		return c.updateStatusInstanceType(ctx, wObj, wObj.Resource.InstanceType, metav1.Condition{
+			Type:    string(kaitov1alpha1.WorkspaceConditionTypeInstanceTypeSelected),
+			Status:  metav1.ConditionTrue,
+			Reason:  "InstanceTypeSpecified",
+			Message: fmt.Sprintf("%s is specified by the workspace", wObj.Resource.InstanceType),
+		})
+	}
+	if wObj.Status.InstanceType != "" {
+		wObj.Resource.InstanceType = wObj.Status.InstanceType
+		return nil
+	}
+	if wObj.Inference == nil || wObj.Inference.Preset == nil {
+		return fmt.Errorf("instance type must be specified for workspaces without an inference preset")
+	}
+
+	skuHandler, err := utils.GetSKUHandler()
+	if err != nil {
+		return err
+	}
+	presetName := string(wObj.Inference.Preset.Name)
+	requirement := plugin.KaitoModelRegister.MustGet(presetName).GetInferenceParameters().GetGPURequirement(lo.FromPtr(wObj.Resource.Count))
+	config, unpriced, err := sku.SelectInstanceType(skuHandler, requirement)
+	if err != nil {
+		if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeInstanceTypeSelected, metav1.ConditionFalse,
+			"InstanceTypeSelectionFailed", err.Error()); updateErr != nil {
+			klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+			return updateErr
+		}
+		return err
+	}
+
+	message := fmt.Sprintf("%s is the cheapest instance type providing the %s GPUs, %s per GPU and %s in total required by preset %s on %d node(s)",
+		config.SKU, requirement.GPUCount.String(), requirement.PerGPUMemory.String(), requirement.TotalGPUMemory.String(), presetName, requirement.NodeCount)
+	if len(unpriced) > 0 {
+		message += fmt.Sprintf(", %s also meet the requirements but are not considered because they have no price", strings.Join(unpriced, ", "))
+	}
+	klog.InfoS("Selected instance type", "workspace", klog.KObj(wObj), "instanceType", config.SKU)
+	if err := c.updateStatusInstanceType(ctx, wObj, config.SKU, metav1.Condition{
+		Type:    string(kaitov1alpha1.WorkspaceConditionTypeInstanceTypeSelected),
+		Status:  metav1.ConditionTrue,
+		Reason:  "InstanceTypeSelected",
+		Message: message,
+	}); err != nil {
+		return err
+	}
+	wObj.Resource.InstanceType = config.SKU
+	return nil
+}
+
+// fallbackInstanceType switches the workspace to the next fallback instance type after the current one turned out to
+// be unavailable. Fallback instance types which do not meet the GPU requirements of the preset on the current number
+// of nodes are skipped. consts.ErrorInstanceTypesUnavailable is returned if there are no instance types left.
+func (c *WorkspaceReconciler) fallbackInstanceType(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	current := wObj.Resource.InstanceType
+	candidates := wObj.Resource.FallbackInstanceTypes[lo.IndexOf(wObj.Resource.FallbackInstanceTypes, current)+1:]
+	if len(candidates) == 0 {
+		return fmt.Errorf(consts.ErrorInstanceTypesUnavailable)
+	}
+
+	var gpuConfigs map[string]sku.GPUConfig
+	var requirement sku.GPURequirement
+	if wObj.Inference != nil && wObj.Inference.Preset != nil {
+		skuHandler, err := utils.GetSKUHandler()
+		if err != nil {
+			return err
+		}
+		gpuConfigs = skuHandler.GetGPUConfigs()
+		requirement = plugin.KaitoModelRegister.MustGet(string(wObj.Inference.Preset.Name)).GetInferenceParameters().
+			GetGPURequirement(lo.FromPtr(wObj.Resource.Count))
+	}
+
+	for _, candidate := range candidates {
+		// Instance types which are not in the SKU list cannot be checked, they have been accepted by the validation.
+		if config, found := gpuConfigs[candidate]; found && !sku.MeetsGPURequirement(config, requirement) {
+			klog.InfoS("Skipping fallback instance type which does not meet the GPU requirements", "workspace", klog.KObj(wObj),
+				"instanceType", candidate)
+			continue
+		}
+		klog.InfoS("Falling back to the next instance type", "workspace", klog.KObj(wObj), "unavailable", current, "instanceType", candidate)
+		if err := c.updateStatusInstanceType(ctx, wObj, candidate, metav1.Condition{
+			Type:    string(kaitov1alpha1.WorkspaceConditionTypeInstanceTypeSelected),
+			Status:  metav1.ConditionTrue,
+			Reason:  "InstanceTypeFallback",
+			Message: fmt.Sprintf("%s is unavailable, falling back to %s", current, candidate),
+		}); err != nil {
+			return err
+		}
+		wObj.Resource.InstanceType = candidate
+		return nil
+	}
+	return fmt.Errorf(consts.ErrorInstanceTypesUnavailable)
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+func TestResolveInstanceType(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	testcases := map[string]struct {
+		workspace            *v1alpha1.Workspace
+		expectedInstanceType string
+		expectedError        bool
+		expectedStatusCalls  int
+	}{
+		"Instance type is specified": {
+			workspace:            test.MockWorkspaceWithPreset.DeepCopy(),
+			expectedInstanceType: test.MockWorkspaceWithPreset.Resource.InstanceType,
+			expectedStatusCalls:  1,
+		},
+		"Fallback instance type is reused": {
+			workspace: func() *v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithPreset.DeepCopy()
+				ws.Resource.FallbackInstanceTypes = []string{"Standard_NC6s_v3"}
+				ws.Status.InstanceType = "Standard_NC6s_v3"
+				return ws
+			}(),
+			expectedInstanceType: "Standard_NC6s_v3",
+		},
+		"Fallback instance type which was removed is not reused": {
+			workspace: func() *v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithPreset.DeepCopy()
+				ws.Status.InstanceType = "Standard_NC6s_v3"
+				return ws
+			}(),
+			expectedInstanceType: test.MockWorkspaceWithPreset.Resource.InstanceType,
+			expectedStatusCalls:  1,
+		},
+		"Instance type is selected for a preset": {
+			workspace: func() *v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithPreset.DeepCopy()
+				ws.Resource.InstanceType = ""
+				return ws
+			}(),
+			// The cheapest NVIDIA SKU providing one GPU.
+			expectedInstanceType: "Standard_NC4as_T4_v3",
+			expectedStatusCalls:  1,
+		},
+		"Previously selected instance type is reused": {
+			workspace: func() *v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithPreset.DeepCopy()
+				ws.Resource.InstanceType = ""
+				ws.Status.InstanceType = "Standard_NC6s_v3"
+				return ws
+			}(),
+			expectedInstanceType: "Standard_NC6s_v3",
+		},
+		"Instance type cannot be selected for a template": {
+			workspace: func() *v1alpha1.Workspace {
+				ws := test.MockWorkspaceWithInferenceTemplate.DeepCopy()
+				ws.Resource.InstanceType = ""
+				return ws
+			}(),
+			expectedError: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(tc.workspace.DeepCopy())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			err := reconciler.resolveInstanceType(context.Background(), tc.workspace)
+			if tc.expectedError {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedInstanceType, tc.workspace.Resource.InstanceType)
+			mockClient.StatusMock.AssertNumberOfCalls(t, "Update", tc.expectedStatusCalls)
+			if tc.expectedStatusCalls > 0 {
+				assert.Equal(t, tc.expectedInstanceType, tc.workspace.Status.InstanceType)
+				assert.Assert(t, meta.IsStatusConditionTrue(tc.workspace.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeInstanceTypeSelected)))
+			}
+		})
+	}
+}
+
+func TestFallbackInstanceType(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	testcases := map[string]struct {
+		currentInstanceType   string
+		fallbackInstanceTypes []string
+		count                 int
+		expectedInstanceType  string
+		expectedError         bool
+	}{
+		"Next fallback instance type is used": {
+			currentInstanceType:   "Standard_NC12s_v3",
+			fallbackInstanceTypes: []string{"Standard_NC24ads_A100_v4", "Standard_NC6s_v3"},
+			count:                 1,
+			expectedInstanceType:  "Standard_NC24ads_A100_v4",
+		},
+		"Fallback continues from the instance type in use": {
+			currentInstanceType:   "Standard_NC24ads_A100_v4",
+			fallbackInstanceTypes: []string{"Standard_NC24ads_A100_v4", "Standard_NC6s_v3"},
+			count:                 1,
+			expectedInstanceType:  "Standard_NC6s_v3",
+		},
+		"Fallback instance type which is not in the SKU list is used": {
+			currentInstanceType:   "Standard_NC12s_v3",
+			fallbackInstanceTypes: []string{"Standard_NC_custom"},
+			count:                 1,
+			expectedInstanceType:  "Standard_NC_custom",
+		},
+		"No fallback instance types left": {
+			currentInstanceType:   "Standard_NC6s_v3",
+			fallbackInstanceTypes: []string{"Standard_NC6s_v3"},
+			count:                 1,
+			expectedError:         true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Resource.InstanceType = tc.currentInstanceType
+			wObj.Resource.FallbackInstanceTypes = tc.fallbackInstanceTypes
+			wObj.Resource.Count = &tc.count
+
+			mockClient := test.NewClient()
+			mockClient.CreateOrUpdateObjectInMap(wObj.DeepCopy())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			err := reconciler.fallbackInstanceType(context.Background(), wObj)
+			if tc.expectedError {
+				assert.Error(t, err, consts.ErrorInstanceTypesUnavailable)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedInstanceType, wObj.Resource.InstanceType)
+			assert.Equal(t, tc.expectedInstanceType, wObj.Status.InstanceType)
+			condition := meta.FindStatusCondition(wObj.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeInstanceTypeSelected))
+			assert.Assert(t, condition != nil)
+			assert.Equal(t, "InstanceTypeFallback", condition.Reason)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// replaceUnhealthyNodes replaces the machines/nodeClaims whose nodes have been deleted or have not been ready for
+// consts.NodeUnhealthyTimeout after being initialized. The nodes are cordoned and the workload pods running on them
+// are evicted before the machines/nodeClaims are deleted, new ones are created in their place afterwards. The
+// machines/nodeClaims which are kept are returned.
+func (c *WorkspaceReconciler) replaceUnhealthyNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodes []provisioningNode) ([]provisioningNode, error) {
+	remaining := make([]provisioningNode, 0, len(nodes))
+	for i := range nodes {
+		initialized := lo.ContainsBy(nodes[i].conditions, func(condition apis.Condition) bool {
+			return condition.Type == apis.ConditionReady && condition.Status == corev1.ConditionTrue
+		})
+		if !initialized || nodes[i].nodeName == "" {
+			remaining = append(remaining, nodes[i])
+			continue
+		}
+
+		reason, err := c.getNodeUnhealthyReason(ctx, nodes[i].nodeName)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" {
+			remaining = append(remaining, nodes[i])
+			continue
+		}
+		if err := c.replaceNode(ctx, wObj, nodes[i], reason); err != nil {
+			return nil, err
+		}
+	}
+	return remaining, nil
+}
+
+// getNodeUnhealthyReason returns why the node needs to be replaced, or an empty string if it does not.
+func (c *WorkspaceReconciler) getNodeUnhealthyReason(ctx context.Context, nodeName string) (string, error) {
+	node := &corev1.Node{}
+	if err := c.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("node %s has been deleted", nodeName), nil
+		}
+		return "", err
+	}
+	// The node is already being terminated together with its machine/nodeClaim.
+	if !node.DeletionTimestamp.IsZero() {
+		return "", nil
+	}
+	ready, found := lo.Find(node.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == corev1.NodeReady
+	})
+	if !found || ready.Status == corev1.ConditionTrue {
+		return "", nil
+	}
+	if notReadyFor := time.Since(ready.LastTransitionTime.Time); notReadyFor > consts.NodeUnhealthyTimeout {
+		return fmt.Sprintf("node %s has not been ready for %v", nodeName, notReadyFor.Round(time.Second)), nil
+	}
+	return "", nil
+}
+
+// replaceNode cordons the node of the machine/nodeClaim, evicts the workload pods running on it and deletes the
+// machine/nodeClaim. The replacement is reported by an event and the NodeReplaced condition, which is set back to
+// False once the replacement node is ready.
+func (c *WorkspaceReconciler) replaceNode(ctx context.Context, wObj *kaitov1alpha1.Workspace, node provisioningNode, reason string) error {
+	klog.InfoS("Replacing unhealthy node", "workspace", klog.KObj(wObj), "node", node.nodeName, "reason", reason)
+
+	nodeObj := &corev1.Node{}
+	err := c.Get(ctx, client.ObjectKey{Name: node.nodeName}, nodeObj)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	nodeExists := err == nil
+	if nodeExists && !nodeObj.Spec.Unschedulable {
+		patch := client.MergeFrom(nodeObj.DeepCopy())
+		nodeObj.Spec.Unschedulable = true
+		if err := c.Patch(ctx, nodeObj, patch); client.IgnoreNotFound(err) != nil {
+			klog.ErrorS(err, "failed to cordon node", "node", node.nodeName)
+			return err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(wObj.Namespace),
+		client.MatchingLabels{kaitov1alpha1.LabelWorkspaceName: wObj.Name},
+		client.MatchingFields{"spec.nodeName": node.nodeName}); err != nil {
+		return err
+	}
+	for i := range pods.Items {
+		if err := c.evictPod(ctx, &pods.Items[i], nodeExists); err != nil {
+			klog.ErrorS(err, "failed to evict pod", "pod", klog.KObj(&pods.Items[i]))
+			return err
+		}
+	}
+
+	deletePolicy := metav1.DeletePropagationBackground
+	if err := c.Delete(ctx, node.Object, &client.DeleteOptions{
+		PropagationPolicy: &deletePolicy,
+	}); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("%s, %s is replaced", reason, node.GetName())
+	c.Recorder.Event(wObj, corev1.EventTypeWarning, "NodeReplaced", message)
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeNodeReplaced, metav1.ConditionTrue,
+		"NodeReplaced", message)
+}
+
+// evictPod evicts the pod through the Eviction API, so that the PodDisruptionBudget of the workload is respected.
+// The pods of a node which has been deleted are not running anymore, they are deleted directly.
+func (c *WorkspaceReconciler) evictPod(ctx context.Context, pod *corev1.Pod, nodeExists bool) error {
+	if !nodeExists {
+		return client.IgnoreNotFound(c.Delete(ctx, pod))
+	}
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+	}
+	err := c.SubResource("eviction").Create(ctx, pod, eviction)
+	if apierrors.IsTooManyRequests(err) {
+		return newPendingError("eviction of pod %s is blocked by its disruption budget", pod.Name)
+	}
+	return client.IgnoreNotFound(err)
+}
+
+// resetNodeReplacedCondition sets the NodeReplaced condition back to False once the replacement nodes are ready.
+func (c *WorkspaceReconciler) resetNodeReplacedCondition(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	if !meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeNodeReplaced)) {
+		return nil
+	}
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeNodeReplaced, metav1.ConditionFalse,
+		"ReplacementNodeReady", "all the nodes of the workspace are ready")
+}
+
+// watches for nodes with labels indicating workspace name or shared node pool, they are created by the machines/nodeClaims
+// of the workspace or of the pool.
+func (c *WorkspaceReconciler) watchNodes() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(
+		func(ctx context.Context, o client.Object) []reconcile.Request {
+			if pool, ok := o.GetLabels()[kaitov1alpha1.LabelSharedNodePool]; ok {
+				return c.getSharedNodePoolRequests(ctx, pool)
+			}
+			name, ok := o.GetLabels()[kaitov1alpha1.LabelWorkspaceName]
+			if !ok {
+				return nil
+			}
+			namespace, ok := o.GetLabels()[kaitov1alpha1.LabelWorkspaceNamespace]
+			if !ok {
+				return nil
+			}
+			return []reconcile.Request{
+				{
+					NamespacedName: client.ObjectKey{
+						Name:      name,
+						Namespace: namespace,
+					},
+				},
+			}
+		})
+}
+
+// nodeStatusChanged filters out the node updates which change neither the Ready condition nor the capacity, such as
+// heartbeats. The capacity changes once the device plugin advertises the GPUs of the node.
+func nodeStatusChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNode, ok := e.ObjectOld.(*corev1.Node)
+			if !ok {
+				return true
+			}
+			newNode, ok := e.ObjectNew.(*corev1.Node)
+			if !ok {
+				return true
+			}
+			return getNodeReadyStatus(oldNode) != getNodeReadyStatus(newNode) ||
+				!equality.Semantic.DeepEqual(oldNode.Status.Capacity, newNode.Status.Capacity)
+		},
+	}
+}
+
+func getNodeReadyStatus(node *corev1.Node) corev1.ConditionStatus {
+	ready, found := lo.Find(node.Status.Conditions, func(condition corev1.NodeCondition) bool {
+		return condition.Type == corev1.NodeReady
+	})
+	if !found {
+		return corev1.ConditionUnknown
+	}
+	return ready.Status
+}
@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReplaceUnhealthyNodes(t *testing.T) {
+	readyCondition := apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue}
+	newNode := func(status corev1.ConditionStatus, since time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+				Type:               corev1.NodeReady,
+				Status:             status,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+			}}},
+		}
+	}
+
+	testcases := map[string]struct {
+		node               *corev1.Node
+		conditions         apis.Conditions
+		withPod            bool
+		evictionErr        error
+		expectedReplaced   bool
+		expectedEvictions  int
+		expectedPodDeletes int
+		expectedPending    bool
+	}{
+		"Ready node is kept": {
+			node:       newNode(corev1.ConditionTrue, time.Hour),
+			conditions: apis.Conditions{readyCondition},
+		},
+		"Node which has just become NotReady is kept": {
+			node:       newNode(corev1.ConditionFalse, time.Minute),
+			conditions: apis.Conditions{readyCondition},
+		},
+		"Node which is not initialized is left to provisioning": {
+			node: newNode(corev1.ConditionFalse, consts.NodeUnhealthyTimeout*2),
+		},
+		"Node which has been NotReady for too long is replaced": {
+			node:              newNode(corev1.ConditionUnknown, consts.NodeUnhealthyTimeout*2),
+			conditions:        apis.Conditions{readyCondition},
+			withPod:           true,
+			expectedReplaced:  true,
+			expectedEvictions: 1,
+		},
+		"Node is kept while the disruption budget blocks the eviction": {
+			node:              newNode(corev1.ConditionUnknown, consts.NodeUnhealthyTimeout*2),
+			conditions:        apis.Conditions{readyCondition},
+			withPod:           true,
+			evictionErr:       apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10),
+			expectedEvictions: 1,
+			expectedPending:   true,
+		},
+		"Deleted node is replaced": {
+			conditions:         apis.Conditions{readyCondition},
+			withPod:            true,
+			expectedReplaced:   true,
+			expectedPodDeletes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			mockClient.CreateOrUpdateObjectInMap(wObj)
+			if tc.node != nil {
+				mockClient.CreateOrUpdateObjectInMap(tc.node)
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(nil)
+			} else {
+				mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything).Return(test.NotFoundError())
+			}
+			if tc.withPod {
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: wObj.Namespace}}
+				mockClient.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(pod)] = pod
+			}
+			mockClient.On("SubResourceCreate", mock.IsType(context.Background()), "eviction", mock.IsType(&corev1.Pod{}), mock.IsType(&policyv1.Eviction{}), mock.Anything).Return(tc.evictionErr)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.On("Patch", mock.IsType(context.Background()), mock.IsType(&corev1.Node{}), mock.Anything, mock.Anything).Return(nil)
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&corev1.Pod{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &WorkspaceReconciler{
+				Client:   mockClient,
+				Scheme:   test.NewTestScheme(),
+				Recorder: recorder,
+			}
+			nodes := []provisioningNode{{
+				Object:     &v1alpha5.Machine{ObjectMeta: metav1.ObjectMeta{Name: "machine-1"}},
+				conditions: tc.conditions,
+				nodeName:   "node-1",
+			}}
+			remaining, err := reconciler.replaceUnhealthyNodes(context.Background(), wObj, nodes)
+			if tc.expectedPending {
+				assert.Assert(t, isPending(err), "expected a pending error, got %v", err)
+			} else {
+				assert.NilError(t, err)
+			}
+			mockClient.AssertNumberOfCalls(t, "SubResourceCreate", tc.expectedEvictions)
+
+			if !tc.expectedReplaced {
+				mockClient.AssertNotCalled(t, "Delete", mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything)
+				assert.Equal(t, 0, len(recorder.Events))
+				if !tc.expectedPending {
+					assert.Equal(t, 1, len(remaining))
+				}
+				return
+			}
+			assert.Equal(t, 0, len(remaining))
+			mockClient.AssertCalled(t, "Delete", mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything)
+			mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedPodDeletes+1)
+			if tc.node != nil {
+				mockClient.AssertCalled(t, "Patch", mock.Anything, mock.IsType(&corev1.Node{}), mock.Anything, mock.Anything)
+			}
+			assert.Equal(t, 1, len(recorder.Events))
+			// The NodeReplaced condition is set.
+			mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+		})
+	}
+}
+
+func TestResetNodeReplacedCondition(t *testing.T) {
+	mockClient := test.NewClient()
+	wObj := test.MockWorkspaceWithPreset.DeepCopy()
+	wObj.Status.Conditions = []metav1.Condition{{Type: string(v1alpha1.WorkspaceConditionTypeNodeReplaced), Status: metav1.ConditionTrue}}
+	mockClient.CreateOrUpdateObjectInMap(wObj.DeepCopy())
+	mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+	var updated *v1alpha1.Workspace
+	mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
+		updated = args.Get(1).(*v1alpha1.Workspace)
+	}).Return(nil)
+
+	reconciler := &WorkspaceReconciler{
+		Client: mockClient,
+		Scheme: test.NewTestScheme(),
+	}
+	assert.NilError(t, reconciler.resetNodeReplacedCondition(context.Background(), wObj))
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+	assert.Assert(t, meta.IsStatusConditionFalse(updated.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeNodeReplaced)))
+
+	// Nothing to do once the condition has been reset.
+	assert.NilError(t, reconciler.resetNodeReplacedCondition(context.Background(), updated))
+	mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 1)
+}
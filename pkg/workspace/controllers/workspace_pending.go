@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// pendingRequeuePeriod is the period after which a pending workspace is reconciled again in case no watch event
+// is received for the nodes or the workload it waits for.
+const pendingRequeuePeriod = time.Minute
+
+// pendingError is returned when the nodes or the workload of a workspace are not ready yet. The reconcile loop does
+// not wait for them, the progress is recorded in the status conditions and the workspace is reconciled again when
+// the watched objects change.
+type pendingError struct {
+	message string
+}
+
+func (e *pendingError) Error() string {
+	return e.message
+}
+
+func newPendingError(format string, args ...interface{}) error {
+	return &pendingError{message: fmt.Sprintf(format, args...)}
+}
+
+func isPending(err error) bool {
+	var pErr *pendingError
+	return errors.As(err, &pErr)
+}
+
+// checkWorkloadStatus returns a pending error if the workload is not ready yet. An error is returned if the workload
+// has failed, or if it has not become ready within the timeout since it was created or since the condition turned false.
+func checkWorkloadStatus(wObj *kaitov1alpha1.Workspace, obj client.Object, cType kaitov1alpha1.ConditionType, timeout time.Duration) error {
+	ready, message, err := resources.GetResourceStatus(obj)
+	if err != nil || ready {
+		return err
+	}
+
+	since := obj.GetCreationTimestamp().Time
+	if condition := meta.FindStatusCondition(wObj.Status.Conditions, string(cType)); condition != nil &&
+		condition.Status == metav1.ConditionFalse && condition.LastTransitionTime.After(since) {
+		since = condition.LastTransitionTime.Time
+	}
+	if !since.IsZero() && time.Since(since) > timeout {
+		return fmt.Errorf("%s, not ready after %v", message, timeout)
+	}
+	return newPendingError("%s", message)
+}
+
+// requeuePendingWorkspace records the pending progress in the WorkspaceSucceeded condition and requeues the workspace
+// without returning an error, so that the reconcile worker is released and no backoff is applied.
+func (c *WorkspaceReconciler) requeuePendingWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace, err error) (reconcile.Result, error) {
+	klog.InfoS("workspace is pending", "workspace", klog.KObj(wObj), "reason", err.Error())
+	if updateErr := c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSucceeded, metav1.ConditionFalse,
+		"workspacePending", err.Error()); updateErr != nil {
+		klog.ErrorS(updateErr, "failed to update workspace status", "workspace", klog.KObj(wObj))
+		return reconcile.Result{}, updateErr
+	}
+	return reconcile.Result{RequeueAfter: pendingRequeuePeriod}, nil
+}
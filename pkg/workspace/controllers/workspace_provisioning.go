@@ -0,0 +1,169 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/machine"
+	"github.com/kaito-project/kaito/pkg/utils/nodeclaim"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/karpenter/pkg/apis/v1beta1"
+)
+
+// provisioningNode is a machine/nodeClaim created for the workspace.
+type provisioningNode struct {
+	client.Object
+	conditions   apis.Conditions
+	nodeName     string
+	instanceType string
+}
+
+// listProvisioningNodes lists the machines/nodeClaims of the workspace which are not being deleted.
+func (c *WorkspaceReconciler) listProvisioningNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) ([]provisioningNode, error) {
+	var nodes []provisioningNode
+	if featuregates.FeatureGates[consts.FeatureFlagKarpenter] {
+		ncList, err := nodeclaim.ListNodeClaim(ctx, wObj, c.Client)
+		if err != nil {
+			return nil, err
+		}
+		for i := range ncList.Items {
+			if ncList.Items[i].DeletionTimestamp.IsZero() {
+				nodes = append(nodes, provisioningNode{
+					Object:     &ncList.Items[i],
+					conditions: ncList.Items[i].GetConditions(),
+					nodeName:   ncList.Items[i].Status.NodeName,
+					instanceType: getRequiredInstanceType(lo.Map(ncList.Items[i].Spec.Requirements,
+						func(requirement v1beta1.NodeSelectorRequirementWithMinValues, _ int) corev1.NodeSelectorRequirement {
+							return requirement.NodeSelectorRequirement
+						})),
+				})
+			}
+		}
+	} else {
+		mList, err := machine.ListMachines(ctx, wObj, c.Client)
+		if err != nil {
+			return nil, err
+		}
+		for i := range mList.Items {
+			if mList.Items[i].DeletionTimestamp.IsZero() {
+				nodes = append(nodes, provisioningNode{
+					Object:       &mList.Items[i],
+					conditions:   mList.Items[i].GetConditions(),
+					nodeName:     mList.Items[i].Status.NodeName,
+					instanceType: getRequiredInstanceType(mList.Items[i].Spec.Requirements),
+				})
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// isInstanceTypeUnavailable returns whether a machine/nodeClaim of the instance type in use could not be launched
+// because the instance type is unavailable, retrying with the same instance type is pointless.
+func isInstanceTypeUnavailable(wObj *kaitov1alpha1.Workspace, nodes []provisioningNode) bool {
+	return lo.ContainsBy(nodes, func(node provisioningNode) bool {
+		launched, found := findLaunchedCondition(node.conditions)
+		return node.instanceType == wObj.Resource.InstanceType && found &&
+			launched.Status == corev1.ConditionFalse && launched.Message == consts.ErrorInstanceTypesUnavailable
+	})
+}
+
+// syncNodeProvisioning reports the provisioning status of each machine/nodeClaim in the workspace status and returns
+// the number of the ones still pending. Failed machines/nodeClaims are deleted so that new ones are created in their
+// place, the ones which are ready are kept.
+func (c *WorkspaceReconciler) syncNodeProvisioning(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodes []provisioningNode,
+	qualifiedNodes []*corev1.Node) (int, error) {
+	qualifiedNodeNames := sets.New(lo.Map(qualifiedNodes, func(node *corev1.Node, _ int) string {
+		return node.Name
+	})...)
+
+	pendingCount := 0
+	statuses := make([]kaitov1alpha1.NodeProvisioningStatus, 0, len(nodes))
+	for i := range nodes {
+		status := kaitov1alpha1.NodeProvisioningStatus{
+			Name:     nodes[i].GetName(),
+			NodeName: nodes[i].nodeName,
+		}
+		status.Phase, status.Message = getNodeProvisioningPhase(nodes[i], wObj.Resource.InstanceType, qualifiedNodeNames)
+		switch status.Phase {
+		case kaitov1alpha1.NodeProvisioningPhasePending:
+			pendingCount++
+		case kaitov1alpha1.NodeProvisioningPhaseFailed:
+			klog.InfoS("Replacing failed node", "workspace", klog.KObj(wObj), "node", status.Name, "reason", status.Message)
+			deletePolicy := metav1.DeletePropagationBackground
+			if err := c.Delete(ctx, nodes[i].Object, &client.DeleteOptions{
+				PropagationPolicy: &deletePolicy,
+			}); client.IgnoreNotFound(err) != nil {
+				return 0, err
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+
+	if err := c.updateStatusNodeProvisioningIfNotMatch(ctx, wObj, statuses); err != nil {
+		return 0, err
+	}
+	return pendingCount, nil
+}
+
+// getNodeProvisioningPhase returns the provisioning phase of a machine/nodeClaim. It has failed if it could not be
+// launched, if it has not been initialized within consts.NodeProvisioningTimeout or if the workspace has fallen back
+// to another instance type.
+func getNodeProvisioningPhase(node provisioningNode, instanceType string, qualifiedNodeNames sets.Set[string]) (kaitov1alpha1.NodeProvisioningPhase, string) {
+	if node.instanceType != "" && node.instanceType != instanceType {
+		return kaitov1alpha1.NodeProvisioningPhaseFailed, fmt.Sprintf("instance type %s is no longer used by the workspace", node.instanceType)
+	}
+	ready := lo.ContainsBy(node.conditions, func(condition apis.Condition) bool {
+		return condition.Type == apis.ConditionReady && condition.Status == corev1.ConditionTrue
+	})
+	if ready && qualifiedNodeNames.Has(node.nodeName) {
+		return kaitov1alpha1.NodeProvisioningPhaseReady, ""
+	}
+	if launched, found := findLaunchedCondition(node.conditions); found && launched.Status == corev1.ConditionFalse {
+		return kaitov1alpha1.NodeProvisioningPhaseFailed, fmt.Sprintf("node failed to launch: %s", launched.Message)
+	}
+	// Nodes which become NotReady after being initialized are replaced by replaceUnhealthyNodes.
+	if created := node.GetCreationTimestamp(); !ready && !created.IsZero() && time.Since(created.Time) > consts.NodeProvisioningTimeout {
+		return kaitov1alpha1.NodeProvisioningPhaseFailed, fmt.Sprintf("node is not ready after %v", consts.NodeProvisioningTimeout)
+	}
+	if ready {
+		return kaitov1alpha1.NodeProvisioningPhasePending, fmt.Sprintf("waiting for node %s to be ready", node.nodeName)
+	}
+	return kaitov1alpha1.NodeProvisioningPhasePending, "waiting for node to be launched"
+}
+
+func findLaunchedCondition(conditions apis.Conditions) (apis.Condition, bool) {
+	return lo.Find(conditions, func(condition apis.Condition) bool {
+		return condition.Type == v1alpha5.MachineLaunched || condition.Type == v1beta1.Launched
+	})
+}
+
+// getRequiredInstanceType returns the instance type required by a machine/nodeClaim.
+func getRequiredInstanceType(requirements []corev1.NodeSelectorRequirement) string {
+	requirement, found := lo.Find(requirements, func(requirement corev1.NodeSelectorRequirement) bool {
+		return requirement.Key == corev1.LabelInstanceTypeStable && requirement.Operator == corev1.NodeSelectorOpIn &&
+			len(requirement.Values) > 0
+	})
+	if !found {
+		return ""
+	}
+	return requirement.Values[0]
+}
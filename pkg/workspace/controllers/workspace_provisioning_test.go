@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestSyncNodeProvisioning(t *testing.T) {
+	readyNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-ready"}}
+	newMachine := func(name, nodeName string, age time.Duration, conditions ...apis.Condition) provisioningNode {
+		m := &v1alpha5.Machine{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		}}
+		return provisioningNode{Object: m, conditions: conditions, nodeName: nodeName, instanceType: "Standard_NC12s_v3"}
+	}
+	readyCondition := apis.Condition{Type: apis.ConditionReady, Status: corev1.ConditionTrue}
+
+	testcases := map[string]struct {
+		nodes           []provisioningNode
+		expectedPending int
+		expectedPhases  map[string]v1alpha1.NodeProvisioningPhase
+		expectedDeletes int
+	}{
+		"Machine with a ready node": {
+			nodes:          []provisioningNode{newMachine("ready", readyNode.Name, time.Minute, readyCondition)},
+			expectedPhases: map[string]v1alpha1.NodeProvisioningPhase{"ready": v1alpha1.NodeProvisioningPhaseReady},
+		},
+		"Machines being launched are pending": {
+			nodes: []provisioningNode{
+				newMachine("launching", "", time.Minute),
+				newMachine("registering", "node-not-ready", time.Minute, readyCondition),
+			},
+			expectedPending: 2,
+			expectedPhases: map[string]v1alpha1.NodeProvisioningPhase{
+				"launching":   v1alpha1.NodeProvisioningPhasePending,
+				"registering": v1alpha1.NodeProvisioningPhasePending,
+			},
+		},
+		"Failed machines are replaced and ready ones are kept": {
+			nodes: []provisioningNode{
+				newMachine("ready", readyNode.Name, consts.NodeProvisioningTimeout*2, readyCondition),
+				newMachine("launch-failed", "", time.Minute, apis.Condition{
+					Type:    v1alpha5.MachineLaunched,
+					Status:  corev1.ConditionFalse,
+					Message: "quota exceeded",
+				}),
+				newMachine("timed-out", "", consts.NodeProvisioningTimeout*2),
+			},
+			expectedPhases: map[string]v1alpha1.NodeProvisioningPhase{
+				"ready":         v1alpha1.NodeProvisioningPhaseReady,
+				"launch-failed": v1alpha1.NodeProvisioningPhaseFailed,
+				"timed-out":     v1alpha1.NodeProvisioningPhaseFailed,
+			},
+			expectedDeletes: 2,
+		},
+		"Machines of a previous instance type are replaced": {
+			nodes: []provisioningNode{
+				func() provisioningNode {
+					node := newMachine("previous", readyNode.Name, time.Minute, readyCondition)
+					node.instanceType = "Standard_NC6s_v3"
+					return node
+				}(),
+				newMachine("current", "", time.Minute),
+			},
+			expectedPending: 1,
+			expectedPhases: map[string]v1alpha1.NodeProvisioningPhase{
+				"previous": v1alpha1.NodeProvisioningPhaseFailed,
+				"current":  v1alpha1.NodeProvisioningPhasePending,
+			},
+			expectedDeletes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceDistributedModel.DeepCopy()
+			mockClient.CreateOrUpdateObjectInMap(wObj)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			pending, err := reconciler.syncNodeProvisioning(context.Background(), wObj, tc.nodes, []*corev1.Node{readyNode})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedPending, pending)
+			mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+
+			assert.Equal(t, len(tc.expectedPhases), len(wObj.Status.NodeProvisioning))
+			for i, status := range wObj.Status.NodeProvisioning {
+				if i > 0 {
+					assert.Assert(t, wObj.Status.NodeProvisioning[i-1].Name < status.Name, "statuses are not sorted")
+				}
+				assert.Equal(t, tc.expectedPhases[status.Name], status.Phase)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// revisionData is the content of a workspace ControllerRevision, see marshalSelectedFields.
+type revisionData struct {
+	Resource   kaitov1alpha1.ResourceSpec    `json:"resource"`
+	Scheduling *kaitov1alpha1.SchedulingSpec `json:"scheduling"`
+	Inference  *kaitov1alpha1.InferenceSpec  `json:"inference"`
+	Tuning     *kaitov1alpha1.TuningSpec     `json:"tuning"`
+}
+
+// rollbackWorkspace restores the scheduling, inference and tuning spec of the workspace from the revision requested by the
+// rollback annotation. The annotation is removed once the request has been handled, whether it succeeds or not.
+func (c *WorkspaceReconciler) rollbackWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	target, ok := wObj.Annotations[kaitov1alpha1.AnnotationWorkspaceRollbackToRevision]
+	if !ok {
+		return nil
+	}
+
+	data, err := c.getRevisionData(ctx, wObj, target)
+	delete(wObj.Annotations, kaitov1alpha1.AnnotationWorkspaceRollbackToRevision)
+	if err != nil {
+		klog.ErrorS(err, "failed to roll back workspace", "workspace", klog.KObj(wObj), "revision", target)
+		if updateErr := c.Update(ctx, wObj); updateErr != nil {
+			return fmt.Errorf("failed to remove the rollback annotation: %w", updateErr)
+		}
+		return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeRolledBack, metav1.ConditionFalse,
+			"RollbackFailed", err.Error())
+	}
+
+	klog.InfoS("Rolling back workspace", "workspace", klog.KObj(wObj), "revision", target)
+	wObj.Scheduling = data.Scheduling
+	wObj.Inference = data.Inference
+	wObj.Tuning = data.Tuning
+	if err := c.Update(ctx, wObj); err != nil {
+		return fmt.Errorf("failed to restore workspace revision %s: %w", target, err)
+	}
+	return c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeRolledBack, metav1.ConditionTrue,
+		"RollbackSucceeded", fmt.Sprintf("workspace spec has been restored from revision %s", target))
+}
+
+// getRevisionData returns the workspace spec stored in the ControllerRevision with the given revision number.
+func (c *WorkspaceReconciler) getRevisionData(ctx context.Context, wObj *kaitov1alpha1.Workspace, target string) (*revisionData, error) {
+	revisionNum, err := strconv.ParseInt(target, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid revision %q: %w", target, err)
+	}
+
+	revisions := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, revisions, client.InNamespace(wObj.Namespace), client.MatchingLabels{WorkspaceNameLabel: wObj.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	revision, found := lo.Find(revisions.Items, func(r appsv1.ControllerRevision) bool {
+		return r.Revision == revisionNum
+	})
+	if !found {
+		return nil, fmt.Errorf("revision %d is not found in the revision history", revisionNum)
+	}
+
+	data := &revisionData{}
+	if err := json.Unmarshal(revision.Data.Raw, data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision %d: %w", revisionNum, err)
+	}
+	return data, nil
+}
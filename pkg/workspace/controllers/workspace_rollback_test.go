@@ -0,0 +1,98 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRollbackWorkspace(t *testing.T) {
+	testcases := map[string]struct {
+		annotations        map[string]string
+		expectedUpdates    int
+		expectedRolledBack *metav1.ConditionStatus
+		expectedAdapters   int
+	}{
+		"No rollback is requested": {
+			expectedAdapters: 0,
+		},
+		"Roll back to an existing revision": {
+			annotations:        map[string]string{v1alpha1.AnnotationWorkspaceRollbackToRevision: "1"},
+			expectedUpdates:    1,
+			expectedRolledBack: lo.ToPtr(metav1.ConditionTrue),
+			expectedAdapters:   1,
+		},
+		"Roll back to a missing revision": {
+			annotations:        map[string]string{v1alpha1.AnnotationWorkspaceRollbackToRevision: "5"},
+			expectedUpdates:    1,
+			expectedRolledBack: lo.ToPtr(metav1.ConditionFalse),
+			expectedAdapters:   0,
+		},
+		"Roll back to an invalid revision": {
+			annotations:        map[string]string{v1alpha1.AnnotationWorkspaceRollbackToRevision: "latest"},
+			expectedUpdates:    1,
+			expectedRolledBack: lo.ToPtr(metav1.ConditionFalse),
+			expectedAdapters:   0,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Annotations = tc.annotations
+
+			// Revision 1 had an adapter which has been removed since.
+			previous := wObj.DeepCopy()
+			previous.Inference.Adapters = []v1alpha1.AdapterSpec{{Source: &v1alpha1.DataSource{Name: "adapter", Image: "adapter-image"}}}
+			data, err := marshalSelectedFields(previous)
+			assert.NilError(t, err)
+			revision := &appsv1.ControllerRevision{
+				ObjectMeta: metav1.ObjectMeta{Name: wObj.Name + "-1", Namespace: wObj.Namespace, Labels: map[string]string{WorkspaceNameLabel: wObj.Name}},
+				Revision:   1,
+				Data:       runtime.RawExtension{Raw: data},
+			}
+			mockClient.CreateMapWithType(&appsv1.ControllerRevisionList{})[client.ObjectKeyFromObject(revision)] = revision
+			mockClient.CreateOrUpdateObjectInMap(wObj.DeepCopy())
+
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&appsv1.ControllerRevisionList{}), mock.Anything).Return(nil)
+			mockClient.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			var updatedStatus *v1alpha1.Workspace
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Run(func(args mock.Arguments) {
+				updatedStatus = args.Get(1).(*v1alpha1.Workspace)
+			}).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+			assert.NilError(t, reconciler.rollbackWorkspace(context.Background(), wObj))
+
+			mockClient.AssertNumberOfCalls(t, "Update", tc.expectedUpdates)
+			assert.Equal(t, tc.expectedAdapters, len(wObj.Inference.Adapters))
+			_, found := wObj.Annotations[v1alpha1.AnnotationWorkspaceRollbackToRevision]
+			assert.Equal(t, false, found)
+			if tc.expectedRolledBack != nil {
+				condition := meta.FindStatusCondition(updatedStatus.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeRolledBack))
+				assert.Assert(t, condition != nil)
+				assert.Equal(t, *tc.expectedRolledBack, condition.Status)
+			} else {
+				mockClient.StatusMock.AssertNumberOfCalls(t, "Update", 0)
+			}
+		})
+	}
+}
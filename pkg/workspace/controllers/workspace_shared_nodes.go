@@ -0,0 +1,158 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils"
+	"github.com/kaito-project/kaito/pkg/utils/plugin"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getSharedNodesWithFreeGPUs returns the nodes of the shared node pool of the workspace which have enough free GPUs to
+// run the workload. The GPUs requested by the pods of other workspaces are in use, the ones requested by the pods of
+// the workspace itself are not.
+func (c *WorkspaceReconciler) getSharedNodesWithFreeGPUs(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodes []*corev1.Node) ([]*corev1.Node, error) {
+	required := resource.MustParse(plugin.KaitoModelRegister.MustGet(getPresetName(wObj)).GetInferenceParameters().GPUCountRequirement)
+	if wObj.Resource.FractionalGPU != nil {
+		required = resource.MustParse("1")
+	}
+	resourceName := resources.GetGPUResourceName(wObj, utils.GetGPUVendor(wObj.Resource.InstanceType))
+
+	var sharedNodes []*corev1.Node
+	for _, node := range nodes {
+		if node.Labels[kaitov1alpha1.LabelSharedNodePool] != wObj.Resource.SharedNodePool {
+			continue
+		}
+		free, err := c.getFreeGPUs(ctx, wObj, node, resourceName)
+		if err != nil {
+			return nil, err
+		}
+		if free.Cmp(required) >= 0 {
+			sharedNodes = append(sharedNodes, node)
+		}
+	}
+	return sharedNodes, nil
+}
+
+// getFreeGPUs returns the number of allocatable GPUs of the node which are not requested by the pods of other workspaces.
+func (c *WorkspaceReconciler) getFreeGPUs(ctx context.Context, wObj *kaitov1alpha1.Workspace, node *corev1.Node, resourceName corev1.ResourceName) (resource.Quantity, error) {
+	free := node.Status.Allocatable.Name(resourceName, resource.DecimalSI).DeepCopy()
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.MatchingFields{"spec.nodeName": node.Name}); err != nil {
+		return free, err
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if isWorkspacePod(wObj, pod) || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if request, found := container.Resources.Requests[resourceName]; found {
+				free.Sub(request)
+			}
+		}
+	}
+	return free, nil
+}
+
+// releaseSharedNodes deletes the machines/nodeClaims of the shared node pool of the workspace which are no longer used
+// by any workspace. A shared node is in use if it is selected or still being provisioned by a workspace of the pool,
+// or if the pods of another workspace run on it.
+func (c *WorkspaceReconciler) releaseSharedNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	wList := &kaitov1alpha1.WorkspaceList{}
+	if err := c.List(ctx, wList); err != nil {
+		return err
+	}
+	users := 0
+	usedNodes := sets.New[string]()
+	for i := range wList.Items {
+		user := &wList.Items[i]
+		if user.Namespace == wObj.Namespace && user.Name == wObj.Name {
+			user = wObj
+		}
+		if user.Resource.SharedNodePool != wObj.Resource.SharedNodePool || !user.DeletionTimestamp.IsZero() {
+			continue
+		}
+		users++
+		usedNodes.Insert(user.Status.WorkerNodes...)
+		for _, status := range user.Status.NodeProvisioning {
+			if status.Phase == kaitov1alpha1.NodeProvisioningPhasePending {
+				usedNodes.Insert(status.Name)
+			}
+		}
+	}
+
+	nodes, err := c.listProvisioningNodes(ctx, wObj)
+	if err != nil {
+		return err
+	}
+	for i := range nodes {
+		if usedNodes.Has(nodes[i].GetName()) || usedNodes.Has(nodes[i].nodeName) {
+			continue
+		}
+		if users > 0 {
+			// The node may have just been created by another workspace of the pool which has not reported it yet.
+			if nodes[i].nodeName == "" {
+				continue
+			}
+			inUse, err := c.isSharedNodeInUse(ctx, wObj, nodes[i].nodeName)
+			if err != nil {
+				return err
+			}
+			if inUse {
+				continue
+			}
+		}
+		klog.InfoS("releasing unused shared node", "node", nodes[i].GetName(), "sharedNodePool", wObj.Resource.SharedNodePool, "workspace", klog.KObj(wObj))
+		if deleteErr := c.Delete(ctx, nodes[i].Object, &client.DeleteOptions{}); client.IgnoreNotFound(deleteErr) != nil {
+			klog.ErrorS(deleteErr, "failed to delete the shared node", "node", nodes[i].GetName())
+			return deleteErr
+		}
+	}
+	return nil
+}
+
+// isSharedNodeInUse returns whether the pods of workspaces other than the given one run on the node.
+func (c *WorkspaceReconciler) isSharedNodeInUse(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeName string) (bool, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.HasLabels{kaitov1alpha1.LabelWorkspaceName},
+		client.MatchingFields{"spec.nodeName": nodeName}); err != nil {
+		return false, err
+	}
+	return lo.ContainsBy(pods.Items, func(pod corev1.Pod) bool {
+		return !isWorkspacePod(wObj, &pod) && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+	}), nil
+}
+
+func isWorkspacePod(wObj *kaitov1alpha1.Workspace, pod *corev1.Pod) bool {
+	return pod.Namespace == wObj.Namespace && pod.Labels[kaitov1alpha1.LabelWorkspaceName] == wObj.Name
+}
+
+// getSharedNodePoolRequests returns the reconcile requests of the workspaces of the shared node pool, the readiness
+// of shared nodes matters to all of them.
+func (c *WorkspaceReconciler) getSharedNodePoolRequests(ctx context.Context, pool string) []reconcile.Request {
+	wList := &kaitov1alpha1.WorkspaceList{}
+	if err := c.List(ctx, wList); err != nil {
+		klog.ErrorS(err, "failed to list workspaces", "sharedNodePool", pool)
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range wList.Items {
+		if wList.Items[i].Resource.SharedNodePool == pool {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&wList.Items[i])})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newSharedNodePoolPod(name, workspace string, gpus string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "kaito",
+			Labels:    map[string]string{v1alpha1.LabelWorkspaceName: workspace},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			Containers: []corev1.Container{{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{consts.NvidiaGPU: resource.MustParse(gpus)},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestGetSharedNodesWithFreeGPUs(t *testing.T) {
+	test.RegisterTestModel()
+	t.Setenv("CLOUD_PROVIDER", consts.AzureCloudName)
+
+	testcases := map[string]struct {
+		pool          string
+		pods          []*corev1.Pod
+		expectedNodes int
+	}{
+		"Node without other workloads fits": {
+			pool:          "small-models",
+			expectedNodes: 1,
+		},
+		"Node of another pool is not selected": {
+			pool: "large-models",
+		},
+		"Node whose GPUs are used up by other workspaces is not selected": {
+			pool: "small-models",
+			pods: []*corev1.Pod{
+				newSharedNodePoolPod("other-1", "other", "3", corev1.PodRunning),
+			},
+		},
+		"GPUs of the workspace itself and of completed pods are free": {
+			pool: "small-models",
+			pods: []*corev1.Pod{
+				newSharedNodePoolPod("self-1", "testWorkspace", "1", corev1.PodRunning),
+				newSharedNodePoolPod("other-1", "other", "2", corev1.PodRunning),
+				newSharedNodePoolPod("other-2", "other", "1", corev1.PodSucceeded),
+			},
+			expectedNodes: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			for _, pod := range tc.pods {
+				mockClient.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(pod)] = pod
+			}
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Resource.SharedNodePool = "small-models"
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-1",
+					Labels: map[string]string{v1alpha1.LabelSharedNodePool: tc.pool},
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{consts.NvidiaGPU: resource.MustParse("3")},
+				},
+			}
+
+			reconciler := &WorkspaceReconciler{Client: mockClient, Scheme: test.NewTestScheme()}
+			nodes, err := reconciler.getSharedNodesWithFreeGPUs(context.Background(), wObj, []*corev1.Node{node})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedNodes, len(nodes))
+		})
+	}
+}
+
+func TestReleaseSharedNodes(t *testing.T) {
+	testcases := map[string]struct {
+		otherWorkspace *v1alpha1.Workspace
+		nodeName       string
+		pods           []*corev1.Pod
+		expectedDelete bool
+	}{
+		"Node is released once no workspace uses the pool": {
+			nodeName:       "node-1",
+			expectedDelete: true,
+		},
+		"Node still launching is released once no workspace uses the pool": {
+			expectedDelete: true,
+		},
+		"Node selected by another workspace is kept": {
+			otherWorkspace: &v1alpha1.Workspace{Status: v1alpha1.WorkspaceStatus{WorkerNodes: []string{"node-1"}}},
+			nodeName:       "node-1",
+		},
+		"Node provisioned for another workspace is kept": {
+			otherWorkspace: &v1alpha1.Workspace{Status: v1alpha1.WorkspaceStatus{NodeProvisioning: []v1alpha1.NodeProvisioningStatus{
+				{Name: "machine-1", Phase: v1alpha1.NodeProvisioningPhasePending},
+			}}},
+			nodeName: "node-1",
+		},
+		"Node running the pods of another workspace is kept": {
+			otherWorkspace: &v1alpha1.Workspace{},
+			nodeName:       "node-1",
+			pods:           []*corev1.Pod{newSharedNodePoolPod("other-1", "other", "1", corev1.PodRunning)},
+		},
+		"Node only running the pods of the workspace is released": {
+			otherWorkspace: &v1alpha1.Workspace{},
+			nodeName:       "node-1",
+			pods:           []*corev1.Pod{newSharedNodePoolPod("self-1", "testWorkspace", "1", corev1.PodRunning)},
+			expectedDelete: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			wObj.Resource.SharedNodePool = "small-models"
+			wObj.DeletionTimestamp = lo.ToPtr(metav1.Now())
+			workspaces := mockClient.CreateMapWithType(&v1alpha1.WorkspaceList{})
+			workspaces[client.ObjectKeyFromObject(wObj)] = wObj
+			if tc.otherWorkspace != nil {
+				other := tc.otherWorkspace.DeepCopy()
+				other.Name, other.Namespace = "other", "kaito"
+				other.Resource.SharedNodePool = "small-models"
+				workspaces[client.ObjectKeyFromObject(other)] = other
+			}
+			for _, pod := range tc.pods {
+				mockClient.CreateMapWithType(&corev1.PodList{})[client.ObjectKeyFromObject(pod)] = pod
+			}
+			machine := &v1alpha5.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "machine-1",
+					Labels: map[string]string{v1alpha1.LabelSharedNodePool: "small-models"},
+				},
+				Status: v1alpha5.MachineStatus{NodeName: tc.nodeName},
+			}
+			mockClient.CreateMapWithType(&v1alpha5.MachineList{})[client.ObjectKeyFromObject(machine)] = machine
+
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha1.WorkspaceList{}), mock.Anything).Return(nil)
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&corev1.PodList{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{Client: mockClient, Scheme: test.NewTestScheme()}
+			assert.NilError(t, reconciler.deleteWorkspaceNodes(context.Background(), wObj))
+
+			if tc.expectedDelete {
+				mockClient.AssertCalled(t, "Delete", mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything)
+			} else {
+				mockClient.AssertNotCalled(t, "Delete", mock.Anything, mock.IsType(&v1alpha5.Machine{}), mock.Anything)
+			}
+		})
+	}
+}
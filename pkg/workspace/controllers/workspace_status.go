@@ -7,12 +7,12 @@ import (
 	"context"
 	"reflect"
 	"sort"
+	"strconv"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
@@ -20,28 +20,23 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (c *WorkspaceReconciler) updateWorkspaceStatus(ctx context.Context, name *client.ObjectKey, condition *metav1.Condition, workerNodes []string) error {
-	return retry.OnError(retry.DefaultRetry,
-		func(err error) bool {
-			return apierrors.IsServiceUnavailable(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err)
-		},
-		func() error {
-			// Read the latest version to avoid update conflict.
-			wObj := &kaitov1alpha1.Workspace{}
-			if err := c.Client.Get(ctx, *name, wObj); err != nil {
-				if !errors.IsNotFound(err) {
-					return err
-				}
-				return nil
-			}
-			if condition != nil {
-				meta.SetStatusCondition(&wObj.Status.Conditions, *condition)
-			}
-			if workerNodes != nil {
-				wObj.Status.WorkerNodes = workerNodes
-			}
-			return c.Client.Status().Update(ctx, wObj)
-		})
+// updateStatus applies the mutation to the status of the latest version of the workspace and updates it, retrying on
+// conflicts. Nothing is updated if the workspace has been deleted. Once the status is updated, the mutation is also
+// applied to wObj so that the rest of the reconcile loop sees the new status.
+func (c *WorkspaceReconciler) updateStatus(ctx context.Context, wObj *kaitov1alpha1.Workspace, mutate func(*kaitov1alpha1.WorkspaceStatus)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// Read the latest version to avoid update conflict.
+		latest := &kaitov1alpha1.Workspace{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(wObj), latest); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		mutate(&latest.Status)
+		if err := c.Client.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		mutate(&wObj.Status)
+		return nil
+	})
 }
 
 func (c *WorkspaceReconciler) updateStatusConditionIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, cType kaitov1alpha1.ConditionType,
@@ -53,14 +48,22 @@ func (c *WorkspaceReconciler) updateStatusConditionIfNotMatch(ctx context.Contex
 		}
 	}
 	klog.InfoS("updateStatusCondition", "workspace", klog.KObj(wObj), "conditionType", cType, "status", cStatus, "reason", cReason, "message", cMessage)
-	cObj := metav1.Condition{
-		Type:               string(cType),
-		Status:             cStatus,
-		Reason:             cReason,
-		ObservedGeneration: wObj.GetGeneration(),
-		Message:            cMessage,
-	}
-	return c.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, &cObj, nil)
+	return c.updateStatusConditions(ctx, wObj, metav1.Condition{
+		Type:    string(cType),
+		Status:  cStatus,
+		Reason:  cReason,
+		Message: cMessage,
+	})
+}
+
+// updateStatusConditions sets multiple status conditions in a single update.
+func (c *WorkspaceReconciler) updateStatusConditions(ctx context.Context, wObj *kaitov1alpha1.Workspace, conditions ...metav1.Condition) error {
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		for _, condition := range conditions {
+			condition.ObservedGeneration = wObj.GetGeneration()
+			meta.SetStatusCondition(&status.Conditions, condition)
+		}
+	})
 }
 
 func (c *WorkspaceReconciler) updateStatusNodeListIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, validNodeList []*corev1.Node) error {
@@ -72,6 +75,74 @@ func (c *WorkspaceReconciler) updateStatusNodeListIfNotMatch(ctx context.Context
 	if reflect.DeepEqual(wObj.Status.WorkerNodes, nodeNameList) {
 		return nil
 	}
-	klog.InfoS("updateStatusNodeList", "workspace", klog.KObj(wObj))
-	return c.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: wObj.Name, Namespace: wObj.Namespace}, nil, nodeNameList)
+	return c.updateStatusWorkerNodes(ctx, wObj, nodeNameList)
+}
+
+// updateStatusWorkerNodes replaces the selected nodes of the workspace.
+func (c *WorkspaceReconciler) updateStatusWorkerNodes(ctx context.Context, wObj *kaitov1alpha1.Workspace, nodeNames []string) error {
+	klog.InfoS("updateStatusNodeList", "workspace", klog.KObj(wObj), "workerNodes", nodeNames)
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.WorkerNodes = nodeNames
+	})
+}
+
+func (c *WorkspaceReconciler) updateStatusAutoscalingIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, autoscalingStatus *kaitov1alpha1.AutoscalingStatus) error {
+	if reflect.DeepEqual(wObj.Status.Autoscaling, autoscalingStatus) {
+		return nil
+	}
+	klog.InfoS("updateStatusAutoscaling", "workspace", klog.KObj(wObj), "desiredReplicas", autoscalingStatus.DesiredReplicas)
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.Autoscaling = autoscalingStatus
+	})
+}
+
+func (c *WorkspaceReconciler) updateStatusLastActivityTime(ctx context.Context, wObj *kaitov1alpha1.Workspace, lastActivityTime metav1.Time) error {
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.LastActivityTime = &lastActivityTime
+	})
+}
+
+// updateStatusCurrentRevisionIfNotMatch records the revision that the workload has been reconciled to.
+func (c *WorkspaceReconciler) updateStatusCurrentRevisionIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	revision, err := strconv.ParseInt(wObj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation], 10, 64)
+	if err != nil || wObj.Status.CurrentRevision == revision {
+		return nil
+	}
+	klog.InfoS("updateStatusCurrentRevision", "workspace", klog.KObj(wObj), "revision", revision)
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.CurrentRevision = revision
+	})
+}
+
+// updateStatusExternalURLIfNotMatch records the external URL of the exposed inference service.
+func (c *WorkspaceReconciler) updateStatusExternalURLIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace, externalURL string) error {
+	if wObj.Status.ExternalURL == externalURL {
+		return nil
+	}
+	klog.InfoS("updateStatusExternalURL", "workspace", klog.KObj(wObj), "externalURL", externalURL)
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.ExternalURL = externalURL
+	})
+}
+
+// updateStatusNodeProvisioningIfNotMatch records the provisioning status of the machines/nodeClaims of the workspace.
+func (c *WorkspaceReconciler) updateStatusNodeProvisioningIfNotMatch(ctx context.Context, wObj *kaitov1alpha1.Workspace,
+	nodeProvisioning []kaitov1alpha1.NodeProvisioningStatus) error {
+	if equality.Semantic.DeepEqual(wObj.Status.NodeProvisioning, nodeProvisioning) {
+		return nil
+	}
+	klog.InfoS("updateStatusNodeProvisioning", "workspace", klog.KObj(wObj))
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.NodeProvisioning = nodeProvisioning
+	})
+}
+
+// updateStatusInstanceType records the instance type selected by the controller together with the reason.
+func (c *WorkspaceReconciler) updateStatusInstanceType(ctx context.Context, wObj *kaitov1alpha1.Workspace, instanceType string,
+	condition metav1.Condition) error {
+	condition.ObservedGeneration = wObj.GetGeneration()
+	return c.updateStatus(ctx, wObj, func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.InstanceType = instanceType
+		meta.SetStatusCondition(&status.Conditions, condition)
+	})
 }
@@ -15,10 +15,14 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func TestUpdateWorkspaceStatus(t *testing.T) {
+func TestUpdateStatus(t *testing.T) {
+	workerNodes := []string{"node1", "node2"}
+	setWorkerNodes := func(status *kaitov1alpha1.WorkspaceStatus) {
+		status.WorkerNodes = workerNodes
+	}
+
 	t.Run("Should update workspace status successfully", func(t *testing.T) {
 		mockClient := test.NewClient()
 		reconciler := &WorkspaceReconciler{
@@ -26,20 +30,34 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			Scheme: test.NewTestScheme(),
 		}
 		ctx := context.Background()
-		workspace := test.MockWorkspaceDistributedModel
-		condition := metav1.Condition{
-			Type:    "TestCondition",
-			Status:  metav1.ConditionStatus("True"),
-			Reason:  "TestReason",
-			Message: "TestMessage",
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
+
+		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+		err := reconciler.updateStatus(ctx, workspace, setWorkerNodes)
+		assert.Nil(t, err)
+		assert.Equal(t, workerNodes, workspace.Status.WorkerNodes)
+	})
+
+	t.Run("Should retry on conflict", func(t *testing.T) {
+		mockClient := test.NewClient()
+		reconciler := &WorkspaceReconciler{
+			Client: mockClient,
+			Scheme: test.NewTestScheme(),
 		}
-		workerNodes := []string{"node1", "node2"}
+		ctx := context.Background()
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
 
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
+		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).
+			Return(apierrors.NewConflict(schema.GroupResource{}, "workspace", errors.New("conflict"))).Once()
 		mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(nil)
 
-		err := reconciler.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: workspace.Name, Namespace: workspace.Namespace}, &condition, workerNodes)
+		err := reconciler.updateStatus(ctx, workspace, setWorkerNodes)
 		assert.Nil(t, err)
+		mockClient.AssertNumberOfCalls(t, "Get", 2)
+		assert.Equal(t, workerNodes, workspace.Status.WorkerNodes)
 	})
 
 	t.Run("Should return error when Get operation fails", func(t *testing.T) {
@@ -49,19 +67,13 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			Scheme: test.NewTestScheme(),
 		}
 		ctx := context.Background()
-		workspace := test.MockWorkspaceDistributedModel
-		condition := metav1.Condition{
-			Type:    "TestCondition",
-			Status:  metav1.ConditionStatus("True"),
-			Reason:  "TestReason",
-			Message: "TestMessage",
-		}
-		workerNodes := []string{"node1", "node2"}
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
 
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(errors.New("Get operation failed"))
 
-		err := reconciler.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: workspace.Name, Namespace: workspace.Namespace}, &condition, workerNodes)
+		err := reconciler.updateStatus(ctx, workspace, setWorkerNodes)
 		assert.NotNil(t, err)
+		assert.Nil(t, workspace.Status.WorkerNodes)
 	})
 
 	t.Run("Should return nil when workspace is not found", func(t *testing.T) {
@@ -71,18 +83,11 @@ func TestUpdateWorkspaceStatus(t *testing.T) {
 			Scheme: test.NewTestScheme(),
 		}
 		ctx := context.Background()
-		workspace := test.MockWorkspaceDistributedModel
-		condition := metav1.Condition{
-			Type:    "TestCondition",
-			Status:  metav1.ConditionStatus("True"),
-			Reason:  "TestReason",
-			Message: "TestMessage",
-		}
-		workerNodes := []string{"node1", "node2"}
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
 
 		mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&kaitov1alpha1.Workspace{}), mock.Anything).Return(apierrors.NewNotFound(schema.GroupResource{}, "workspace"))
 
-		err := reconciler.updateWorkspaceStatus(ctx, &client.ObjectKey{Name: workspace.Name, Namespace: workspace.Namespace}, &condition, workerNodes)
+		err := reconciler.updateStatus(ctx, workspace, setWorkerNodes)
 		assert.Nil(t, err)
 	})
 }
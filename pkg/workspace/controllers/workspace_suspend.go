@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// syncSuspendState tears down the workload of a suspended workspace and resumes it once the suspension is lifted.
+// It returns true if the workspace is suspended, in which case the rest of the reconcile loop is skipped.
+func (c *WorkspaceReconciler) syncSuspendState(ctx context.Context, wObj *kaitov1alpha1.Workspace) (bool, error) {
+	suspended := meta.IsStatusConditionTrue(wObj.Status.Conditions, string(kaitov1alpha1.WorkspaceConditionTypeSuspended))
+	if !kaitov1alpha1.IsWorkspaceSuspended(wObj) {
+		if suspended {
+			klog.InfoS("Resuming workspace", "workspace", klog.KObj(wObj))
+			return false, c.updateStatusConditionIfNotMatch(ctx, wObj, kaitov1alpha1.WorkspaceConditionTypeSuspended, metav1.ConditionFalse,
+				"WorkspaceResumed", "workspace has been resumed")
+		}
+		return false, nil
+	}
+
+	if err := c.suspendWorkspace(ctx, wObj); err != nil {
+		return true, err
+	}
+	if suspended {
+		return true, nil
+	}
+
+	klog.InfoS("Workspace has been suspended", "workspace", klog.KObj(wObj))
+	conditions := []metav1.Condition{
+		{Type: string(kaitov1alpha1.WorkspaceConditionTypeSuspended), Status: metav1.ConditionTrue,
+			Reason: "WorkspaceSuspended", Message: "workload has been torn down and nodes have been released"},
+		{Type: string(kaitov1alpha1.ConditionTypeResourceStatus), Status: metav1.ConditionFalse,
+			Reason: "WorkspaceSuspended", Message: "Nodes have been released"},
+		{Type: string(kaitov1alpha1.WorkspaceConditionTypeSucceeded), Status: metav1.ConditionFalse,
+			Reason: "WorkspaceSuspended", Message: "workspace has been suspended"},
+	}
+	if wObj.Inference != nil {
+		conditions = append(conditions, metav1.Condition{Type: string(kaitov1alpha1.WorkspaceConditionTypeInferenceStatus), Status: metav1.ConditionFalse,
+			Reason: "WorkspaceSuspended", Message: "Inference workload has been deleted"})
+	}
+	if wObj.Tuning != nil {
+		conditions = append(conditions, metav1.Condition{Type: string(kaitov1alpha1.WorkspaceConditionTypeTuningJobStatus), Status: metav1.ConditionFalse,
+			Reason: "WorkspaceSuspended", Message: "Tuning job has been deleted"})
+	}
+	// A suspended workspace is not woken up by requests.
+	for _, cType := range []kaitov1alpha1.ConditionType{kaitov1alpha1.WorkspaceConditionTypeIdle, kaitov1alpha1.WorkspaceConditionTypeWaking} {
+		if meta.FindStatusCondition(wObj.Status.Conditions, string(cType)) != nil {
+			conditions = append(conditions, metav1.Condition{Type: string(cType), Status: metav1.ConditionFalse,
+				Reason: "WorkspaceSuspended", Message: "workspace has been suspended"})
+		}
+	}
+	return true, c.updateStatusConditions(ctx, wObj, conditions...)
+}
+
+// suspendWorkspace deletes the workload and the nodes of the workspace. The workspace, its Services and
+// its ControllerRevisions are kept so that the same revision is deployed when the workspace is resumed.
+func (c *WorkspaceReconciler) suspendWorkspace(ctx context.Context, wObj *kaitov1alpha1.Workspace) error {
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &appsv1.StatefulSet{}, &batchv1.Job{}, &discoveryv1.EndpointSlice{}} {
+		name := wObj.Name
+		if _, ok := obj.(*discoveryv1.EndpointSlice); ok {
+			name = fmt.Sprintf("%s-activator", wObj.Name)
+		}
+		if err := c.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: wObj.Namespace}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !obj.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		klog.InfoS("Deleting workload of suspended workspace", "workspace", klog.KObj(wObj), "object", klog.KObj(obj))
+		if err := c.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	if err := c.deleteWorkspaceNodes(ctx, wObj); err != nil {
+		return err
+	}
+	if len(wObj.Status.WorkerNodes) > 0 {
+		if err := c.updateStatusWorkerNodes(ctx, wObj, []string{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
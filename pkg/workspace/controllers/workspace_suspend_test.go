@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT license.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/karpenter-core/pkg/apis/v1alpha5"
+	"github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/featuregates"
+	"github.com/kaito-project/kaito/pkg/utils/consts"
+	"github.com/kaito-project/kaito/pkg/utils/test"
+	"github.com/stretchr/testify/mock"
+	"gotest.tools/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSyncSuspendState(t *testing.T) {
+	suspendedCondition := metav1.Condition{Type: string(v1alpha1.WorkspaceConditionTypeSuspended), Status: metav1.ConditionTrue}
+	testcases := map[string]struct {
+		suspend             bool
+		conditions          []metav1.Condition
+		expectedSuspended   bool
+		expectedDeletes     int
+		expectedStatusCalls int
+	}{
+		"Workspace is not suspended": {
+			expectedSuspended: false,
+		},
+		"Workspace is suspended": {
+			suspend:           true,
+			expectedSuspended: true,
+			// The Deployment and the Machine are deleted.
+			expectedDeletes: 2,
+			// The worker nodes are cleared and the conditions are set.
+			expectedStatusCalls: 2,
+		},
+		"Suspended workspace stays suspended": {
+			suspend:           true,
+			conditions:        []metav1.Condition{suspendedCondition},
+			expectedSuspended: true,
+			expectedDeletes:   2,
+			// Only the worker nodes are cleared.
+			expectedStatusCalls: 1,
+		},
+		"Workspace is resumed": {
+			conditions:          []metav1.Condition{suspendedCondition},
+			expectedSuspended:   false,
+			expectedStatusCalls: 1,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			featuregates.FeatureGates[consts.FeatureFlagKarpenter] = false
+			mockClient := test.NewClient()
+			wObj := test.MockWorkspaceWithPreset.DeepCopy()
+			if tc.suspend {
+				wObj.Annotations = map[string]string{v1alpha1.AnnotationWorkspaceSuspend: "true"}
+			}
+			wObj.Status.Conditions = tc.conditions
+			wObj.Status.WorkerNodes = []string{"node-1"}
+			mockClient.CreateOrUpdateObjectInMap(wObj.DeepCopy())
+
+			mockClient.CreateOrUpdateObjectInMap(test.MockDeploymentUpdated.DeepCopy())
+			machine := test.MockMachine.DeepCopy()
+			mockClient.CreateMapWithType(&v1alpha5.MachineList{})[client.ObjectKeyFromObject(machine)] = machine
+			mockClient.On("List", mock.IsType(context.Background()), mock.IsType(&v1alpha5.MachineList{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&appsv1.StatefulSet{}), mock.Anything).Return(test.NotFoundError())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&batchv1.Job{}), mock.Anything).Return(test.NotFoundError())
+			mockClient.On("Get", mock.IsType(context.Background()), mock.Anything, mock.IsType(&discoveryv1.EndpointSlice{}), mock.Anything).Return(test.NotFoundError())
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			mockClient.On("Delete", mock.IsType(context.Background()), mock.IsType(&v1alpha5.Machine{}), mock.Anything).Return(nil)
+			mockClient.StatusMock.On("Update", mock.IsType(context.Background()), mock.IsType(&v1alpha1.Workspace{}), mock.Anything).Return(nil)
+
+			reconciler := &WorkspaceReconciler{
+				Client: mockClient,
+				Scheme: test.NewTestScheme(),
+			}
+
+			suspended, err := reconciler.syncSuspendState(context.Background(), wObj)
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedSuspended, suspended)
+			mockClient.AssertNumberOfCalls(t, "Delete", tc.expectedDeletes)
+			mockClient.StatusMock.AssertNumberOfCalls(t, "Update", tc.expectedStatusCalls)
+			if tc.expectedSuspended {
+				assert.Equal(t, true, meta.IsStatusConditionTrue(wObj.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeSuspended)))
+				assert.Equal(t, 0, len(wObj.Status.WorkerNodes))
+				assert.Equal(t, false, meta.IsStatusConditionTrue(wObj.Status.Conditions, string(v1alpha1.WorkspaceConditionTypeInferenceStatus)))
+			}
+		})
+	}
+}
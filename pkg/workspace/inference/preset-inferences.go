@@ -4,7 +4,11 @@ package inference
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 
@@ -14,8 +18,10 @@ import (
 	"github.com/kaito-project/kaito/api/v1alpha1"
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
 	"github.com/kaito-project/kaito/pkg/model"
+	"github.com/kaito-project/kaito/pkg/sku"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -34,6 +40,8 @@ var (
 	},
 	}
 
+	// The probe fields defaulted by the API server are set explicitly, so that the generated manifest can be
+	// compared with the existing workload.
 	livenessProbe = &corev1.Probe{
 		ProbeHandler: corev1.ProbeHandler{
 			HTTPGet: &corev1.HTTPGetAction{
@@ -43,6 +51,9 @@ var (
 		},
 		InitialDelaySeconds: 600, // 10 minutes
 		PeriodSeconds:       10,
+		TimeoutSeconds:      1,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
 	}
 
 	readinessProbe = &corev1.Probe{
@@ -54,13 +65,19 @@ var (
 		},
 		InitialDelaySeconds: 30,
 		PeriodSeconds:       10,
+		TimeoutSeconds:      1,
+		SuccessThreshold:    1,
+		FailureThreshold:    3,
 	}
+)
 
-	tolerations = []corev1.Toleration{
+// getTolerations returns the tolerations of the taints on the GPU nodes of the vendor.
+func getTolerations(vendor sku.GPUVendor) []corev1.Toleration {
+	return []corev1.Toleration{
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
 			Operator: corev1.TolerationOpExists,
-			Key:      resources.CapacityNvidiaGPU,
+			Key:      vendor.ResourceName,
 		},
 		{
 			Effect:   corev1.TaintEffectNoSchedule,
@@ -69,7 +86,7 @@ var (
 			Operator: corev1.TolerationOpEqual,
 		},
 	}
-)
+}
 
 func updateTorchParamsForDistributedInference(ctx context.Context, kubeClient client.Client, wObj *kaitov1alpha1.Workspace, inferenceParam *model.PresetParam) error {
 	runtimeName := v1alpha1.GetWorkspaceRuntimeName(wObj)
@@ -119,7 +136,7 @@ func GetInferenceImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 		return imageName, imagePullSecretRefs
 	} else {
 		imageName := string(workspaceObj.Inference.Preset.Name)
-		imageTag := presetObj.Tag
+		imageTag := presetObj.GetImageTag(utils.GetGPUVendor(workspaceObj.Resource.InstanceType))
 		registryName := os.Getenv("PRESET_REGISTRY_NAME")
 		imageName = fmt.Sprintf("%s/kaito-%s:%s", registryName, imageName, imageTag)
 
@@ -128,6 +145,21 @@ func GetInferenceImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 }
 
 func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
+	model model.Model, kubeClient client.Client) (client.Object, error) {
+	depObj, err := GeneratePresetInference(ctx, workspaceObj, revisionNum, model, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	err = resources.CreateResource(ctx, depObj, kubeClient)
+	if client.IgnoreAlreadyExists(err) != nil {
+		return nil, err
+	}
+	return depObj, nil
+}
+
+// GeneratePresetInference renders the Deployment or StatefulSet that runs the preset inference service
+// of the workspace without creating it.
+func GeneratePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
 	model model.Model, kubeClient client.Client) (client.Object, error) {
 	inferenceParam := model.GetInferenceParameters().DeepCopy()
 
@@ -157,12 +189,22 @@ func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 	if err != nil {
 		return nil, fmt.Errorf("failed to get SKU num GPUs: %v", err)
 	}
+	if workspaceObj.Resource.SharedNodePool != "" {
+		// Shared nodes are packed with the workloads of several workspaces, only the GPUs required by the model are used.
+		skuNumGPUs = inferenceParam.GPUCountRequirement
+	}
+	if workspaceObj.Resource.FractionalGPU != nil {
+		// The model fits in a single MIG slice or time-sliced replica of a GPU.
+		skuNumGPUs = "1"
+	}
+	vendor := utils.GetGPUVendor(workspaceObj.Resource.InstanceType)
+	gpuResourceName := resources.GetGPUResourceName(workspaceObj, vendor)
 	resourceReq := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResourceName: resource.MustParse(skuNumGPUs),
 		},
 		Limits: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResourceName: resource.MustParse(skuNumGPUs),
 		},
 	}
 	skuGPUCount, _ := strconv.Atoi(skuNumGPUs)
@@ -192,21 +234,57 @@ func CreatePresetInference(ctx context.Context, workspaceObj *kaitov1alpha1.Work
 
 	// inference command
 	runtimeName := kaitov1alpha1.GetWorkspaceRuntimeName(workspaceObj)
+	setTimeSlicedGPUMemoryUtilization(workspaceObj, runtimeName, inferenceParam)
 	commands := inferenceParam.GetInferenceCommand(runtimeName, skuNumGPUs, &cmVolumeMount)
 
 	image, imagePullSecrets := GetInferenceImageInfo(ctx, workspaceObj, inferenceParam)
 
 	var depObj client.Object
 	if model.SupportDistributedInference() {
-		depObj = manifests.GenerateStatefulSetManifest(ctx, workspaceObj, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
-			containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
+		depObj = manifests.GenerateStatefulSetManifest(ctx, workspaceObj, revisionNum, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
+			containerPorts, livenessProbe, readinessProbe, resourceReq, getTolerations(vendor), volumes, volumeMounts)
 	} else {
 		depObj = manifests.GenerateDeploymentManifest(ctx, workspaceObj, revisionNum, image, imagePullSecrets, *workspaceObj.Resource.Count, commands,
-			containerPorts, livenessProbe, readinessProbe, resourceReq, tolerations, volumes, volumeMounts)
+			containerPorts, livenessProbe, readinessProbe, resourceReq, getTolerations(vendor), volumes, volumeMounts)
 	}
-	err = resources.CreateResource(ctx, depObj, kubeClient)
-	if client.IgnoreAlreadyExists(err) != nil {
+	if err := setTemplateHash(depObj); err != nil {
 		return nil, err
 	}
 	return depObj, nil
 }
+
+// setTemplateHash records the hash of the rendered pod template and update strategy in the workload annotations.
+// The live workload is compared with the rendered one ignoring the fields defaulted by the API server, which also
+// ignores the fields removed from the rendered manifest, e.g., by a new controller version. The hash detects them.
+func setTemplateHash(obj client.Object) error {
+	var spec []any
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		spec = []any{workload.Spec.Strategy, workload.Spec.Template}
+	case *appsv1.StatefulSet:
+		spec = []any{workload.Spec.UpdateStrategy, workload.Spec.Template}
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the pod template: %w", err)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	digest := sha256.Sum256(data)
+	annotations[kaitov1alpha1.WorkspaceTemplateHashAnnotation] = hex.EncodeToString(digest[:])
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// setTimeSlicedGPUMemoryUtilization bounds the GPU memory pre-allocated by vLLM when the GPU is time-sliced, so that
+// the replicas sharing it split its memory. 10% of the share of each replica is left for its CUDA context.
+func setTimeSlicedGPUMemoryUtilization(wObj *kaitov1alpha1.Workspace, runtimeName model.RuntimeName, inferenceParam *model.PresetParam) {
+	fractionalGPU := wObj.Resource.FractionalGPU
+	if runtimeName != model.RuntimeNameVLLM || fractionalGPU == nil || fractionalGPU.Strategy != kaitov1alpha1.FractionalGPUStrategyTimeSlicing {
+		return
+	}
+	utilization := math.Floor(90/float64(fractionalGPU.Replicas)) / 100
+	inferenceParam.VLLM.ModelRunParams["kaito-max-gpu-memory-utilization"] = strconv.FormatFloat(utilization, 'f', 2, 64)
+}
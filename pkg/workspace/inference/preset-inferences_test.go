@@ -24,6 +24,12 @@ var ValidStrength string = "0.5"
 
 func TestCreatePresetInference(t *testing.T) {
 	test.RegisterTestModel()
+	sharedWorkspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	sharedWorkspace.Resource.SharedNodePool = "small-models"
+	migWorkspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	migWorkspace.Resource.FractionalGPU = &v1alpha1.FractionalGPUSpec{Strategy: v1alpha1.FractionalGPUStrategyMIG, MIGProfile: "1g.10gb"}
+	timeSlicedWorkspace := test.MockWorkspaceWithPresetVLLM.DeepCopy()
+	timeSlicedWorkspace.Resource.FractionalGPU = &v1alpha1.FractionalGPUSpec{Strategy: v1alpha1.FractionalGPUStrategyTimeSlicing, Replicas: 4}
 	testcases := map[string]struct {
 		workspace      *v1alpha1.Workspace
 		nodeCount      int
@@ -33,6 +39,7 @@ func TestCreatePresetInference(t *testing.T) {
 		expectedCmd    string
 		hasAdapters    bool
 		expectedVolume string
+		expectedGPU    corev1.ResourceName
 	}{
 
 		"test-model/vllm": {
@@ -50,6 +57,49 @@ func TestCreatePresetInference(t *testing.T) {
 			hasAdapters: false,
 		},
 
+		"test-model-shared-node-pool/vllm": {
+			workspace: sharedWorkspace,
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.TODO()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			},
+			workload: "Deployment",
+			// Only the GPUs required by the model are used on shared nodes.
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --tensor-parallel-size=1 --served-model-name=mymodel --kaito-config-file=/mnt/config/inference_config.yaml",
+			hasAdapters: false,
+		},
+
+		"test-model-mig/vllm": {
+			workspace: migWorkspace,
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.TODO()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			},
+			workload: "Deployment",
+			// The workload runs on a single MIG slice.
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --tensor-parallel-size=1 --served-model-name=mymodel --kaito-config-file=/mnt/config/inference_config.yaml",
+			hasAdapters: false,
+			expectedGPU: "nvidia.com/mig-1g.10gb",
+		},
+
+		"test-model-time-slicing/vllm": {
+			workspace: timeSlicedWorkspace,
+			nodeCount: 1,
+			modelName: "test-model",
+			callMocks: func(c *test.MockClient) {
+				c.On("Get", mock.IsType(context.TODO()), mock.Anything, mock.IsType(&corev1.ConfigMap{}), mock.Anything).Return(nil)
+				c.On("Create", mock.IsType(context.TODO()), mock.IsType(&appsv1.Deployment{}), mock.Anything).Return(nil)
+			},
+			workload: "Deployment",
+			// The replicas sharing the GPU split its memory.
+			expectedCmd: "/bin/sh -c python3 /workspace/vllm/inference_api.py --kaito-config-file=/mnt/config/inference_config.yaml --kaito-max-gpu-memory-utilization=0.22 --served-model-name=mymodel --tensor-parallel-size=1",
+			hasAdapters: false,
+		},
+
 		"test-model-no-parallel/vllm": {
 			workspace: test.MockWorkspaceWithPresetVLLM,
 			nodeCount: 1,
@@ -192,6 +242,13 @@ func TestCreatePresetInference(t *testing.T) {
 				t.Errorf("%s parameters are not expected, got %s, expect %s ", k, params, expectedParams)
 			}
 
+			if tc.expectedGPU != "" {
+				limits := createdObject.(*appsv1.Deployment).Spec.Template.Spec.Containers[0].Resources.Limits
+				if gpus, ok := limits[tc.expectedGPU]; !ok || gpus.Value() != 1 {
+					t.Errorf("%s: expected 1 %s in the resource limits, got %v", k, tc.expectedGPU, limits)
+				}
+			}
+
 			// Check for adapter volume
 			if tc.hasAdapters {
 				var actualSecrets []string
@@ -6,16 +6,26 @@ import (
 	"context"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils"
 	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/kaito-project/kaito/pkg/workspace/manifests"
+	appsv1 "k8s.io/api/apps/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func CreateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, kubeClient client.Client) (client.Object, error) {
-	depObj := manifests.GenerateDeploymentManifestWithPodTemplate(ctx, workspaceObj, tolerations)
+func CreateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
+	kubeClient client.Client) (client.Object, error) {
+	depObj := GenerateTemplateInference(ctx, workspaceObj, revisionNum)
 	err := resources.CreateResource(ctx, client.Object(depObj), kubeClient)
 	if client.IgnoreAlreadyExists(err) != nil {
 		return nil, err
 	}
 	return depObj, nil
 }
+
+// GenerateTemplateInference renders the Deployment that runs the user provided pod template of the workspace.
+// The node affinity and the tolerations required by KAITO are injected into the template.
+func GenerateTemplateInference(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string) *appsv1.Deployment {
+	return manifests.GenerateDeploymentManifestWithPodTemplate(ctx, workspaceObj, revisionNum,
+		getTolerations(utils.GetGPUVendor(workspaceObj.Resource.InstanceType)))
+}
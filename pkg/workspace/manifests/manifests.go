@@ -6,6 +6,7 @@ package manifests
 import (
 	"context"
 	"fmt"
+	"net"
 
 	batchv1 "k8s.io/api/batch/v1"
 	"k8s.io/utils/pointer"
@@ -13,14 +14,160 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/kaito-project/kaito/pkg/utils/resources"
 	"github.com/samber/lo"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
+// ActivatorEndpointSliceManager is the value of the EndpointSlice managed-by label for the activator endpoints.
+const ActivatorEndpointSliceManager = "activator.kaito.sh"
+
 var controller = true
 
+// generateNodeRequirements converts the workspace label selector, including its match expressions, to node affinity
+// requirements in a stable order.
+func generateNodeRequirements(workspaceObj *kaitov1alpha1.Workspace) []corev1.NodeSelectorRequirement {
+	return resources.GetNodeSelectorRequirements(workspaceObj.Resource.LabelSelector)
+}
+
+// applySchedulingSpec merges the scheduling spec of the workspace into the pod spec generated for its workload.
+func applySchedulingSpec(workspaceObj *kaitov1alpha1.Workspace, podSpec *corev1.PodSpec) {
+	scheduling := workspaceObj.Scheduling
+	if scheduling == nil {
+		return
+	}
+	// The slices may be shared with the caller, new ones are allocated instead of appending in place.
+	podSpec.Tolerations = append(append([]corev1.Toleration{}, podSpec.Tolerations...), scheduling.Tolerations...)
+	if scheduling.PriorityClassName != "" {
+		podSpec.PriorityClassName = scheduling.PriorityClassName
+	}
+	podSpec.TopologySpreadConstraints = append(append([]corev1.TopologySpreadConstraint{}, podSpec.TopologySpreadConstraints...),
+		scheduling.TopologySpreadConstraints...)
+	if scheduling.Affinity != nil {
+		podSpec.Affinity = mergeAffinity(podSpec.Affinity, scheduling.Affinity)
+	}
+}
+
+// getPodOverrides returns the pod overrides of the preset used by the workspace, if any.
+func getPodOverrides(workspaceObj *kaitov1alpha1.Workspace) *kaitov1alpha1.PodOverrides {
+	if workspaceObj.Inference != nil && workspaceObj.Inference.Preset != nil {
+		return workspaceObj.Inference.Preset.PodOverrides
+	}
+	if workspaceObj.Tuning != nil && workspaceObj.Tuning.Preset != nil {
+		return workspaceObj.Tuning.Preset.PodOverrides
+	}
+	return nil
+}
+
+// applyPodOverrides merges the pod overrides of the preset on top of the generated pod template. The labels and
+// environment variables generated by KAITO take precedence, the first container is the model container.
+func applyPodOverrides(workspaceObj *kaitov1alpha1.Workspace, template *corev1.PodTemplateSpec) {
+	overrides := getPodOverrides(workspaceObj)
+	if overrides == nil {
+		return
+	}
+	// The labels of the template are shared with the selector of the workload, a new map is allocated instead.
+	if len(overrides.Labels) > 0 {
+		template.Labels = lo.Assign(overrides.Labels, template.Labels)
+	}
+	if len(overrides.Annotations) > 0 {
+		template.Annotations = lo.Assign(overrides.Annotations, template.Annotations)
+	}
+	podSpec := &template.Spec
+	podSpec.Volumes = append(append([]corev1.Volume{}, podSpec.Volumes...), overrides.Volumes...)
+	if overrides.SecurityContext != nil {
+		podSpec.SecurityContext = overrides.SecurityContext.DeepCopy()
+	}
+	if len(podSpec.Containers) == 0 {
+		return
+	}
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(append([]corev1.VolumeMount{}, container.VolumeMounts...), overrides.VolumeMounts...)
+	env := append([]corev1.EnvVar{}, container.Env...)
+	for _, envVar := range overrides.Env {
+		if !lo.ContainsBy(container.Env, func(e corev1.EnvVar) bool { return e.Name == envVar.Name }) {
+			env = append(env, envVar)
+		}
+	}
+	container.Env = env
+	if overrides.ContainerSecurityContext != nil {
+		container.SecurityContext = overrides.ContainerSecurityContext.DeepCopy()
+	}
+	if overrides.Resources != nil {
+		container.Resources.Requests = mergeResourceList(container.Resources.Requests, overrides.Resources.Requests)
+		container.Resources.Limits = mergeResourceList(container.Resources.Limits, overrides.Resources.Limits)
+	}
+}
+
+// mergeResourceList returns a new resource list with the quantities of the override replacing the generated ones.
+func mergeResourceList(generated, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return generated
+	}
+	merged := corev1.ResourceList{}
+	for name, quantity := range generated {
+		merged[name] = quantity.DeepCopy()
+	}
+	for name, quantity := range override {
+		merged[name] = quantity.DeepCopy()
+	}
+	return merged
+}
+
+// mergeAffinity returns the generated affinity merged with the additional affinity of the scheduling spec.
+func mergeAffinity(generated, additional *corev1.Affinity) *corev1.Affinity {
+	merged := generated.DeepCopy()
+	if merged == nil {
+		merged = &corev1.Affinity{}
+	}
+	if additional.NodeAffinity != nil {
+		if merged.NodeAffinity == nil {
+			merged.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = mergeNodeSelectors(
+			merged.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+			additional.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		merged.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			merged.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			additional.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+	}
+	if additional.PodAffinity != nil {
+		merged.PodAffinity = additional.PodAffinity.DeepCopy()
+	}
+	if additional.PodAntiAffinity != nil {
+		merged.PodAntiAffinity = additional.PodAntiAffinity.DeepCopy()
+	}
+	return merged
+}
+
+// mergeNodeSelectors returns the node selector which requires both node selectors. The terms of a node selector are
+// ORed, so every term of one selector is combined with every term of the other.
+func mergeNodeSelectors(a, b *corev1.NodeSelector) *corev1.NodeSelector {
+	if a == nil || len(a.NodeSelectorTerms) == 0 {
+		return b.DeepCopy()
+	}
+	if b == nil || len(b.NodeSelectorTerms) == 0 {
+		return a.DeepCopy()
+	}
+	merged := &corev1.NodeSelector{}
+	for _, termA := range a.NodeSelectorTerms {
+		for _, termB := range b.NodeSelectorTerms {
+			merged.NodeSelectorTerms = append(merged.NodeSelectorTerms, corev1.NodeSelectorTerm{
+				MatchExpressions: append(append([]corev1.NodeSelectorRequirement{}, termA.MatchExpressions...), termB.MatchExpressions...),
+				MatchFields:      append(append([]corev1.NodeSelectorRequirement{}, termA.MatchFields...), termB.MatchFields...),
+			})
+		}
+	}
+	return merged
+}
+
 func GenerateHeadlessServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace) *corev1.Service {
 	serviceName := fmt.Sprintf("%s-headless", workspaceObj.Name)
 	selector := map[string]string{
@@ -107,19 +254,336 @@ func GenerateServiceManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Wo
 	}
 }
 
-func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, imageName string,
+// GenerateNetworkPolicyManifest generates the NetworkPolicy which restricts the clients of the inference workload to
+// the peers listed in the access spec of the workspace. The KAITO controller pods are always admitted, since the
+// controller scrapes the metrics of the inference pods, but not the other pods of the KAITO namespace. The pods of
+// distributed inference can still reach each other, e.g., on the torchrun rendezvous port.
+func GenerateNetworkPolicyManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, kaitoNamespace string, supportsDistributedInference bool) *networkingv1.NetworkPolicy {
+	selector := map[string]string{
+		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
+	}
+	access := workspaceObj.Access
+
+	peers := []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{corev1.LabelMetadataName: kaitoNamespace},
+			},
+			PodSelector: &v1.LabelSelector{
+				MatchLabels: map[string]string{kaitov1alpha1.LabelWorkspaceController: "true"},
+			},
+		},
+	}
+	namespaces := access.Namespaces
+	// The requests of the exposed inference service are proxied by the ingress controller or the Gateway.
+	if workspaceObj.Expose != nil {
+		namespaces = append(append([]string{}, namespaces...), getExposeControllerNamespace(workspaceObj))
+	}
+	if len(namespaces) > 0 {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &v1.LabelSelector{
+				MatchExpressions: []v1.LabelSelectorRequirement{
+					{
+						Key:      corev1.LabelMetadataName,
+						Operator: v1.LabelSelectorOpIn,
+						Values:   lo.Uniq(namespaces),
+					},
+				},
+			},
+		})
+	}
+	for _, peer := range access.Peers {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: peer.NamespaceSelector.DeepCopy(),
+			PodSelector:       peer.PodSelector.DeepCopy(),
+		})
+	}
+
+	ingress := []networkingv1.NetworkPolicyIngressRule{
+		{
+			Ports: []networkingv1.NetworkPolicyPort{
+				{
+					Protocol: lo.ToPtr(corev1.ProtocolTCP),
+					Port:     lo.ToPtr(intstr.FromInt32(5000)),
+				},
+			},
+			From: peers,
+		},
+	}
+	if supportsDistributedInference {
+		ingress = append(ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: &v1.LabelSelector{
+						MatchLabels: selector,
+					},
+				},
+			},
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      workspaceObj.Name,
+			Namespace: workspaceObj.Namespace,
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: kaitov1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					UID:        workspaceObj.UID,
+					Name:       workspaceObj.Name,
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: v1.LabelSelector{
+				MatchLabels: selector,
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress:     ingress,
+		},
+	}
+}
+
+// getExposeControllerNamespace returns the namespace of the pods which proxy the requests of the exposed inference service.
+func getExposeControllerNamespace(workspaceObj *kaitov1alpha1.Workspace) string {
+	expose := workspaceObj.Expose
+	if expose.ControllerNamespace != "" {
+		return expose.ControllerNamespace
+	}
+	if expose.GatewayRef != nil && expose.GatewayRef.Namespace != "" {
+		return expose.GatewayRef.Namespace
+	}
+	return workspaceObj.Namespace
+}
+
+// getExposePathPrefix returns the path prefix of the requests routed to the exposed inference service.
+func getExposePathPrefix(workspaceObj *kaitov1alpha1.Workspace) string {
+	if workspaceObj.Expose.PathPrefix == "" {
+		return "/"
+	}
+	return workspaceObj.Expose.PathPrefix
+}
+
+// GenerateIngressManifest generates the Ingress which exposes the Service of the workspace outside of the cluster.
+func GenerateIngressManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace) *networkingv1.Ingress {
+	expose := workspaceObj.Expose
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      workspaceObj.Name,
+			Namespace: workspaceObj.Namespace,
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: kaitov1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					UID:        workspaceObj.UID,
+					Name:       workspaceObj.Name,
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: expose.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: expose.Hostname,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     getExposePathPrefix(workspaceObj),
+									PathType: lo.ToPtr(networkingv1.PathTypePrefix),
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: workspaceObj.Name,
+											Port: networkingv1.ServiceBackendPort{
+												Number: 80,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if expose.TLSSecretName != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{
+			{
+				Hosts:      []string{expose.Hostname},
+				SecretName: expose.TLSSecretName,
+			},
+		}
+	}
+	return ingress
+}
+
+// GenerateHTTPRouteManifest generates the Gateway API HTTPRoute which attaches the Service of the workspace to a
+// Gateway. If model routing is enabled, only the requests whose model header matches the model name are routed to
+// the workspace, so that multiple workspaces can share the host name of the Gateway.
+func GenerateHTTPRouteManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, modelName string) *gatewayv1.HTTPRoute {
+	expose := workspaceObj.Expose
+	parentRef := gatewayv1.ParentReference{
+		Name: gatewayv1.ObjectName(expose.GatewayRef.Name),
+	}
+	if expose.GatewayRef.Namespace != "" {
+		parentRef.Namespace = lo.ToPtr(gatewayv1.Namespace(expose.GatewayRef.Namespace))
+	}
+	if expose.GatewayRef.SectionName != "" {
+		parentRef.SectionName = lo.ToPtr(gatewayv1.SectionName(expose.GatewayRef.SectionName))
+	}
+
+	match := gatewayv1.HTTPRouteMatch{
+		Path: &gatewayv1.HTTPPathMatch{
+			Type:  lo.ToPtr(gatewayv1.PathMatchPathPrefix),
+			Value: lo.ToPtr(getExposePathPrefix(workspaceObj)),
+		},
+	}
+	if expose.ModelRouting != nil {
+		headerName := expose.ModelRouting.HeaderName
+		if headerName == "" {
+			headerName = kaitov1alpha1.DefaultModelRoutingHeaderName
+		}
+		match.Headers = []gatewayv1.HTTPHeaderMatch{
+			{
+				Type:  lo.ToPtr(gatewayv1.HeaderMatchExact),
+				Name:  gatewayv1.HTTPHeaderName(headerName),
+				Value: modelName,
+			},
+		}
+	}
+
+	return &gatewayv1.HTTPRoute{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      workspaceObj.Name,
+			Namespace: workspaceObj.Namespace,
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: kaitov1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					UID:        workspaceObj.UID,
+					Name:       workspaceObj.Name,
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			CommonRouteSpec: gatewayv1.CommonRouteSpec{
+				ParentRefs: []gatewayv1.ParentReference{parentRef},
+			},
+			Hostnames: []gatewayv1.Hostname{gatewayv1.Hostname(expose.Hostname)},
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches: []gatewayv1.HTTPRouteMatch{match},
+					BackendRefs: []gatewayv1.HTTPBackendRef{
+						{
+							BackendRef: gatewayv1.BackendRef{
+								BackendObjectReference: gatewayv1.BackendObjectReference{
+									Name: gatewayv1.ObjectName(workspaceObj.Name),
+									Port: lo.ToPtr(gatewayv1.PortNumber(80)),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// GenerateActivatorEndpointSliceManifest generates an EndpointSlice that adds the activator as an endpoint of the
+// workspace Service, so that the requests sent to an idle workspace are received by the activator.
+func GenerateActivatorEndpointSliceManifest(workspaceObj *kaitov1alpha1.Workspace, activatorAddress string, activatorPort int32) *discoveryv1.EndpointSlice {
+	addressType := discoveryv1.AddressTypeIPv4
+	if ip := net.ParseIP(activatorAddress); ip != nil && ip.To4() == nil {
+		addressType = discoveryv1.AddressTypeIPv6
+	}
+
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-activator", workspaceObj.Name),
+			Namespace: workspaceObj.Namespace,
+			Labels: map[string]string{
+				discoveryv1.LabelServiceName: workspaceObj.Name,
+				discoveryv1.LabelManagedBy:   ActivatorEndpointSliceManager,
+			},
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: kaitov1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					UID:        workspaceObj.UID,
+					Name:       workspaceObj.Name,
+					Controller: &controller,
+				},
+			},
+		},
+		AddressType: addressType,
+		Endpoints: []discoveryv1.Endpoint{
+			{
+				Addresses:  []string{activatorAddress},
+				Conditions: discoveryv1.EndpointConditions{Ready: lo.ToPtr(true)},
+			},
+		},
+		// The port name matches the HTTP API port of the workspace Service.
+		Ports: []discoveryv1.EndpointPort{
+			{
+				Name:     lo.ToPtr("http"),
+				Protocol: lo.ToPtr(corev1.ProtocolTCP),
+				Port:     lo.ToPtr(activatorPort),
+			},
+		},
+	}
+}
+
+// GeneratePodDisruptionBudgetManifest generates the PodDisruptionBudget of the inference workload. If the workspace
+// does not configure a disruption budget, one pod of a Deployment can be evicted at a time while the pods of a
+// distributed StatefulSet cannot be evicted, since losing any of them breaks the torchrun group. Such a budget blocks
+// node drains, node upgrades and node consolidation, the workspace can opt out with maxUnavailable: 1.
+func GeneratePodDisruptionBudgetManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, supportsDistributedInference bool) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+	if supportsDistributedInference {
+		maxUnavailable = intstr.FromInt32(0)
+	}
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &v1.LabelSelector{
+			MatchLabels: map[string]string{
+				kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
+			},
+		},
+		MaxUnavailable: &maxUnavailable,
+	}
+	if budget := workspaceObj.Inference.DisruptionBudget; budget != nil && (budget.MaxUnavailable != nil || budget.MinAvailable != nil) {
+		spec.MaxUnavailable = budget.MaxUnavailable
+		spec.MinAvailable = budget.MinAvailable
+	}
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      workspaceObj.Name,
+			Namespace: workspaceObj.Namespace,
+			OwnerReferences: []v1.OwnerReference{
+				{
+					APIVersion: kaitov1alpha1.GroupVersion.String(),
+					Kind:       "Workspace",
+					UID:        workspaceObj.UID,
+					Name:       workspaceObj.Name,
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: spec,
+	}
+}
+
+func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string, imageName string,
 	imagePullSecretRefs []corev1.LocalObjectReference, replicas int, commands []string, containerPorts []corev1.ContainerPort,
 	livenessProbe, readinessProbe *corev1.Probe, resourceRequirements corev1.ResourceRequirements,
 	tolerations []corev1.Toleration, volumes []corev1.Volume, volumeMount []corev1.VolumeMount) *appsv1.StatefulSet {
 
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
-		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-			Key:      key,
-			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
-		})
-	}
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	selector := map[string]string{
 		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
@@ -141,11 +605,18 @@ func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha
 					Controller: &controller,
 				},
 			},
+			Annotations: map[string]string{
+				kaitov1alpha1.WorkspaceRevisionAnnotation: revisionNum,
+			},
 		},
 		Spec: appsv1.StatefulSetSpec{
 			Replicas:            lo.ToPtr(int32(replicas)),
 			PodManagementPolicy: appsv1.ParallelPodManagement,
-			Selector:            labelselector,
+			// Pods are updated one at a time in reverse ordinal order.
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.RollingUpdateStatefulSetStrategyType,
+			},
+			Selector: labelselector,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: v1.ObjectMeta{
 					Labels: selector,
@@ -183,6 +654,8 @@ func GenerateStatefulSetManifest(ctx context.Context, workspaceObj *kaitov1alpha
 		},
 	}
 	ss.Spec.ServiceName = fmt.Sprintf("%s-headless", workspaceObj.Name)
+	applySchedulingSpec(workspaceObj, &ss.Spec.Template.Spec)
+	applyPodOverrides(workspaceObj, &ss.Spec.Template)
 	return ss
 }
 
@@ -216,7 +689,7 @@ func GenerateTuningJobManifest(ctx context.Context, wObj *kaitov1alpha1.Workspac
 	}, sidecarContainers...)
 
 	var numBackoff int32
-	return &batchv1.Job{
+	job := &batchv1.Job{
 		TypeMeta: v1.TypeMeta{
 			APIVersion: "batch/v1",
 			Kind:       "Job",
@@ -255,6 +728,28 @@ func GenerateTuningJobManifest(ctx context.Context, wObj *kaitov1alpha1.Workspac
 			},
 		},
 	}
+
+	applySchedulingSpec(wObj, &job.Spec.Template.Spec)
+	applyPodOverrides(wObj, &job.Spec.Template)
+
+	// The pods on spot nodes are disrupted when the nodes are evicted. The disrupted pods are not counted as failures,
+	// so the job creates a new pod once the evicted node is replaced instead of failing.
+	if wObj.Resource.CapacityType == kaitov1alpha1.CapacityTypeSpot || wObj.Resource.CapacityType == kaitov1alpha1.CapacityTypeSpotWithFallback {
+		job.Spec.PodFailurePolicy = &batchv1.PodFailurePolicy{
+			Rules: []batchv1.PodFailurePolicyRule{
+				{
+					Action: batchv1.PodFailurePolicyActionIgnore,
+					OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{
+						{
+							Type:   corev1.DisruptionTarget,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			},
+		}
+	}
+	return job
 }
 
 func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string, imageName string,
@@ -262,14 +757,7 @@ func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1
 	livenessProbe, readinessProbe *corev1.Probe, resourceRequirements corev1.ResourceRequirements,
 	tolerations []corev1.Toleration, volumes []corev1.Volume, volumeMount []corev1.VolumeMount) *appsv1.Deployment {
 
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
-		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-			Key:      key,
-			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
-		})
-	}
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	selector := map[string]string{
 		kaitov1alpha1.LabelWorkspaceName: workspaceObj.Name,
@@ -283,7 +771,7 @@ func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1
 		initContainers, envs = GenerateInitContainers(workspaceObj, volumeMount)
 	}
 
-	return &appsv1.Deployment{
+	deployment := &appsv1.Deployment{
 		ObjectMeta: v1.ObjectMeta{
 			Name:      workspaceObj.Name,
 			Namespace: workspaceObj.Namespace,
@@ -353,6 +841,9 @@ func GenerateDeploymentManifest(ctx context.Context, workspaceObj *kaitov1alpha1
 			},
 		},
 	}
+	applySchedulingSpec(workspaceObj, &deployment.Spec.Template.Spec)
+	applyPodOverrides(workspaceObj, &deployment.Spec.Template)
+	return deployment
 }
 
 func GenerateInitContainers(wObj *kaitov1alpha1.Workspace, volumeMount []corev1.VolumeMount) ([]corev1.Container, []corev1.EnvVar) {
@@ -379,15 +870,9 @@ func GenerateInitContainers(wObj *kaitov1alpha1.Workspace, volumeMount []corev1.
 	return initContainers, envs
 }
 
-func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, tolerations []corev1.Toleration) *appsv1.Deployment {
-	nodeRequirements := make([]corev1.NodeSelectorRequirement, 0, len(workspaceObj.Resource.LabelSelector.MatchLabels))
-	for key, value := range workspaceObj.Resource.LabelSelector.MatchLabels {
-		nodeRequirements = append(nodeRequirements, corev1.NodeSelectorRequirement{
-			Key:      key,
-			Operator: corev1.NodeSelectorOpIn,
-			Values:   []string{value},
-		})
-	}
+func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj *kaitov1alpha1.Workspace, revisionNum string,
+	tolerations []corev1.Toleration) *appsv1.Deployment {
+	nodeRequirements := generateNodeRequirements(workspaceObj)
 
 	templateCopy := workspaceObj.Inference.Template.DeepCopy()
 
@@ -419,6 +904,7 @@ func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj
 	} else {
 		templateCopy.Spec.Tolerations = append(templateCopy.Spec.Tolerations, tolerations...)
 	}
+	applySchedulingSpec(workspaceObj, &templateCopy.Spec)
 
 	return &appsv1.Deployment{
 		ObjectMeta: v1.ObjectMeta{
@@ -433,6 +919,9 @@ func GenerateDeploymentManifestWithPodTemplate(ctx context.Context, workspaceObj
 					Controller: &controller,
 				},
 			},
+			Annotations: map[string]string{
+				kaitov1alpha1.WorkspaceRevisionAnnotation: revisionNum,
+			},
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: lo.ToPtr(int32(*workspaceObj.Resource.Count)),
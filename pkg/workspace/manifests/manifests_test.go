@@ -13,7 +13,15 @@ import (
 	"testing"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
+	"github.com/samber/lo"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func TestGenerateStatefulSetManifest(t *testing.T) {
@@ -23,6 +31,7 @@ func TestGenerateStatefulSetManifest(t *testing.T) {
 		workspace := test.MockWorkspaceWithPreset
 
 		obj := GenerateStatefulSetManifest(context.TODO(), workspace,
+			"1", //revisionNum
 			"",  //imageName
 			nil, //imagePullSecretRefs
 			*workspace.Resource.Count,
@@ -101,12 +110,435 @@ func TestGenerateDeploymentManifest(t *testing.T) {
 	})
 }
 
+func TestGenerateDeploymentManifestWithMatchExpressions(t *testing.T) {
+	t.Run("generate deployment with match expressions", func(t *testing.T) {
+
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Resource.LabelSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{Key: "topology.kubernetes.io/zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"eastus-1", "eastus-2"}},
+			{Key: "node.kaito.sh/draining", Operator: metav1.LabelSelectorOpDoesNotExist},
+		}
+
+		obj := GenerateDeploymentManifest(context.TODO(), workspace, test.MockWorkspaceWithPresetHash,
+			"",  //imageName
+			nil, //imagePullSecretRefs
+			*workspace.Resource.Count,
+			nil, //commands
+			nil, //containerPorts
+			nil, //livenessProbe
+			nil, //readinessProbe
+			v1.ResourceRequirements{},
+			nil, //tolerations
+			nil, //volumes
+			nil, //volumeMount
+		)
+
+		nodeReq := obj.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions
+		expected := []v1.NodeSelectorRequirement{
+			{Key: "apps", Operator: v1.NodeSelectorOpIn, Values: []string{"test"}},
+			{Key: "topology.kubernetes.io/zone", Operator: v1.NodeSelectorOpIn, Values: []string{"eastus-1", "eastus-2"}},
+			{Key: "node.kaito.sh/draining", Operator: v1.NodeSelectorOpDoesNotExist},
+		}
+		if !reflect.DeepEqual(expected, nodeReq) {
+			t.Errorf("node affinity is wrong, got %v, expected %v", nodeReq, expected)
+		}
+	})
+}
+
+func TestGenerateDeploymentManifestWithScheduling(t *testing.T) {
+	t.Run("generate deployment with scheduling spec", func(t *testing.T) {
+
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Scheduling = &kaitov1alpha1.SchedulingSpec{
+			Tolerations:       []v1.Toleration{{Key: "example.com/dedicated", Operator: v1.TolerationOpExists}},
+			PriorityClassName: "inference-high",
+			TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway},
+			},
+			Affinity: &v1.Affinity{
+				NodeAffinity: &v1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+						NodeSelectorTerms: []v1.NodeSelectorTerm{
+							{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"zone-1"}}}},
+							{MatchExpressions: []v1.NodeSelectorRequirement{{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{"zone-2"}}}},
+						},
+					},
+				},
+				PodAntiAffinity: &v1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+						{Weight: 1, PodAffinityTerm: v1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+					},
+				},
+			},
+		}
+		gpuTolerations := []v1.Toleration{{Key: "nvidia.com/gpu", Operator: v1.TolerationOpExists}}
+
+		obj := GenerateDeploymentManifest(context.TODO(), workspace, test.MockWorkspaceWithPresetHash,
+			"",  //imageName
+			nil, //imagePullSecretRefs
+			*workspace.Resource.Count,
+			nil, //commands
+			nil, //containerPorts
+			nil, //livenessProbe
+			nil, //readinessProbe
+			v1.ResourceRequirements{},
+			gpuTolerations,
+			nil, //volumes
+			nil, //volumeMount
+		)
+
+		podSpec := obj.Spec.Template.Spec
+		expectedTolerations := []v1.Toleration{gpuTolerations[0], workspace.Scheduling.Tolerations[0]}
+		if !reflect.DeepEqual(expectedTolerations, podSpec.Tolerations) {
+			t.Errorf("tolerations are wrong, got %v, expected %v", podSpec.Tolerations, expectedTolerations)
+		}
+		if len(gpuTolerations) != 1 {
+			t.Errorf("GPU tolerations of the caller must not be modified")
+		}
+		if podSpec.PriorityClassName != "inference-high" {
+			t.Errorf("priority class name is wrong, got %s", podSpec.PriorityClassName)
+		}
+		if !reflect.DeepEqual(workspace.Scheduling.TopologySpreadConstraints, podSpec.TopologySpreadConstraints) {
+			t.Errorf("topology spread constraints are wrong")
+		}
+
+		// Each additional term must be combined with the requirements of the label selector.
+		terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if len(terms) != 2 {
+			t.Fatalf("expected 2 node selector terms, got %d", len(terms))
+		}
+		for i, term := range terms {
+			if !kvInNodeRequirement("apps", "test", term.MatchExpressions) {
+				t.Errorf("node selector term %d does not require the label selector", i)
+			}
+			if !kvInNodeRequirement("zone", fmt.Sprintf("zone-%d", i+1), term.MatchExpressions) {
+				t.Errorf("node selector term %d does not require the additional affinity", i)
+			}
+		}
+		if !reflect.DeepEqual(workspace.Scheduling.Affinity.PodAntiAffinity, podSpec.Affinity.PodAntiAffinity) {
+			t.Errorf("pod anti-affinity is wrong")
+		}
+	})
+}
+
+func TestGenerateNetworkPolicyManifest(t *testing.T) {
+	t.Run("generate network policy of a distributed workspace", func(t *testing.T) {
+		workspace := test.MockWorkspaceDistributedModel.DeepCopy()
+		workspace.Access = &kaitov1alpha1.AccessSpec{
+			Namespaces: []string{"clients", "kaito-workspace"},
+			Peers: []kaitov1alpha1.AccessPeer{
+				{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "chat"}}},
+			},
+		}
+
+		obj := GenerateNetworkPolicyManifest(context.TODO(), workspace, "kaito-workspace", true)
+
+		if len(obj.OwnerReferences) != 1 || obj.OwnerReferences[0].UID != workspace.UID {
+			t.Errorf("network policy must be owned by the workspace")
+		}
+		expectedSelector := map[string]string{kaitov1alpha1.LabelWorkspaceName: workspace.Name}
+		if !reflect.DeepEqual(expectedSelector, obj.Spec.PodSelector.MatchLabels) {
+			t.Errorf("pod selector is wrong")
+		}
+		if len(obj.Spec.Ingress) != 2 {
+			t.Fatalf("expected 2 ingress rules, got %d", len(obj.Spec.Ingress))
+		}
+
+		clients := obj.Spec.Ingress[0]
+		if len(clients.Ports) != 1 || clients.Ports[0].Port.IntValue() != 5000 {
+			t.Errorf("clients must only be admitted on the serving port, got %v", clients.Ports)
+		}
+		if len(clients.From) != 3 {
+			t.Fatalf("expected 3 peers, got %d", len(clients.From))
+		}
+		controllerPeer := clients.From[0]
+		if controllerPeer.NamespaceSelector.MatchLabels[v1.LabelMetadataName] != "kaito-workspace" ||
+			controllerPeer.PodSelector.MatchLabels[kaitov1alpha1.LabelWorkspaceController] != "true" {
+			t.Errorf("only the controller pods of the KAITO namespace must be admitted, got %v", controllerPeer)
+		}
+		expectedNamespaces := []string{"clients", "kaito-workspace"}
+		if !reflect.DeepEqual(expectedNamespaces, clients.From[1].NamespaceSelector.MatchExpressions[0].Values) {
+			t.Errorf("namespaces are wrong, got %v", clients.From[1].NamespaceSelector.MatchExpressions[0].Values)
+		}
+		if clients.From[2].NamespaceSelector != nil || clients.From[2].PodSelector.MatchLabels["app"] != "chat" {
+			t.Errorf("pod selector peer is wrong, got %v", clients.From[2])
+		}
+
+		workers := obj.Spec.Ingress[1]
+		if len(workers.Ports) != 0 || !reflect.DeepEqual(expectedSelector, workers.From[0].PodSelector.MatchLabels) {
+			t.Errorf("pods of the workspace must reach each other, got %v", workers)
+		}
+	})
+
+	t.Run("generate network policy of a single node workspace", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Access = &kaitov1alpha1.AccessSpec{Namespaces: []string{"clients"}}
+
+		obj := GenerateNetworkPolicyManifest(context.TODO(), workspace, "kaito-workspace", false)
+
+		if len(obj.Spec.Ingress) != 1 {
+			t.Errorf("expected 1 ingress rule, got %d", len(obj.Spec.Ingress))
+		}
+	})
+
+	t.Run("generate network policy of an exposed workspace", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Access = &kaitov1alpha1.AccessSpec{Namespaces: []string{"clients"}}
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{
+			Hostname:   "llm.example.com",
+			GatewayRef: &kaitov1alpha1.GatewayReference{Name: "inference-gateway", Namespace: "gateway"},
+		}
+
+		obj := GenerateNetworkPolicyManifest(context.TODO(), workspace, "kaito-workspace", false)
+
+		expectedNamespaces := []string{"clients", "gateway"}
+		if values := obj.Spec.Ingress[0].From[1].NamespaceSelector.MatchExpressions[0].Values; !reflect.DeepEqual(expectedNamespaces, values) {
+			t.Errorf("the namespace of the Gateway must be admitted, got %v", values)
+		}
+
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{Hostname: "llm.example.com", ControllerNamespace: "ingress-nginx"}
+		obj = GenerateNetworkPolicyManifest(context.TODO(), workspace, "kaito-workspace", false)
+
+		expectedNamespaces = []string{"clients", "ingress-nginx"}
+		if values := obj.Spec.Ingress[0].From[1].NamespaceSelector.MatchExpressions[0].Values; !reflect.DeepEqual(expectedNamespaces, values) {
+			t.Errorf("the namespace of the ingress controller must be admitted, got %v", values)
+		}
+	})
+}
+
+func TestGenerateIngressManifest(t *testing.T) {
+	t.Run("generate ingress with TLS", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{
+			Hostname:         "llm.example.com",
+			PathPrefix:       "/falcon",
+			IngressClassName: lo.ToPtr("nginx"),
+			TLSSecretName:    "llm-tls",
+		}
+
+		obj := GenerateIngressManifest(context.TODO(), workspace)
+
+		if len(obj.OwnerReferences) != 1 || obj.OwnerReferences[0].UID != workspace.UID {
+			t.Errorf("ingress must be owned by the workspace")
+		}
+		if obj.Spec.IngressClassName == nil || *obj.Spec.IngressClassName != "nginx" {
+			t.Errorf("ingress class name is wrong")
+		}
+		rule := obj.Spec.Rules[0]
+		if rule.Host != "llm.example.com" {
+			t.Errorf("host is wrong, got %s", rule.Host)
+		}
+		path := rule.HTTP.Paths[0]
+		if path.Path != "/falcon" || *path.PathType != networkingv1.PathTypePrefix {
+			t.Errorf("path is wrong, got %v", path)
+		}
+		if path.Backend.Service.Name != workspace.Name || path.Backend.Service.Port.Number != 80 {
+			t.Errorf("backend is wrong, got %v", path.Backend.Service)
+		}
+		expectedTLS := []networkingv1.IngressTLS{{Hosts: []string{"llm.example.com"}, SecretName: "llm-tls"}}
+		if !reflect.DeepEqual(expectedTLS, obj.Spec.TLS) {
+			t.Errorf("tls is wrong, got %v", obj.Spec.TLS)
+		}
+	})
+
+	t.Run("generate ingress without TLS", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{Hostname: "llm.example.com"}
+
+		obj := GenerateIngressManifest(context.TODO(), workspace)
+
+		if obj.Spec.Rules[0].HTTP.Paths[0].Path != "/" {
+			t.Errorf("path must default to /, got %s", obj.Spec.Rules[0].HTTP.Paths[0].Path)
+		}
+		if obj.Spec.IngressClassName != nil || len(obj.Spec.TLS) != 0 {
+			t.Errorf("ingress class name and tls must not be set")
+		}
+	})
+}
+
+func TestGenerateHTTPRouteManifest(t *testing.T) {
+	t.Run("generate http route with model routing", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{
+			Hostname:     "llm.example.com",
+			GatewayRef:   &kaitov1alpha1.GatewayReference{Name: "inference-gateway", Namespace: "gateway", SectionName: "https"},
+			ModelRouting: &kaitov1alpha1.ModelRoutingSpec{},
+		}
+
+		obj := GenerateHTTPRouteManifest(context.TODO(), workspace, "test-model")
+
+		if len(obj.OwnerReferences) != 1 || obj.OwnerReferences[0].UID != workspace.UID {
+			t.Errorf("http route must be owned by the workspace")
+		}
+		expectedParent := gatewayv1.ParentReference{
+			Name:        "inference-gateway",
+			Namespace:   lo.ToPtr(gatewayv1.Namespace("gateway")),
+			SectionName: lo.ToPtr(gatewayv1.SectionName("https")),
+		}
+		if !reflect.DeepEqual([]gatewayv1.ParentReference{expectedParent}, obj.Spec.ParentRefs) {
+			t.Errorf("parent refs are wrong, got %v", obj.Spec.ParentRefs)
+		}
+		if !reflect.DeepEqual([]gatewayv1.Hostname{"llm.example.com"}, obj.Spec.Hostnames) {
+			t.Errorf("hostnames are wrong, got %v", obj.Spec.Hostnames)
+		}
+		rule := obj.Spec.Rules[0]
+		match := rule.Matches[0]
+		if *match.Path.Type != gatewayv1.PathMatchPathPrefix || *match.Path.Value != "/" {
+			t.Errorf("path match is wrong, got %v", match.Path)
+		}
+		expectedHeaders := []gatewayv1.HTTPHeaderMatch{
+			{Type: lo.ToPtr(gatewayv1.HeaderMatchExact), Name: kaitov1alpha1.DefaultModelRoutingHeaderName, Value: "test-model"},
+		}
+		if !reflect.DeepEqual(expectedHeaders, match.Headers) {
+			t.Errorf("header match is wrong, got %v", match.Headers)
+		}
+		backend := rule.BackendRefs[0]
+		if string(backend.Name) != workspace.Name || *backend.Port != 80 {
+			t.Errorf("backend ref is wrong, got %v", backend)
+		}
+	})
+
+	t.Run("generate http route without model routing", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Expose = &kaitov1alpha1.ExposeSpec{
+			Hostname:   "llm.example.com",
+			PathPrefix: "/falcon",
+			GatewayRef: &kaitov1alpha1.GatewayReference{Name: "inference-gateway"},
+		}
+
+		obj := GenerateHTTPRouteManifest(context.TODO(), workspace, "test-model")
+
+		parent := obj.Spec.ParentRefs[0]
+		if parent.Namespace != nil || parent.SectionName != nil {
+			t.Errorf("parent ref must default to the gateway in the workspace namespace, got %v", parent)
+		}
+		match := obj.Spec.Rules[0].Matches[0]
+		if *match.Path.Value != "/falcon" || len(match.Headers) != 0 {
+			t.Errorf("match is wrong, got %v", match)
+		}
+	})
+}
+
+func TestGeneratePodDisruptionBudgetManifest(t *testing.T) {
+	t.Run("generate default budget of a distributed statefulset", func(t *testing.T) {
+		workspace := test.MockWorkspaceDistributedModel
+
+		obj := GeneratePodDisruptionBudgetManifest(context.TODO(), workspace, true)
+
+		if obj.Name != workspace.Name || obj.Namespace != workspace.Namespace {
+			t.Errorf("name of the budget is wrong, got %s/%s", obj.Namespace, obj.Name)
+		}
+		if len(obj.OwnerReferences) != 1 || obj.OwnerReferences[0].UID != workspace.UID {
+			t.Errorf("budget must be owned by the workspace")
+		}
+		expectedSelector := map[string]string{kaitov1alpha1.LabelWorkspaceName: workspace.Name}
+		if !reflect.DeepEqual(expectedSelector, obj.Spec.Selector.MatchLabels) {
+			t.Errorf("budget selector is wrong")
+		}
+		if obj.Spec.MaxUnavailable == nil || obj.Spec.MaxUnavailable.IntValue() != 0 || obj.Spec.MinAvailable != nil {
+			t.Errorf("distributed inference pods must not be evicted, got %v", obj.Spec)
+		}
+	})
+
+	t.Run("generate budget of the workspace spec", func(t *testing.T) {
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		minAvailable := intstr.FromString("50%")
+		workspace.Inference.DisruptionBudget = &kaitov1alpha1.DisruptionBudgetSpec{MinAvailable: &minAvailable}
+
+		obj := GeneratePodDisruptionBudgetManifest(context.TODO(), workspace, false)
+
+		if obj.Spec.MaxUnavailable != nil || !reflect.DeepEqual(&minAvailable, obj.Spec.MinAvailable) {
+			t.Errorf("budget is wrong, got %v", obj.Spec)
+		}
+	})
+}
+
+func TestGenerateDeploymentManifestWithPodOverrides(t *testing.T) {
+	t.Run("generate deployment with pod overrides", func(t *testing.T) {
+
+		workspace := test.MockWorkspaceWithPreset.DeepCopy()
+		workspace.Inference.Preset.PodOverrides = &kaitov1alpha1.PodOverrides{
+			Labels:      map[string]string{"sidecar.istio.io/inject": "true"},
+			Annotations: map[string]string{"example.com/cost-center": "ml"},
+			Env:         []v1.EnvVar{{Name: "HF_HUB_OFFLINE", Value: "1"}},
+			Volumes: []v1.Volume{
+				{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+			},
+			VolumeMounts: []v1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+			Resources: &kaitov1alpha1.PodOverridesResources{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			},
+		}
+		resourceRequirements := v1.ResourceRequirements{
+			Requests: v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+			Limits:   v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")},
+		}
+		volumes := []v1.Volume{{Name: "dshm"}}
+		volumeMounts := []v1.VolumeMount{{Name: "dshm", MountPath: "/dev/shm"}}
+
+		obj := GenerateDeploymentManifest(context.TODO(), workspace, test.MockWorkspaceWithPresetHash,
+			"",  //imageName
+			nil, //imagePullSecretRefs
+			*workspace.Resource.Count,
+			[]string{"python", "inference_api.py"},
+			nil, //containerPorts
+			nil, //livenessProbe
+			nil, //readinessProbe
+			resourceRequirements,
+			nil, //tolerations
+			volumes,
+			volumeMounts,
+		)
+
+		template := obj.Spec.Template
+		expectedLabels := map[string]string{
+			kaitov1alpha1.LabelWorkspaceName: workspace.Name,
+			"sidecar.istio.io/inject":        "true",
+		}
+		if !reflect.DeepEqual(expectedLabels, template.Labels) {
+			t.Errorf("template labels are wrong, got %v, expected %v", template.Labels, expectedLabels)
+		}
+		if len(obj.Spec.Selector.MatchLabels) != 1 {
+			t.Errorf("workload selector must not include the override labels, got %v", obj.Spec.Selector.MatchLabels)
+		}
+		if template.Annotations["example.com/cost-center"] != "ml" {
+			t.Errorf("template annotations are wrong, got %v", template.Annotations)
+		}
+
+		container := template.Spec.Containers[0]
+		if !reflect.DeepEqual([]string{"python", "inference_api.py"}, container.Command) {
+			t.Errorf("command must not be changed, got %v", container.Command)
+		}
+		if !reflect.DeepEqual(workspace.Inference.Preset.PodOverrides.Env, container.Env) {
+			t.Errorf("env is wrong, got %v", container.Env)
+		}
+		if len(template.Spec.Volumes) != 2 || template.Spec.Volumes[1].Name != "cache" {
+			t.Errorf("volumes are wrong, got %v", template.Spec.Volumes)
+		}
+		if len(container.VolumeMounts) != 2 || container.VolumeMounts[1].Name != "cache" {
+			t.Errorf("volume mounts are wrong, got %v", container.VolumeMounts)
+		}
+		if len(volumes) != 1 || len(volumeMounts) != 1 {
+			t.Errorf("volumes of the caller must not be modified")
+		}
+		if cpu := container.Resources.Requests[v1.ResourceCPU]; cpu.String() != "4" {
+			t.Errorf("cpu request is wrong, got %s", cpu.String())
+		}
+		if gpu := container.Resources.Requests["nvidia.com/gpu"]; gpu.String() != "1" {
+			t.Errorf("gpu request is wrong, got %s", gpu.String())
+		}
+		if _, ok := resourceRequirements.Requests[v1.ResourceCPU]; ok {
+			t.Errorf("resource requirements of the caller must not be modified")
+		}
+	})
+}
+
 func TestGenerateDeploymentManifestWithPodTemplate(t *testing.T) {
 	t.Run("generate deployment with pod template", func(t *testing.T) {
 
 		workspace := test.MockWorkspaceWithInferenceTemplate
 
-		obj := GenerateDeploymentManifestWithPodTemplate(context.TODO(), workspace, nil)
+		obj := GenerateDeploymentManifestWithPodTemplate(context.TODO(), workspace, "1", nil)
 
 		appSelector := map[string]string{
 			kaitov1alpha1.LabelWorkspaceName: workspace.Name,
@@ -118,6 +550,9 @@ func TestGenerateDeploymentManifestWithPodTemplate(t *testing.T) {
 		if !reflect.DeepEqual(appSelector, obj.Spec.Template.ObjectMeta.Labels) {
 			t.Errorf("template label is wrong")
 		}
+		if obj.Annotations[kaitov1alpha1.WorkspaceRevisionAnnotation] != "1" {
+			t.Errorf("revision annotation is wrong")
+		}
 
 		nodeReq := obj.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions
 
@@ -138,6 +573,52 @@ func kvInNodeRequirement(key, val string, nodeReq []v1.NodeSelectorRequirement)
 	return false
 }
 
+func TestGenerateTuningJobManifest(t *testing.T) {
+	testcases := map[string]struct {
+		capacityType          kaitov1alpha1.CapacityType
+		expectedFailurePolicy bool
+	}{
+		"On-demand nodes": {
+			capacityType: kaitov1alpha1.CapacityTypeOnDemand,
+		},
+		"Spot nodes": {
+			capacityType:          kaitov1alpha1.CapacityTypeSpot,
+			expectedFailurePolicy: true,
+		},
+		"Spot nodes with on-demand fallback": {
+			capacityType:          kaitov1alpha1.CapacityTypeSpotWithFallback,
+			expectedFailurePolicy: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset.DeepCopy()
+			workspace.Resource.CapacityType = tc.capacityType
+
+			obj := GenerateTuningJobManifest(context.TODO(), workspace, "", "test-image", nil, 1, nil, nil, nil, nil,
+				v1.ResourceRequirements{}, nil, nil, nil, nil, nil, nil)
+
+			if *obj.Spec.BackoffLimit != 0 {
+				t.Errorf("backoff limit is wrong, expected 0, got %d", *obj.Spec.BackoffLimit)
+			}
+			if !tc.expectedFailurePolicy {
+				if obj.Spec.PodFailurePolicy != nil {
+					t.Errorf("pod failure policy is not expected, got %v", obj.Spec.PodFailurePolicy)
+				}
+				return
+			}
+			if obj.Spec.PodFailurePolicy == nil || len(obj.Spec.PodFailurePolicy.Rules) != 1 {
+				t.Fatalf("pod failure policy is expected to have 1 rule, got %v", obj.Spec.PodFailurePolicy)
+			}
+			rule := obj.Spec.PodFailurePolicy.Rules[0]
+			if rule.Action != batchv1.PodFailurePolicyActionIgnore || rule.OnPodConditions[0].Type != v1.DisruptionTarget {
+				t.Errorf("disrupted pods are expected to be ignored, got %v", rule)
+			}
+		})
+	}
+}
+
 func TestGenerateServiceManifest(t *testing.T) {
 	options := []bool{true, false}
 
@@ -179,3 +660,32 @@ func TestGenerateHeadlessServiceManifest(t *testing.T) {
 		}
 	})
 }
+
+func TestGenerateActivatorEndpointSliceManifest(t *testing.T) {
+	for _, address := range []string{"10.0.0.100", "fd00::100"} {
+		t.Run(fmt.Sprintf("generate activator endpointslice for %s", address), func(t *testing.T) {
+			workspace := test.MockWorkspaceWithPreset
+			obj := GenerateActivatorEndpointSliceManifest(workspace, address, 8090)
+
+			if obj.Labels[discoveryv1.LabelServiceName] != workspace.Name {
+				t.Errorf("endpointslice service name label is wrong")
+			}
+			if obj.Labels[discoveryv1.LabelManagedBy] != ActivatorEndpointSliceManager {
+				t.Errorf("endpointslice managed-by label is wrong")
+			}
+			expectedAddressType := discoveryv1.AddressTypeIPv4
+			if address == "fd00::100" {
+				expectedAddressType = discoveryv1.AddressTypeIPv6
+			}
+			if obj.AddressType != expectedAddressType {
+				t.Errorf("endpointslice address type is wrong")
+			}
+			if !reflect.DeepEqual(obj.Endpoints[0].Addresses, []string{address}) {
+				t.Errorf("endpointslice addresses are wrong")
+			}
+			if *obj.Ports[0].Name != "http" || *obj.Ports[0].Port != 8090 {
+				t.Errorf("endpointslice port is wrong")
+			}
+		})
+	}
+}
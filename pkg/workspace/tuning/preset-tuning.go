@@ -76,7 +76,7 @@ func GetTuningImageInfo(ctx context.Context, workspaceObj *kaitov1alpha1.Workspa
 		return imageName, imagePullSecretRefs
 	} else {
 		imageName := string(workspaceObj.Tuning.Preset.Name)
-		imageTag := presetObj.Tag
+		imageTag := presetObj.GetImageTag(utils.GetGPUVendor(workspaceObj.Resource.InstanceType))
 		registryName := os.Getenv("PRESET_REGISTRY_NAME")
 		imageName = fmt.Sprintf("%s/kaito-%s:%s", registryName, imageName, imageTag)
 		return imageName, imagePullSecretRefs
@@ -383,7 +383,7 @@ func handleImageDataSource(ctx context.Context, image string) (*corev1.Container
 		Command: []string{"sh", "-c", command},
 		VolumeMounts: []corev1.VolumeMount{
 			{
-				Name:      "data-volume",
+				Name:      utils.DataVolumeName,
 				MountPath: utils.DefaultDataVolumePath,
 			},
 		},
@@ -428,7 +428,7 @@ func handleURLDataSource(ctx context.Context, workspaceObj *kaitov1alpha1.Worksp
 		`},
 		VolumeMounts: []corev1.VolumeMount{
 			{
-				Name:      "data-volume",
+				Name:      utils.DataVolumeName,
 				MountPath: utils.DefaultDataVolumePath,
 			},
 		},
@@ -468,12 +468,13 @@ func prepareTuningParameters(ctx context.Context, wObj *kaitov1alpha1.Workspace,
 	torchCommand := utils.BuildCmdStr(hfParam.BaseCommand, hfParam.TorchRunParams, hfParam.TorchRunRdzvParams)
 	commands := utils.ShellCmd(torchCommand + " " + modelCommand)
 
+	gpuResourceName := corev1.ResourceName(utils.GetGPUVendor(wObj.Resource.InstanceType).ResourceName)
 	resourceRequirements := corev1.ResourceRequirements{
 		Requests: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResourceName: resource.MustParse(skuNumGPUs),
 		},
 		Limits: corev1.ResourceList{
-			corev1.ResourceName(resources.CapacityNvidiaGPU): resource.MustParse(skuNumGPUs),
+			gpuResourceName: resource.MustParse(skuNumGPUs),
 		},
 	}
 
@@ -11,6 +11,7 @@ import (
 	knativeinjection "knative.dev/pkg/injection"
 	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/resourcesemantics"
+	"knative.dev/pkg/webhook/resourcesemantics/defaulting"
 	"knative.dev/pkg/webhook/resourcesemantics/validation"
 
 	kaitov1alpha1 "github.com/kaito-project/kaito/api/v1alpha1"
@@ -19,10 +20,21 @@ import (
 func NewWorkspaceWebhooks() []knativeinjection.ControllerConstructor {
 	return []knativeinjection.ControllerConstructor{
 		certificates.NewController,
+		NewWorkspaceCRDDefaultingWebhook,
 		NewWorkspaceCRDValidationWebhook,
 	}
 }
 
+func NewWorkspaceCRDDefaultingWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return defaulting.NewAdmissionController(ctx,
+		"defaulting.workspace.kaito.sh",
+		"/default/workspace.kaito.sh",
+		WorkspaceResources,
+		func(ctx context.Context) context.Context { return ctx },
+		true,
+	)
+}
+
 func NewWorkspaceCRDValidationWebhook(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	return validation.NewAdmissionController(ctx,
 		"validation.workspace.kaito.sh",
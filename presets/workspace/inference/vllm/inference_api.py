@@ -39,6 +39,7 @@ class KAITOArgumentParser(argparse.ArgumentParser):
         self.add_argument("--kaito-adapters-dir", type=str, default="/mnt/adapter", help="Directory where adapters are stored in KAITO preset.")
         self.add_argument("--kaito-config-file", type=str, default="", help="Additional args for KAITO preset.")
         self.add_argument("--kaito-max-probe-steps", type=int, default=6, help="Maximum number of steps to find the max available seq len fitting in the GPU memory.")
+        self.add_argument("--kaito-max-gpu-memory-utilization", type=float, default=None, help="Upper bound of the GPU memory utilization, used when the GPU is shared with other replicas.")
 
     def _reset_vllm_defaults(self):
         local_rank = int(os.environ.get("LOCAL_RANK",
@@ -78,6 +79,9 @@ class KAITOArgumentParser(argparse.ArgumentParser):
                 runtime_args.append(str(value))
 
         vllm_args = self.vllm_parser.parse_args(runtime_args, **kwargs)
+        # The bound takes precedence over the config file, a time-sliced GPU is shared with the other replicas.
+        if kaito_args.kaito_max_gpu_memory_utilization is not None:
+            vllm_args.gpu_memory_utilization = min(vllm_args.gpu_memory_utilization, kaito_args.kaito_max_gpu_memory_utilization)
         # Merge KAITO and vLLM args
         return argparse.Namespace(**vars(kaito_args), **vars(vllm_args))
 